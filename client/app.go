@@ -129,7 +129,17 @@ func (c *AppClient) ListAll(ctx context.Context, opts *AppListOptions) ([]*resou
 	if opts == nil {
 		opts = NewAppListOptions()
 	}
-	return AutoPage[*AppListOptions, *resource.App](opts, func(opts *AppListOptions) ([]*resource.App, *Pager, error) {
+	return autoPage[*AppListOptions, *resource.App](ctx, c.client, opts, func(ctx context.Context, opts *AppListOptions) ([]*resource.App, *Pager, error) {
+		return c.List(ctx, opts)
+	})
+}
+
+// IterateAll lazily retrieves all apps the user has access to, fetching each page only as it's needed
+func (c *AppClient) IterateAll(ctx context.Context, opts *AppListOptions) *Iterator[*AppListOptions, *resource.App] {
+	if opts == nil {
+		opts = NewAppListOptions()
+	}
+	return Iterate[*AppListOptions, *resource.App](ctx, opts, func(opts *AppListOptions) ([]*resource.App, *Pager, error) {
 		return c.List(ctx, opts)
 	})
 }
@@ -0,0 +1,236 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/sftp"
+)
+
+// AppSFTPClient copies files to and from an app instance over SFTP, for example to collect heap dumps
+// from a misbehaving instance
+type AppSFTPClient struct {
+	sftp *sftp.Client
+	conn *AppSSHConnection // closed with the SFTP client when it was opened by ConnectSFTP
+}
+
+// ConnectSFTP opens an SSH connection to the instance of the app process, see Connect, and starts an
+// SFTP session over it. Closing the returned client also closes the SSH connection
+func (c *AppSSHClient) ConnectSFTP(ctx context.Context, processGUID string, index int) (*AppSFTPClient, error) {
+	conn, err := c.Connect(ctx, processGUID, index)
+	if err != nil {
+		return nil, err
+	}
+	s, err := conn.SFTP()
+	if err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	s.conn = conn
+	return s, nil
+}
+
+// SFTP starts an SFTP session over the SSH connection. Closing the returned client leaves the SSH
+// connection open
+func (c *AppSSHConnection) SFTP() (*AppSFTPClient, error) {
+	s, err := sftp.NewClient(c.client)
+	if err != nil {
+		return nil, fmt.Errorf("error starting SFTP session: %w", err)
+	}
+	return &AppSFTPClient{sftp: s}, nil
+}
+
+// Close ends the SFTP session
+func (s *AppSFTPClient) Close() error {
+	err := s.sftp.Close()
+	if s.conn != nil {
+		if connErr := s.conn.Close(); err == nil {
+			err = connErr
+		}
+	}
+	return err
+}
+
+// List returns the entries in the remote directory
+func (s *AppSFTPClient) List(ctx context.Context, remoteDir string) ([]os.FileInfo, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	entries, err := s.sftp.ReadDir(remoteDir)
+	if err != nil {
+		return nil, fmt.Errorf("error listing remote directory %s: %w", remoteDir, err)
+	}
+	return entries, nil
+}
+
+// Get copies the remote file to the local path, creating or truncating the local file with the same permissions
+func (s *AppSFTPClient) Get(ctx context.Context, remotePath, localPath string) error {
+	remote, err := s.sftp.Open(remotePath)
+	if err != nil {
+		return fmt.Errorf("error opening remote file %s: %w", remotePath, err)
+	}
+	defer func() {
+		_ = remote.Close()
+	}()
+	fi, err := remote.Stat()
+	if err != nil {
+		return fmt.Errorf("error reading remote file %s: %w", remotePath, err)
+	}
+	if fi.IsDir() {
+		return fmt.Errorf("remote path %s is a directory, use GetDir", remotePath)
+	}
+
+	local, err := os.OpenFile(localPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, fi.Mode().Perm())
+	if err != nil {
+		return fmt.Errorf("error creating local file %s: %w", localPath, err)
+	}
+	// WriteTo reads the remote file concurrently, which is much faster for large files like heap dumps
+	_, err = remote.WriteTo(&contextWriter{ctx: ctx, w: local})
+	if closeErr := local.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("error copying remote file %s to %s: %w", remotePath, localPath, err)
+	}
+	return nil
+}
+
+// Put copies the local file to the remote path, creating or truncating the remote file with the same permissions
+func (s *AppSFTPClient) Put(ctx context.Context, localPath, remotePath string) error {
+	local, err := os.Open(localPath)
+	if err != nil {
+		return fmt.Errorf("error opening local file %s: %w", localPath, err)
+	}
+	defer func() {
+		_ = local.Close()
+	}()
+	fi, err := local.Stat()
+	if err != nil {
+		return fmt.Errorf("error reading local file %s: %w", localPath, err)
+	}
+	if fi.IsDir() {
+		return fmt.Errorf("local path %s is a directory, use PutDir", localPath)
+	}
+
+	remote, err := s.sftp.OpenFile(remotePath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC)
+	if err != nil {
+		return fmt.Errorf("error creating remote file %s: %w", remotePath, err)
+	}
+	_, err = remote.ReadFrom(&contextReader{ctx: ctx, r: local})
+	if closeErr := remote.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("error copying %s to remote file %s: %w", localPath, remotePath, err)
+	}
+	if err = s.sftp.Chmod(remotePath, fi.Mode().Perm()); err != nil {
+		return fmt.Errorf("error setting the permissions of remote file %s: %w", remotePath, err)
+	}
+	return nil
+}
+
+// GetDir recursively copies the remote directory to the local directory, creating any missing directories.
+// Symbolic links are skipped
+func (s *AppSFTPClient) GetDir(ctx context.Context, remoteDir, localDir string) error {
+	walker := s.sftp.Walk(remoteDir)
+	for walker.Step() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := walker.Err(); err != nil {
+			return fmt.Errorf("error reading remote directory %s: %w", walker.Path(), err)
+		}
+		rel, err := relativeRemotePath(remoteDir, walker.Path())
+		if err != nil {
+			return err
+		}
+		localPath := filepath.Join(localDir, filepath.FromSlash(rel))
+		fi := walker.Stat()
+		switch {
+		case fi.IsDir():
+			if err = os.MkdirAll(localPath, fi.Mode().Perm()|0o700); err != nil {
+				return fmt.Errorf("error creating local directory %s: %w", localPath, err)
+			}
+		case fi.Mode().IsRegular():
+			if err = s.Get(ctx, walker.Path(), localPath); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// PutDir recursively copies the local directory to the remote directory, creating any missing directories.
+// Symbolic links are skipped
+func (s *AppSFTPClient) PutDir(ctx context.Context, localDir, remoteDir string) error {
+	return filepath.WalkDir(localDir, func(localPath string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return fmt.Errorf("error reading local directory %s: %w", localPath, err)
+		}
+		if err = ctx.Err(); err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(localDir, localPath)
+		if err != nil {
+			return err
+		}
+		remotePath := path.Join(remoteDir, filepath.ToSlash(rel))
+		switch {
+		case d.IsDir():
+			if err = s.sftp.MkdirAll(remotePath); err != nil {
+				return fmt.Errorf("error creating remote directory %s: %w", remotePath, err)
+			}
+		case d.Type().IsRegular():
+			return s.Put(ctx, localPath, remotePath)
+		}
+		return nil
+	})
+}
+
+// relativeRemotePath returns the remote path relative to the remote directory
+func relativeRemotePath(dir, p string) (string, error) {
+	dir, p = path.Clean(dir), path.Clean(p)
+	if p == dir {
+		return ".", nil
+	}
+	prefix := dir
+	if prefix != "/" {
+		prefix += "/"
+	}
+	if !strings.HasPrefix(p, prefix) {
+		return "", fmt.Errorf("remote path %s is not in directory %s", p, dir)
+	}
+	return strings.TrimPrefix(p, prefix), nil
+}
+
+// contextReader stops reading once the context is done
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (r *contextReader) Read(p []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return r.r.Read(p)
+}
+
+// contextWriter stops writing once the context is done
+type contextWriter struct {
+	ctx context.Context
+	w   io.Writer
+}
+
+func (w *contextWriter) Write(p []byte) (int, error) {
+	if err := w.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return w.w.Write(p)
+}
@@ -0,0 +1,116 @@
+package client
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/pkg/sftp"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+)
+
+// newFakeSFTPProxy serves SFTP from the local filesystem over the fake SSH proxy
+func newFakeSFTPProxy(t *testing.T) *fakeSSHProxy {
+	p := newFakeSSHProxy(t)
+	p.subsystems["sftp"] = func(ch ssh.Channel) {
+		server, err := sftp.NewServer(ch)
+		if err != nil {
+			return
+		}
+		_ = server.Serve()
+	}
+	return p
+}
+
+func writeFile(t *testing.T, name, content string, perm os.FileMode) {
+	require.NoError(t, os.MkdirAll(filepath.Dir(name), 0o755))
+	require.NoError(t, os.WriteFile(name, []byte(content), perm))
+}
+
+func TestAppSFTP(t *testing.T) {
+	conn := connectToFakeSSHProxy(t, newFakeSFTPProxy(t))
+	s, err := conn.SFTP()
+	require.NoError(t, err)
+	defer s.Close()
+
+	ctx := context.Background()
+	remote := t.TempDir()
+	local := t.TempDir()
+
+	t.Run("get and put files", func(t *testing.T) {
+		writeFile(t, filepath.Join(remote, "heap.hprof"), "heap dump", 0o600)
+		require.NoError(t, s.Get(ctx, filepath.Join(remote, "heap.hprof"), filepath.Join(local, "heap.hprof")))
+		b, err := os.ReadFile(filepath.Join(local, "heap.hprof"))
+		require.NoError(t, err)
+		require.Equal(t, "heap dump", string(b))
+
+		writeFile(t, filepath.Join(local, "agent.jar"), "agent", 0o640)
+		require.NoError(t, s.Put(ctx, filepath.Join(local, "agent.jar"), filepath.Join(remote, "agent.jar")))
+		fi, err := os.Stat(filepath.Join(remote, "agent.jar"))
+		require.NoError(t, err)
+		require.Equal(t, os.FileMode(0o640), fi.Mode().Perm())
+
+		err = s.Get(ctx, remote, filepath.Join(local, "dir"))
+		require.ErrorContains(t, err, "use GetDir")
+	})
+
+	t.Run("list a directory", func(t *testing.T) {
+		dir := filepath.Join(remote, "dumps")
+		writeFile(t, filepath.Join(dir, "thread-1.txt"), "threads", 0o644)
+		writeFile(t, filepath.Join(dir, "thread-2.txt"), "threads", 0o644)
+		require.NoError(t, os.Mkdir(filepath.Join(dir, "old"), 0o755))
+
+		entries, err := s.List(ctx, dir)
+		require.NoError(t, err)
+		var names []string
+		for _, e := range entries {
+			names = append(names, e.Name())
+		}
+		sort.Strings(names)
+		require.Equal(t, []string{"old", "thread-1.txt", "thread-2.txt"}, names)
+	})
+
+	t.Run("copy directories recursively", func(t *testing.T) {
+		src := filepath.Join(remote, "logs")
+		writeFile(t, filepath.Join(src, "app.log"), "app", 0o644)
+		writeFile(t, filepath.Join(src, "gc", "gc.log"), "gc", 0o644)
+
+		dst := filepath.Join(local, "logs")
+		require.NoError(t, s.GetDir(ctx, src, dst))
+		b, err := os.ReadFile(filepath.Join(dst, "gc", "gc.log"))
+		require.NoError(t, err)
+		require.Equal(t, "gc", string(b))
+
+		back := filepath.Join(remote, "logs-copy")
+		require.NoError(t, s.PutDir(ctx, dst, back))
+		b, err = os.ReadFile(filepath.Join(back, "app.log"))
+		require.NoError(t, err)
+		require.Equal(t, "app", string(b))
+		b, err = os.ReadFile(filepath.Join(back, "gc", "gc.log"))
+		require.NoError(t, err)
+		require.Equal(t, "gc", string(b))
+	})
+
+	t.Run("stops when the context is cancelled", func(t *testing.T) {
+		cancelled, cancel := context.WithCancel(ctx)
+		cancel()
+		err := s.GetDir(cancelled, filepath.Join(remote, "logs"), filepath.Join(local, "cancelled"))
+		require.ErrorIs(t, err, context.Canceled)
+	})
+}
+
+func TestRelativeRemotePath(t *testing.T) {
+	rel, err := relativeRemotePath("/home/vcap/app/", "/home/vcap/app/logs/gc.log")
+	require.NoError(t, err)
+	require.Equal(t, "logs/gc.log", rel)
+
+	rel, err = relativeRemotePath("/", "/tmp")
+	require.NoError(t, err)
+	require.Equal(t, "tmp", rel)
+
+	_, err = relativeRemotePath("/home/vcap/app", "/home/vcap/application")
+	require.Error(t, err)
+}
@@ -0,0 +1,418 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+)
+
+const (
+	// DefaultAppSSHKeepAliveInterval is how often a keep alive is sent so the SSH proxy doesn't drop idle connections
+	DefaultAppSSHKeepAliveInterval = 30 * time.Second
+
+	// the lengths of the host key fingerprint formats the SSH proxy may publish
+	sha256FingerprintLength = 43 // base64 without padding
+	sha1FingerprintLength   = 59 // colon separated hex
+	md5FingerprintLength    = 47 // colon separated hex
+)
+
+// AppSSHClient opens SSH connections to app process instances through the SSH proxy linked from the API root,
+// the same as cf ssh
+type AppSSHClient commonClient
+
+// AppSSHConnection is an SSH connection to a single app process instance. Commands, terminals and port
+// forwards can be used concurrently over the same connection
+type AppSSHConnection struct {
+	client *ssh.Client
+	done   chan struct{}
+	once   sync.Once
+}
+
+// AppSSHCommandResult is the output and exit code of a command run in an app instance
+type AppSSHCommandResult struct {
+	Stdout   []byte
+	Stderr   []byte
+	ExitCode int
+}
+
+// AppSSHTerminalOptions configures an interactive terminal session
+type AppSSHTerminalOptions struct {
+	Term    string // the terminal type, defaults to xterm
+	Width   int    // the terminal width in columns, defaults to 80
+	Height  int    // the terminal height in rows, defaults to 24
+	Command string // the command to run, runs a login shell if empty
+
+	Stdin  io.Reader
+	Stdout io.Writer
+	Stderr io.Writer
+}
+
+// NewAppSSHTerminalOptions creates new options to start a terminal session that reads from stdin and
+// writes to stdout. Put the local terminal in raw mode before starting the session so key presses
+// are sent as they're typed
+func NewAppSSHTerminalOptions(stdin io.Reader, stdout, stderr io.Writer) *AppSSHTerminalOptions {
+	return &AppSSHTerminalOptions{
+		Term:   "xterm",
+		Width:  80,
+		Height: 24,
+		Stdin:  stdin,
+		Stdout: stdout,
+		Stderr: stderr,
+	}
+}
+
+// AppSSHTerminal is a running interactive terminal session
+type AppSSHTerminal struct {
+	session *ssh.Session
+}
+
+// AppSSHPortForward forwards connections to a local port through the SSH connection to an address
+// reachable from the app instance
+type AppSSHPortForward struct {
+	client     *ssh.Client
+	listener   net.Listener
+	remoteAddr string
+	done       chan struct{}
+	once       sync.Once
+}
+
+// Connect opens an SSH connection to the instance of the app process, authenticating to the SSH proxy as
+// cf:<process-guid>/<index> with a one-time code from UAA. The proxy's host key is verified against the
+// fingerprint published in the API root. The web process of an app has the same GUID as the app.
+//
+// It is the caller's responsibility to close the connection
+func (c *AppSSHClient) Connect(ctx context.Context, processGUID string, index int) (*AppSSHConnection, error) {
+	root, err := c.client.Root.Get(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error discovering the SSH proxy: %w", err)
+	}
+	endpoint := root.Links.AppSSH.Href
+	if endpoint == "" {
+		return nil, errors.New("the CF API root does not link to an SSH proxy")
+	}
+	fingerprint := root.Links.AppSSH.Meta.HostKeyFingerprint
+	if fingerprint == "" {
+		return nil, errors.New("the CF API root does not include the SSH proxy host key fingerprint")
+	}
+
+	code, err := c.client.SSHCode(ctx)
+	if err != nil {
+		return nil, err
+	}
+	config := &ssh.ClientConfig{
+		User:            fmt.Sprintf("cf:%s/%d", processGUID, index),
+		Auth:            []ssh.AuthMethod{ssh.Password(code)},
+		HostKeyCallback: hostKeyFingerprintCallback(fingerprint),
+	}
+
+	var d net.Dialer
+	netConn, err := d.DialContext(ctx, "tcp", endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("error connecting to the SSH proxy %s: %w", endpoint, err)
+	}
+	sshClient, err := sshHandshake(ctx, netConn, endpoint, config)
+	if err != nil {
+		_ = netConn.Close()
+		return nil, fmt.Errorf("error opening an SSH connection to instance %d of process %s: %w", index, processGUID, err)
+	}
+
+	conn := &AppSSHConnection{
+		client: sshClient,
+		done:   make(chan struct{}),
+	}
+	go conn.keepAlive(DefaultAppSSHKeepAliveInterval)
+	return conn, nil
+}
+
+// sshHandshake authenticates the connection, aborting the handshake if the context is done first
+func sshHandshake(ctx context.Context, conn net.Conn, addr string, config *ssh.ClientConfig) (*ssh.Client, error) {
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		select {
+		case <-ctx.Done():
+			_ = conn.SetDeadline(time.Now())
+		case <-stop:
+		}
+	}()
+	sshConn, chans, reqs, err := ssh.NewClientConn(conn, addr, config)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, err
+	}
+	return ssh.NewClient(sshConn, chans, reqs), nil
+}
+
+// hostKeyFingerprintCallback verifies the host key matches the fingerprint, which may be a base64 sha256
+// fingerprint or a colon separated hex sha1 or md5 fingerprint
+func hostKeyFingerprintCallback(expected string) ssh.HostKeyCallback {
+	expected = strings.TrimPrefix(expected, "SHA256:")
+	return func(hostname string, remote net.Addr, key ssh.PublicKey) error {
+		var actual string
+		switch len(expected) {
+		case sha256FingerprintLength:
+			sum := sha256.Sum256(key.Marshal())
+			actual = base64.RawStdEncoding.EncodeToString(sum[:])
+		case sha1FingerprintLength:
+			sum := sha1.Sum(key.Marshal())
+			actual = hexFingerprint(sum[:])
+		case md5FingerprintLength:
+			sum := md5.Sum(key.Marshal())
+			actual = hexFingerprint(sum[:])
+		default:
+			return fmt.Errorf("unsupported SSH proxy host key fingerprint format %q", expected)
+		}
+		if subtle.ConstantTimeCompare([]byte(strings.ToLower(actual)), []byte(strings.ToLower(expected))) != 1 {
+			return fmt.Errorf("the SSH proxy host key fingerprint %s does not match the expected fingerprint %s", actual, expected)
+		}
+		return nil
+	}
+}
+
+func hexFingerprint(sum []byte) string {
+	parts := make([]string, len(sum))
+	for i, b := range sum {
+		parts[i] = fmt.Sprintf("%02x", b)
+	}
+	return strings.Join(parts, ":")
+}
+
+// keepAlive periodically sends a request so the SSH proxy doesn't close an idle connection
+func (c *AppSSHConnection) keepAlive(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if _, _, err := c.client.SendRequest("keepalive@cloudfoundry.org", true, nil); err != nil {
+				return
+			}
+		case <-c.done:
+			return
+		}
+	}
+}
+
+// Close closes the connection along with any commands, terminals and port forwards using it
+func (c *AppSSHConnection) Close() error {
+	c.once.Do(func() {
+		close(c.done)
+	})
+	return c.client.Close()
+}
+
+// Run runs the command in the app instance and returns its output and exit code. A command that exits
+// with a non-zero exit code isn't an error
+func (c *AppSSHConnection) Run(ctx context.Context, command string) (*AppSSHCommandResult, error) {
+	var stdout, stderr bytes.Buffer
+	exitCode, err := c.Stream(ctx, command, nil, &stdout, &stderr)
+	if err != nil {
+		return nil, err
+	}
+	return &AppSSHCommandResult{
+		Stdout:   stdout.Bytes(),
+		Stderr:   stderr.Bytes(),
+		ExitCode: exitCode,
+	}, nil
+}
+
+// Stream runs the command in the app instance, streaming stdin to it and its output to stdout and stderr,
+// and returns its exit code. Any of stdin, stdout and stderr may be nil. If the context is cancelled the
+// command is killed
+func (c *AppSSHConnection) Stream(ctx context.Context, command string, stdin io.Reader, stdout, stderr io.Writer) (int, error) {
+	session, err := c.client.NewSession()
+	if err != nil {
+		return -1, fmt.Errorf("error opening SSH session: %w", err)
+	}
+	defer func() {
+		_ = session.Close()
+	}()
+	session.Stdin = stdin
+	session.Stdout = stdout
+	session.Stderr = stderr
+	if err = session.Start(command); err != nil {
+		return -1, fmt.Errorf("error running command: %w", err)
+	}
+	return waitForExit(ctx, session)
+}
+
+// StartTerminal starts an interactive terminal session in the app instance, like cf ssh without a command.
+// Call Wait to wait for the session to exit
+func (c *AppSSHConnection) StartTerminal(opts *AppSSHTerminalOptions) (*AppSSHTerminal, error) {
+	if opts == nil {
+		return nil, errors.New("terminal options are required")
+	}
+	session, err := c.client.NewSession()
+	if err != nil {
+		return nil, fmt.Errorf("error opening SSH session: %w", err)
+	}
+	session.Stdin = opts.Stdin
+	session.Stdout = opts.Stdout
+	session.Stderr = opts.Stderr
+
+	term, width, height := opts.Term, opts.Width, opts.Height
+	if term == "" {
+		term = "xterm"
+	}
+	if width < 1 {
+		width = 80
+	}
+	if height < 1 {
+		height = 24
+	}
+	modes := ssh.TerminalModes{
+		ssh.ECHO:          1,
+		ssh.TTY_OP_ISPEED: 115200,
+		ssh.TTY_OP_OSPEED: 115200,
+	}
+	if err = session.RequestPty(term, height, width, modes); err != nil {
+		_ = session.Close()
+		return nil, fmt.Errorf("error requesting a terminal: %w", err)
+	}
+	if opts.Command == "" {
+		err = session.Shell()
+	} else {
+		err = session.Start(opts.Command)
+	}
+	if err != nil {
+		_ = session.Close()
+		return nil, fmt.Errorf("error starting terminal session: %w", err)
+	}
+	return &AppSSHTerminal{session: session}, nil
+}
+
+// Resize tells the app instance the local terminal has been resized
+func (t *AppSSHTerminal) Resize(width, height int) error {
+	return t.session.WindowChange(height, width)
+}
+
+// Wait waits for the terminal session to exit and returns its exit code. If the context is cancelled the
+// session is killed
+func (t *AppSSHTerminal) Wait(ctx context.Context) (int, error) {
+	defer func() {
+		_ = t.session.Close()
+	}()
+	return waitForExit(ctx, t.session)
+}
+
+// Close ends the terminal session
+func (t *AppSSHTerminal) Close() error {
+	return t.session.Close()
+}
+
+// waitForExit waits for the session's command to exit and returns its exit code, killing it if the context
+// is cancelled first
+func waitForExit(ctx context.Context, session *ssh.Session) (int, error) {
+	done := make(chan error, 1)
+	go func() {
+		done <- session.Wait()
+	}()
+	select {
+	case err := <-done:
+		if err == nil {
+			return 0, nil
+		}
+		var exitErr *ssh.ExitError
+		if errors.As(err, &exitErr) {
+			return exitErr.ExitStatus(), nil
+		}
+		return -1, fmt.Errorf("error waiting for command to exit: %w", err)
+	case <-ctx.Done():
+		_ = session.Signal(ssh.SIGKILL)
+		_ = session.Close()
+		return -1, ctx.Err()
+	}
+}
+
+// ForwardLocalPort listens on the local address, like 127.0.0.1:9000 or 127.0.0.1:0 to pick a free port, and
+// forwards each connection to the remote address as seen from the app instance, like localhost:8080. Forwarding
+// stops when the context is cancelled or the port forward or connection is closed
+func (c *AppSSHConnection) ForwardLocalPort(ctx context.Context, localAddr, remoteAddr string) (*AppSSHPortForward, error) {
+	var lc net.ListenConfig
+	listener, err := lc.Listen(ctx, "tcp", localAddr)
+	if err != nil {
+		return nil, fmt.Errorf("error listening on %s: %w", localAddr, err)
+	}
+	f := &AppSSHPortForward{
+		client:     c.client,
+		listener:   listener,
+		remoteAddr: remoteAddr,
+		done:       make(chan struct{}),
+	}
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-c.done:
+		case <-f.done:
+		}
+		_ = f.Close()
+	}()
+	go f.serve()
+	return f, nil
+}
+
+// Addr returns the local address connections are forwarded from
+func (f *AppSSHPortForward) Addr() net.Addr {
+	return f.listener.Addr()
+}
+
+// Close stops accepting new connections, connections already forwarded are left open until either side closes
+func (f *AppSSHPortForward) Close() error {
+	var err error
+	f.once.Do(func() {
+		close(f.done)
+		err = f.listener.Close()
+	})
+	return err
+}
+
+func (f *AppSSHPortForward) serve() {
+	for {
+		local, err := f.listener.Accept()
+		if err != nil {
+			return
+		}
+		go f.forward(local)
+	}
+}
+
+// forward copies data both ways between the local connection and the remote address, the local
+// connection is closed if the app instance can't connect to the remote address
+func (f *AppSSHPortForward) forward(local net.Conn) {
+	defer func() {
+		_ = local.Close()
+	}()
+	remote, err := f.client.Dial("tcp", f.remoteAddr)
+	if err != nil {
+		return
+	}
+	defer func() {
+		_ = remote.Close()
+	}()
+
+	copied := make(chan struct{}, 2)
+	go func() {
+		_, _ = io.Copy(remote, local)
+		copied <- struct{}{}
+	}()
+	go func() {
+		_, _ = io.Copy(local, remote)
+		copied <- struct{}{}
+	}()
+	<-copied
+}
@@ -0,0 +1,364 @@
+package client
+
+import (
+	"bufio"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cloudfoundry/go-cfclient/v3/config"
+	"github.com/cloudfoundry/go-cfclient/v3/testutil"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+)
+
+const (
+	sshTestProcessGUID = "7b34f1cf-7e73-428a-bb5a-8a17a8058396"
+	sshTestCode        = "abc123"
+)
+
+// fakeSSHProxy is an SSH server that authenticates like the CF SSH proxy and runs a few fake commands
+type fakeSSHProxy struct {
+	listener    net.Listener
+	fingerprint string
+	subsystems  map[string]func(ch ssh.Channel)
+
+	mu      sync.Mutex
+	ptyTerm string
+	resized string
+}
+
+func newFakeSSHProxy(t *testing.T) *fakeSSHProxy {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	signer, err := ssh.NewSignerFromKey(key)
+	require.NoError(t, err)
+	sum := sha256.Sum256(signer.PublicKey().Marshal())
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = listener.Close()
+	})
+
+	p := &fakeSSHProxy{
+		listener:    listener,
+		fingerprint: base64.RawStdEncoding.EncodeToString(sum[:]),
+		subsystems:  map[string]func(ch ssh.Channel){},
+	}
+	serverConfig := &ssh.ServerConfig{
+		PasswordCallback: func(conn ssh.ConnMetadata, password []byte) (*ssh.Permissions, error) {
+			if conn.User() != fmt.Sprintf("cf:%s/0", sshTestProcessGUID) || string(password) != sshTestCode {
+				return nil, fmt.Errorf("invalid credentials for %s", conn.User())
+			}
+			return nil, nil
+		},
+	}
+	serverConfig.AddHostKey(signer)
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go p.serve(conn, serverConfig)
+		}
+	}()
+	return p
+}
+
+// setup links the fake API root to the proxy and serves the one-time code from the fake UAA
+func (p *fakeSSHProxy) setup(t *testing.T) string {
+	serverURL := testutil.Setup(testutil.MockRoute{
+		Method:           "GET",
+		Endpoint:         "/oauth/authorize",
+		Status:           http.StatusFound,
+		RedirectLocation: "https://uaa.example.org/login?code=" + sshTestCode,
+	}, t)
+	testutil.SetAppSSHProxy(p.listener.Addr().String(), p.fingerprint)
+	return serverURL
+}
+
+func (p *fakeSSHProxy) serve(conn net.Conn, serverConfig *ssh.ServerConfig) {
+	_, chans, reqs, err := ssh.NewServerConn(conn, serverConfig)
+	if err != nil {
+		return
+	}
+	go ssh.DiscardRequests(reqs)
+	for newCh := range chans {
+		switch newCh.ChannelType() {
+		case "session":
+			ch, chReqs, err := newCh.Accept()
+			if err != nil {
+				continue
+			}
+			go p.session(ch, chReqs)
+		case "direct-tcpip":
+			go p.directTCPIP(newCh)
+		default:
+			_ = newCh.Reject(ssh.UnknownChannelType, "unsupported channel type")
+		}
+	}
+}
+
+func (p *fakeSSHProxy) session(ch ssh.Channel, reqs <-chan *ssh.Request) {
+	exit := func(code uint32) {
+		_, _ = ch.SendRequest("exit-status", false, ssh.Marshal(struct{ Status uint32 }{code}))
+		_ = ch.Close()
+	}
+	for req := range reqs {
+		switch req.Type {
+		case "pty-req":
+			var pty struct {
+				Term          string
+				Columns, Rows uint32
+				Width, Height uint32
+				Modes         string
+			}
+			_ = ssh.Unmarshal(req.Payload, &pty)
+			p.mu.Lock()
+			p.ptyTerm = fmt.Sprintf("%s %dx%d", pty.Term, pty.Columns, pty.Rows)
+			p.mu.Unlock()
+			_ = req.Reply(true, nil)
+		case "window-change":
+			var size struct{ Columns, Rows, Width, Height uint32 }
+			_ = ssh.Unmarshal(req.Payload, &size)
+			p.mu.Lock()
+			p.resized = fmt.Sprintf("%dx%d", size.Columns, size.Rows)
+			p.mu.Unlock()
+		case "shell":
+			_ = req.Reply(true, nil)
+			go func() {
+				// echo each line until stdin is closed
+				scanner := bufio.NewScanner(ch)
+				for scanner.Scan() {
+					_, _ = fmt.Fprintf(ch, "$ %s\n", scanner.Text())
+				}
+				exit(0)
+			}()
+		case "exec":
+			var cmd struct{ Command string }
+			_ = ssh.Unmarshal(req.Payload, &cmd)
+			_ = req.Reply(true, nil)
+			go func() {
+				switch {
+				case strings.HasPrefix(cmd.Command, "echo "):
+					_, _ = fmt.Fprintln(ch, strings.TrimPrefix(cmd.Command, "echo "))
+					exit(0)
+				case cmd.Command == "cat":
+					_, _ = io.Copy(ch, ch)
+					exit(0)
+				case cmd.Command == "sleep":
+					// runs until killed
+				default:
+					_, _ = fmt.Fprintf(ch.Stderr(), "%s: command not found\n", cmd.Command)
+					exit(127)
+				}
+			}()
+		case "subsystem":
+			var subsystem struct{ Name string }
+			_ = ssh.Unmarshal(req.Payload, &subsystem)
+			handler, ok := p.subsystems[subsystem.Name]
+			_ = req.Reply(ok, nil)
+			if ok {
+				go func() {
+					handler(ch)
+					exit(0)
+				}()
+			}
+		case "signal":
+			exit(137)
+		default:
+			if req.WantReply {
+				_ = req.Reply(false, nil)
+			}
+		}
+	}
+}
+
+func (p *fakeSSHProxy) directTCPIP(newCh ssh.NewChannel) {
+	var target struct {
+		Host       string
+		Port       uint32
+		OriginHost string
+		OriginPort uint32
+	}
+	if err := ssh.Unmarshal(newCh.ExtraData(), &target); err != nil {
+		_ = newCh.Reject(ssh.ConnectionFailed, err.Error())
+		return
+	}
+	remote, err := net.Dial("tcp", net.JoinHostPort(target.Host, fmt.Sprint(target.Port)))
+	if err != nil {
+		_ = newCh.Reject(ssh.ConnectionFailed, err.Error())
+		return
+	}
+	ch, reqs, err := newCh.Accept()
+	if err != nil {
+		_ = remote.Close()
+		return
+	}
+	go ssh.DiscardRequests(reqs)
+	go func() {
+		_, _ = io.Copy(ch, remote)
+		_ = ch.Close()
+	}()
+	_, _ = io.Copy(remote, ch)
+	_ = remote.Close()
+}
+
+func connectToFakeSSHProxy(t *testing.T, p *fakeSSHProxy) *AppSSHConnection {
+	serverURL := p.setup(t)
+	t.Cleanup(testutil.Teardown)
+	c, _ := config.New(serverURL, config.Token("", "fake-refresh-token"))
+	cf, err := New(c)
+	require.NoError(t, err)
+
+	conn, err := cf.AppSSH.Connect(context.Background(), sshTestProcessGUID, 0)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = conn.Close()
+	})
+	return conn
+}
+
+func TestAppSSHRun(t *testing.T) {
+	conn := connectToFakeSSHProxy(t, newFakeSSHProxy(t))
+
+	result, err := conn.Run(context.Background(), "echo hello")
+	require.NoError(t, err)
+	require.Equal(t, "hello\n", string(result.Stdout))
+	require.Equal(t, 0, result.ExitCode)
+
+	result, err = conn.Run(context.Background(), "jstack")
+	require.NoError(t, err)
+	require.Equal(t, "jstack: command not found\n", string(result.Stderr))
+	require.Equal(t, 127, result.ExitCode)
+
+	var stdout strings.Builder
+	exitCode, err := conn.Stream(context.Background(), "cat", strings.NewReader("piped input"), &stdout, nil)
+	require.NoError(t, err)
+	require.Equal(t, "piped input", stdout.String())
+	require.Equal(t, 0, exitCode)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err = conn.Stream(ctx, "sleep", nil, nil, nil)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestAppSSHTerminal(t *testing.T) {
+	p := newFakeSSHProxy(t)
+	conn := connectToFakeSSHProxy(t, p)
+
+	stdin, stdinWriter := io.Pipe()
+	var stdout strings.Builder
+	opts := NewAppSSHTerminalOptions(stdin, &stdout, io.Discard)
+	opts.Width = 100
+	opts.Height = 30
+	term, err := conn.StartTerminal(opts)
+	require.NoError(t, err)
+
+	_, err = stdinWriter.Write([]byte("ls\n"))
+	require.NoError(t, err)
+	require.NoError(t, term.Resize(120, 40))
+	require.Eventually(t, func() bool {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		return p.resized == "120x40"
+	}, time.Second, time.Millisecond)
+	require.NoError(t, stdinWriter.Close())
+
+	exitCode, err := term.Wait(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 0, exitCode)
+	require.Equal(t, "$ ls\n", stdout.String())
+	require.Equal(t, "xterm 100x30", p.ptyTerm)
+}
+
+func TestAppSSHForwardLocalPort(t *testing.T) {
+	conn := connectToFakeSSHProxy(t, newFakeSSHProxy(t))
+
+	// a service only reachable from the app instance, which replies with what it receives in upper case
+	service, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer service.Close()
+	go func() {
+		for {
+			c, err := service.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer c.Close()
+				line, _ := bufio.NewReader(c).ReadString('\n')
+				_, _ = c.Write([]byte(strings.ToUpper(line)))
+			}()
+		}
+	}()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	forward, err := conn.ForwardLocalPort(ctx, "127.0.0.1:0", service.Addr().String())
+	require.NoError(t, err)
+
+	local, err := net.Dial("tcp", forward.Addr().String())
+	require.NoError(t, err)
+	defer local.Close()
+	_, err = local.Write([]byte("heap dump\n"))
+	require.NoError(t, err)
+	reply, err := bufio.NewReader(local).ReadString('\n')
+	require.NoError(t, err)
+	require.Equal(t, "HEAP DUMP\n", reply)
+
+	// cancelling the context stops accepting connections
+	cancel()
+	require.Eventually(t, func() bool {
+		c, err := net.Dial("tcp", forward.Addr().String())
+		if err == nil {
+			_ = c.Close()
+		}
+		return err != nil
+	}, time.Second, time.Millisecond)
+}
+
+func TestAppSSHHostKeyFingerprint(t *testing.T) {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	signer, err := ssh.NewSignerFromKey(key)
+	require.NoError(t, err)
+	pub := signer.PublicKey()
+
+	sha256Sum := sha256.Sum256(pub.Marshal())
+	require.NoError(t, hostKeyFingerprintCallback(base64.RawStdEncoding.EncodeToString(sha256Sum[:]))("", nil, pub))
+	require.NoError(t, hostKeyFingerprintCallback(ssh.FingerprintSHA256(pub))("", nil, pub))
+	require.NoError(t, hostKeyFingerprintCallback(ssh.FingerprintLegacyMD5(pub))("", nil, pub))
+
+	err = hostKeyFingerprintCallback("a6:d1:08:0b:b0:cb:9b:5f:c4:ba:44:2a:97:26:19:8a")("", nil, pub)
+	require.ErrorContains(t, err, "does not match")
+	err = hostKeyFingerprintCallback("not-a-fingerprint")("", nil, pub)
+	require.ErrorContains(t, err, "unsupported")
+}
+
+func TestAppSSHConnectRejectsWrongHostKey(t *testing.T) {
+	p := newFakeSSHProxy(t)
+	p.fingerprint = "Y411oivJwZCUQnXHq83mdM5SKCK4ftyoSXI31RRe4Zs"
+	serverURL := p.setup(t)
+	defer testutil.Teardown()
+	c, _ := config.New(serverURL, config.Token("", "fake-refresh-token"))
+	cf, err := New(c)
+	require.NoError(t, err)
+
+	_, err = cf.AppSSH.Connect(context.Background(), sshTestProcessGUID, 0)
+	require.ErrorContains(t, err, "host key fingerprint")
+}
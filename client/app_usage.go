@@ -58,7 +58,17 @@ func (c *AppUsageClient) ListAll(ctx context.Context, opts *AppUsageListOptions)
 	if opts == nil {
 		opts = NewAppUsageOptions()
 	}
-	return AutoPage[*AppUsageListOptions, *resource.AppUsage](opts, func(opts *AppUsageListOptions) ([]*resource.AppUsage, *Pager, error) {
+	return autoPage[*AppUsageListOptions, *resource.AppUsage](ctx, c.client, opts, func(ctx context.Context, opts *AppUsageListOptions) ([]*resource.AppUsage, *Pager, error) {
+		return c.List(ctx, opts)
+	})
+}
+
+// IterateAll lazily retrieves all app usage events, fetching each page only as it's needed
+func (c *AppUsageClient) IterateAll(ctx context.Context, opts *AppUsageListOptions) *Iterator[*AppUsageListOptions, *resource.AppUsage] {
+	if opts == nil {
+		opts = NewAppUsageOptions()
+	}
+	return Iterate[*AppUsageListOptions, *resource.AppUsage](ctx, opts, func(opts *AppUsageListOptions) ([]*resource.AppUsage, *Pager, error) {
 		return c.List(ctx, opts)
 	})
 }
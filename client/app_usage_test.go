@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"testing"
 
+	"github.com/cloudfoundry/go-cfclient/v3/resource"
 	"github.com/cloudfoundry/go-cfclient/v3/testutil"
 )
 
@@ -39,6 +40,23 @@ func TestAppUsages(t *testing.T) {
 				return c.AppUsageEvents.ListAll(context.Background(), nil)
 			},
 		},
+		{
+			Description: "Iterate all app usage events",
+			Route: testutil.MockRoute{
+				Method:   "GET",
+				Endpoint: "/v3/app_usage_events",
+				Output:   g.Paged([]string{appUsage, appUsage2}, []string{appUsage3}),
+				Status:   http.StatusOK},
+			Expected: g.Array(appUsage, appUsage2, appUsage3),
+			Action: func(c *Client, t *testing.T) (any, error) {
+				var all []*resource.AppUsage
+				it := c.AppUsageEvents.IterateAll(context.Background(), nil)
+				for it.Next() {
+					all = append(all, it.Value())
+				}
+				return all, it.Err()
+			},
+		},
 		{
 			Description: "Purge all app usage events",
 			Route: testutil.MockRoute{
@@ -67,20 +67,19 @@ func (c *AuditEventClient) ListAll(ctx context.Context, opts *AuditEventListOpti
 	if opts == nil {
 		opts = NewAuditEventListOptions()
 	}
+	return autoPage[*AuditEventListOptions, *resource.AuditEvent](ctx, c.client, opts, func(ctx context.Context, opts *AuditEventListOptions) ([]*resource.AuditEvent, *Pager, error) {
+		return c.List(ctx, opts)
+	})
+}
 
-	var all []*resource.AuditEvent
-	for {
-		page, pager, err := c.List(ctx, opts)
-		if err != nil {
-			return nil, err
-		}
-		all = append(all, page...)
-		if !pager.HasNextPage() {
-			break
-		}
-		pager.NextPage(opts)
+// IterateAll lazily retrieves all audit events the user has access to, fetching each page only as it's needed
+func (c *AuditEventClient) IterateAll(ctx context.Context, opts *AuditEventListOptions) *Iterator[*AuditEventListOptions, *resource.AuditEvent] {
+	if opts == nil {
+		opts = NewAuditEventListOptions()
 	}
-	return all, nil
+	return Iterate[*AuditEventListOptions, *resource.AuditEvent](ctx, opts, func(opts *AuditEventListOptions) ([]*resource.AuditEvent, *Pager, error) {
+		return c.List(ctx, opts)
+	})
 }
 
 // Single returns a single audit event matching the options or an error if not exactly 1 match
@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"testing"
 
+	"github.com/cloudfoundry/go-cfclient/v3/resource"
 	"github.com/cloudfoundry/go-cfclient/v3/testutil"
 )
 
@@ -39,6 +40,23 @@ func TestAuditEvents(t *testing.T) {
 				return c.AuditEvents.ListAll(context.Background(), nil)
 			},
 		},
+		{
+			Description: "Iterate all audit events",
+			Route: testutil.MockRoute{
+				Method:   "GET",
+				Endpoint: "/v3/audit_events",
+				Output:   g.Paged([]string{auditEvent, auditEvent2}, []string{auditEvent3}),
+				Status:   http.StatusOK},
+			Expected: g.Array(auditEvent, auditEvent2, auditEvent3),
+			Action: func(c *Client, t *testing.T) (any, error) {
+				var all []*resource.AuditEvent
+				it := c.AuditEvents.IterateAll(context.Background(), nil)
+				for it.Next() {
+					all = append(all, it.Value())
+				}
+				return all, it.Err()
+			},
+		},
 	}
 	ExecuteTests(tests, t)
 }
@@ -107,7 +107,17 @@ func (c *BuildClient) ListAll(ctx context.Context, opts *BuildListOptions) ([]*r
 	if opts == nil {
 		opts = NewBuildListOptions()
 	}
-	return AutoPage[*BuildListOptions, *resource.Build](opts, func(opts *BuildListOptions) ([]*resource.Build, *Pager, error) {
+	return autoPage[*BuildListOptions, *resource.Build](ctx, c.client, opts, func(ctx context.Context, opts *BuildListOptions) ([]*resource.Build, *Pager, error) {
+		return c.List(ctx, opts)
+	})
+}
+
+// IterateAll lazily retrieves all builds the user has access to, fetching each page only as it's needed
+func (c *BuildClient) IterateAll(ctx context.Context, opts *BuildListOptions) *Iterator[*BuildListOptions, *resource.Build] {
+	if opts == nil {
+		opts = NewBuildListOptions()
+	}
+	return Iterate[*BuildListOptions, *resource.Build](ctx, opts, func(opts *BuildListOptions) ([]*resource.Build, *Pager, error) {
 		return c.List(ctx, opts)
 	})
 }
@@ -131,20 +141,21 @@ func (c *BuildClient) ListForAppAll(ctx context.Context, appGUID string, opts *B
 	if opts == nil {
 		opts = NewBuildAppListOptions()
 	}
-	return AutoPage[*BuildAppListOptions, *resource.Build](opts, func(opts *BuildAppListOptions) ([]*resource.Build, *Pager, error) {
+	return autoPage[*BuildAppListOptions, *resource.Build](ctx, c.client, opts, func(ctx context.Context, opts *BuildAppListOptions) ([]*resource.Build, *Pager, error) {
 		return c.ListForApp(ctx, appGUID, opts)
 	})
 }
 
 // PollStaged waits until the build is staged, fails, or times out
 func (c *BuildClient) PollStaged(ctx context.Context, guid string, opts *PollingOptions) error {
-	return PollForStateOrTimeout(func() (string, error) {
+	_, err := PollForState(ctx, func(ctx context.Context) (string, error) {
 		build, err := c.Get(ctx, guid)
-		if build != nil {
-			return string(build.State), err
+		if err != nil {
+			return "", err
 		}
-		return "", err
-	}, string(resource.BuildStateStaged), opts)
+		return string(build.State), nil
+	}, []string{string(resource.BuildStateStaged)}, []string{string(resource.BuildStateFailed)}, opts)
+	return err
 }
 
 // Single returns a single build matching the options or an error if not exactly 1 match
@@ -4,6 +4,7 @@ import (
 	"context"
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/cloudfoundry/go-cfclient/v3/resource"
 	"github.com/cloudfoundry/go-cfclient/v3/testutil"
@@ -45,6 +46,20 @@ func TestBuilds(t *testing.T) {
 				return c.Builds.Get(context.Background(), "be9db090-ad79-41c1-9a01-6200d896f20f")
 			},
 		},
+		{
+			Description: "Poll build staged",
+			Route: testutil.MockRoute{
+				Method:   "GET",
+				Endpoint: "/v3/builds/be9db090-ad79-41c1-9a01-6200d896f20f",
+				Output:   []string{g.Build("STAGING").JSON, g.Build("STAGED").JSON},
+				Status:   http.StatusOK,
+			},
+			Action: func(c *Client, t *testing.T) (any, error) {
+				opts := NewPollingOptions()
+				opts.CheckInterval = time.Millisecond
+				return nil, c.Builds.PollStaged(context.Background(), "be9db090-ad79-41c1-9a01-6200d896f20f", opts)
+			},
+		},
 		{
 			Description: "Delete build",
 			Route: testutil.MockRoute{
@@ -81,20 +81,19 @@ func (c *BuildpackClient) ListAll(ctx context.Context, opts *BuildpackListOption
 	if opts == nil {
 		opts = NewBuildpackListOptions()
 	}
+	return autoPage[*BuildpackListOptions, *resource.Buildpack](ctx, c.client, opts, func(ctx context.Context, opts *BuildpackListOptions) ([]*resource.Buildpack, *Pager, error) {
+		return c.List(ctx, opts)
+	})
+}
 
-	var all []*resource.Buildpack
-	for {
-		page, pager, err := c.List(ctx, opts)
-		if err != nil {
-			return nil, err
-		}
-		all = append(all, page...)
-		if !pager.HasNextPage() {
-			break
-		}
-		pager.NextPage(opts)
+// IterateAll lazily retrieves all buildpacks the user has access to, fetching each page only as it's needed
+func (c *BuildpackClient) IterateAll(ctx context.Context, opts *BuildpackListOptions) *Iterator[*BuildpackListOptions, *resource.Buildpack] {
+	if opts == nil {
+		opts = NewBuildpackListOptions()
 	}
-	return all, nil
+	return Iterate[*BuildpackListOptions, *resource.Buildpack](ctx, opts, func(opts *BuildpackListOptions) ([]*resource.Buildpack, *Pager, error) {
+		return c.List(ctx, opts)
+	})
 }
 
 // Single returns a single buildpack matching the options or an error if not exactly 1 match
@@ -1,15 +1,12 @@
 package client
 
 import (
-	"bytes"
 	"context"
 	"errors"
 	"fmt"
 	"io"
-	"mime/multipart"
 	"net/http"
 	"net/url"
-	"path/filepath"
 	"strings"
 
 	"github.com/cloudfoundry/go-cfclient/v3/config"
@@ -24,10 +21,12 @@ type Client struct {
 	Admin                     *AdminClient
 	Applications              *AppClient
 	AppFeatures               *AppFeatureClient
+	AppSSH                    *AppSSHClient
 	AppUsageEvents            *AppUsageClient
 	AuditEvents               *AuditEventClient
 	Buildpacks                *BuildpackClient
 	Builds                    *BuildClient
+	CredHub                   *CredHubClient
 	Deployments               *DeploymentClient
 	Domains                   *DomainClient
 	Droplets                  *DropletClient
@@ -35,7 +34,10 @@ type Client struct {
 	FeatureFlags              *FeatureFlagClient
 	IsolationSegments         *IsolationSegmentClient
 	Jobs                      *JobClient
+	LogCache                  *LogCacheClient
+	LogStream                 *LogStreamClient
 	Manifests                 *ManifestClient
+	NetworkPolicies           *NetworkPolicyClient
 	Organizations             *OrganizationClient
 	OrganizationQuotas        *OrganizationQuotaClient
 	Packages                  *PackageClient
@@ -45,6 +47,7 @@ type Client struct {
 	Roles                     *RoleClient
 	Root                      *RootClient
 	Routes                    *RouteClient
+	Routing                   *RoutingClient
 	SecurityGroups            *SecurityGroupClient
 	ServiceBrokers            *ServiceBrokerClient
 	ServiceCredentialBindings *ServiceCredentialBindingClient
@@ -60,6 +63,7 @@ type Client struct {
 	SpaceQuotas               *SpaceQuotaClient
 	Stacks                    *StackClient
 	Tasks                     *TaskClient
+	UAA                       *UAAClient
 	Users                     *UserClient
 
 	common commonClient // Reuse a single struct instead of allocating one for each commonClient on the heap.
@@ -87,10 +91,12 @@ func New(config *config.Config) (*Client, error) {
 	client.Admin = (*AdminClient)(&client.common)
 	client.Applications = (*AppClient)(&client.common)
 	client.AppFeatures = (*AppFeatureClient)(&client.common)
+	client.AppSSH = (*AppSSHClient)(&client.common)
 	client.AppUsageEvents = (*AppUsageClient)(&client.common)
 	client.AuditEvents = (*AuditEventClient)(&client.common)
 	client.Buildpacks = (*BuildpackClient)(&client.common)
 	client.Builds = (*BuildClient)(&client.common)
+	client.CredHub = (*CredHubClient)(&client.common)
 	client.Deployments = (*DeploymentClient)(&client.common)
 	client.Domains = (*DomainClient)(&client.common)
 	client.Droplets = (*DropletClient)(&client.common)
@@ -98,7 +104,10 @@ func New(config *config.Config) (*Client, error) {
 	client.FeatureFlags = (*FeatureFlagClient)(&client.common)
 	client.IsolationSegments = (*IsolationSegmentClient)(&client.common)
 	client.Jobs = (*JobClient)(&client.common)
+	client.LogCache = (*LogCacheClient)(&client.common)
+	client.LogStream = (*LogStreamClient)(&client.common)
 	client.Manifests = (*ManifestClient)(&client.common)
+	client.NetworkPolicies = (*NetworkPolicyClient)(&client.common)
 	client.Organizations = (*OrganizationClient)(&client.common)
 	client.OrganizationQuotas = (*OrganizationQuotaClient)(&client.common)
 	client.Packages = (*PackageClient)(&client.common)
@@ -108,6 +117,7 @@ func New(config *config.Config) (*Client, error) {
 	client.Roles = (*RoleClient)(&client.common)
 	client.Root = (*RootClient)(&client.common)
 	client.Routes = (*RouteClient)(&client.common)
+	client.Routing = (*RoutingClient)(&client.common)
 	client.SecurityGroups = (*SecurityGroupClient)(&client.common)
 	client.ServiceBrokers = (*ServiceBrokerClient)(&client.common)
 	client.ServiceCredentialBindings = (*ServiceCredentialBindingClient)(&client.common)
@@ -123,6 +133,7 @@ func New(config *config.Config) (*Client, error) {
 	client.SpaceFeatures = (*SpaceFeatureClient)(&client.common)
 	client.Stacks = (*StackClient)(&client.common)
 	client.Tasks = (*TaskClient)(&client.common)
+	client.UAA = (*UAAClient)(&client.common)
 	client.Users = (*UserClient)(&client.common)
 	return client, nil
 }
@@ -247,28 +258,15 @@ func (c *Client) post(ctx context.Context, resourcePath string, params, result a
 // Download the bits of an existing package or droplet
 // It is the caller's responsibility to close the io.ReadCloser
 func (c *Client) download(ctx context.Context, resourcePath string) (io.ReadCloser, error) {
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.ApiURL(resourcePath), nil)
-	if err != nil {
-		return nil, fmt.Errorf("creating download request for %s failed: %w", resourcePath, err)
-	}
-	resp, err := c.ExecuteAuthRequest(internal.IgnoreRedirect(req))
+	blobStoreLocation, err := c.downloadLocation(ctx, resourcePath)
 	if err != nil {
-		return nil, fmt.Errorf("executing download request for %s failed: %w", resourcePath, err)
-	}
-	ios.Close(resp.Body)
-	if !internal.IsResponseRedirect(resp.StatusCode) {
-		return nil, fmt.Errorf("error downloading `%s` bits, expected redirect to blobstore", resourcePath)
-	}
-	// get the full URL to the blobstore via the Location header
-	blobStoreLocation := resp.Header.Get("Location")
-	if blobStoreLocation == "" {
-		return nil, errors.New("response redirect Location header was empty")
+		return nil, err
 	}
-	req, err = http.NewRequestWithContext(ctx, http.MethodGet, blobStoreLocation, nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, blobStoreLocation, nil)
 	if err != nil {
 		return nil, fmt.Errorf("creating blob download request for %s failed: %w", blobStoreLocation, err)
 	}
-	resp, err = c.ExecuteRequest(req)
+	resp, err := c.ExecuteRequest(req)
 	if err != nil {
 		return nil, fmt.Errorf("executing blob download request for %s failed: %w", blobStoreLocation, err)
 	}
@@ -282,38 +280,34 @@ func (c *Client) download(ctx context.Context, resourcePath string) (io.ReadClos
 // struct to unmarshall the result body. If the resource returns an async job ID in the Location
 // header then the job GUID is returned which the caller can reference via the job endpoint.
 func (c *Client) postFileUpload(ctx context.Context, path, fieldName, fileName string, fileContent io.Reader, result any) (string, error) {
-	// Validate input parameters
-	if path == "" || fieldName == "" || fileName == "" {
-		return "", errors.New("path, fieldName, and fileName are required")
-	}
 	if fileContent == nil {
 		return "", fmt.Errorf("no content was provided for the %s file", fileName)
 	}
+	return c.postMultipartUpload(ctx, path, nil, fieldName, fileName, fileContent, result)
+}
 
+// postMultipartUpload does an HTTP POST to the specified endpoint with a multipart form containing the
+// fields and optionally the specified file, and automatically handles the result whether that's a JSON
+// body or job ID.
+//
+// The form is streamed so the file is never held in memory. A file that's an *os.File or an io.ReaderAt
+// with a Size can be read again to resend the form after re-authenticating, and the progress of sending
+// the file is reported to any UploadProgressFunc set on the context with WithUploadProgress.
+func (c *Client) postMultipartUpload(ctx context.Context, path string, fields map[string]string, fieldName, fileName string, fileContent io.Reader, result any) (string, error) {
+	// Validate input parameters
+	if path == "" || fieldName == "" || fileName == "" {
+		return "", errors.New("path, fieldName, and fileName are required")
+	}
 	if !check.IsNil(result) && !check.IsPointer(result) {
 		return "", errors.New("expected result to be a pointer type or nil")
 	}
-	//
-	// Prepare multipart form data
-	body := &bytes.Buffer{}
-	formWriter := multipart.NewWriter(body)
-	part, err := formWriter.CreateFormFile(fieldName, filepath.Base(fileName))
-	if err != nil {
-		return "", fmt.Errorf("error uploading file to %s: %w", path, err)
-	}
-	if _, err = io.Copy(part, fileContent); err != nil {
-		return "", fmt.Errorf("error uploading file to %s, failed on copy: %w", path, err)
-	}
-	if err = formWriter.Close(); err != nil {
-		return "", fmt.Errorf("error uploading file to %s, failed to close multipart form writer: %w", path, err)
-	}
 
 	// Create and execute the HTTP request
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.ApiURL(path), body)
+	upload := newMultipartUpload(ctx, fields, fieldName, fileName, fileContent)
+	req, err := upload.newRequest(ctx, c.ApiURL(path))
 	if err != nil {
-		return "", fmt.Errorf("error creating request: %w", err)
+		return "", fmt.Errorf("error uploading file to %s: %w", path, err)
 	}
-	req.Header.Set("Content-Type", formWriter.FormDataContentType())
 
 	resp, err := c.ExecuteAuthRequest(req)
 	if err != nil {
@@ -354,6 +348,34 @@ func (c *Client) createOrUpdate(ctx context.Context, method, resourcePath string
 	return internal.DecodeJobIDOrBody(resp, result)
 }
 
+// executeExternal does an authenticated HTTP request against the absolute URL of a platform service that lives
+// outside the CF API, like log-cache, and automatically handles the JSON request and response bodies.
+//
+// Any non-nil params are JSON encoded as the request body and the JSON response body is unmarshalled into
+// the optional result.
+func (c *Client) executeExternal(ctx context.Context, method, serviceURL string, params, result any) error {
+	if !check.IsNil(result) && !check.IsPointer(result) {
+		return errors.New("expected result to be a pointer type, or nil")
+	}
+	body, err := internal.EncodeBody(params)
+	if err != nil {
+		return fmt.Errorf("failed to encode params: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, serviceURL, body)
+	if err != nil {
+		return fmt.Errorf("creating %s request for %s failed: %w", method, serviceURL, err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := c.ExecuteAuthRequest(req)
+	if err != nil {
+		return fmt.Errorf("executing %s request for %s failed: %w", method, serviceURL, err)
+	}
+	defer ios.Close(resp.Body)
+	return internal.DecodeBody(resp, result)
+}
+
 // executeHTTPRequest is the low level client function that handles executing the request against the
 // correct http.Client.
 func (c *Client) executeHTTPRequest(req *http.Request, includeAuthHeader bool) (resp *http.Response, err error) {
@@ -0,0 +1,205 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/cloudfoundry/go-cfclient/v3/internal/path"
+	"github.com/cloudfoundry/go-cfclient/v3/resource"
+)
+
+// CredHubClient manages credentials and their permissions via the CredHub linked from the API root
+type CredHubClient commonClient
+
+// CreatePermission grants the actor the operations on credentials matching the path
+func (c *CredHubClient) CreatePermission(ctx context.Context, r *resource.CredHubPermissionCreate) (*resource.CredHubPermission, error) {
+	var p resource.CredHubPermission
+	if err := c.execute(ctx, http.MethodPost, "/api/v2/permissions", r, &p); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// Delete all versions of the named credential
+func (c *CredHubClient) Delete(ctx context.Context, name string) error {
+	return c.execute(ctx, http.MethodDelete, path.Format("/api/v1/data?%s", url.Values{"name": {name}}), nil, nil)
+}
+
+// DeletePermission deletes the specified permission
+func (c *CredHubClient) DeletePermission(ctx context.Context, uuid string) error {
+	return c.execute(ctx, http.MethodDelete, path.Format("/api/v2/permissions/%s", uuid), nil, nil)
+}
+
+// FindByName retrieves the names of the latest credentials whose name contains the specified value
+func (c *CredHubClient) FindByName(ctx context.Context, nameLike string) ([]*resource.CredHubFoundCredential, error) {
+	return c.find(ctx, url.Values{"name-like": {nameLike}})
+}
+
+// FindByPath retrieves the names of the latest credentials stored under the specified path
+func (c *CredHubClient) FindByPath(ctx context.Context, credPath string) ([]*resource.CredHubFoundCredential, error) {
+	return c.find(ctx, url.Values{"path": {credPath}})
+}
+
+// Generate a new credential version from the specified parameters
+func (c *CredHubClient) Generate(ctx context.Context, r *resource.CredHubCredentialGenerate) (*resource.CredHubCredential, error) {
+	var cred resource.CredHubCredential
+	if err := c.execute(ctx, http.MethodPost, "/api/v1/data", r, &cred); err != nil {
+		return nil, err
+	}
+	return &cred, nil
+}
+
+// GetByID retrieves the specified credential version
+func (c *CredHubClient) GetByID(ctx context.Context, id string) (*resource.CredHubCredential, error) {
+	var cred resource.CredHubCredential
+	if err := c.execute(ctx, http.MethodGet, path.Format("/api/v1/data/%s", id), nil, &cred); err != nil {
+		return nil, err
+	}
+	return &cred, nil
+}
+
+// GetByName retrieves the current version of the named credential
+func (c *CredHubClient) GetByName(ctx context.Context, name string) (*resource.CredHubCredential, error) {
+	var list resource.CredHubCredentialList
+	params := url.Values{"name": {name}, "current": {"true"}}
+	if err := c.execute(ctx, http.MethodGet, path.Format("/api/v1/data?%s", params), nil, &list); err != nil {
+		return nil, err
+	}
+	if len(list.Data) == 0 {
+		return nil, fmt.Errorf("credential %s: %w", name, ErrNoResultsReturned)
+	}
+	return list.Data[0], nil
+}
+
+// GetPermission retrieves the specified permission
+func (c *CredHubClient) GetPermission(ctx context.Context, uuid string) (*resource.CredHubPermission, error) {
+	var p resource.CredHubPermission
+	if err := c.execute(ctx, http.MethodGet, path.Format("/api/v2/permissions/%s", uuid), nil, &p); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// GetPermissionByActorAndPath retrieves the permission granted to the actor on the path
+func (c *CredHubClient) GetPermissionByActorAndPath(ctx context.Context, actor, credPath string) (*resource.CredHubPermission, error) {
+	var p resource.CredHubPermission
+	params := url.Values{"actor": {actor}, "path": {credPath}}
+	if err := c.execute(ctx, http.MethodGet, path.Format("/api/v2/permissions?%s", params), nil, &p); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// Interpolate returns a copy of the credentials with every credhub-ref placeholder, at any depth,
+// replaced by the current value of the referenced credential
+func (c *CredHubClient) Interpolate(ctx context.Context, credentials map[string]any) (map[string]any, error) {
+	resolved := make(map[string]any)
+	v, err := c.interpolate(ctx, credentials, resolved)
+	if err != nil {
+		return nil, err
+	}
+	m, ok := v.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("expected the referenced credential %s to be a JSON object", credentials[resource.CredHubRefKey])
+	}
+	return m, nil
+}
+
+// Set a new version of the credential to the specified value
+func (c *CredHubClient) Set(ctx context.Context, r *resource.CredHubCredentialSet) (*resource.CredHubCredential, error) {
+	var cred resource.CredHubCredential
+	if err := c.execute(ctx, http.MethodPut, "/api/v1/data", r, &cred); err != nil {
+		return nil, err
+	}
+	return &cred, nil
+}
+
+// UpdatePermission replaces the actor, path and operations of the specified permission
+func (c *CredHubClient) UpdatePermission(ctx context.Context, uuid string, r *resource.CredHubPermissionCreate) (*resource.CredHubPermission, error) {
+	var p resource.CredHubPermission
+	if err := c.execute(ctx, http.MethodPut, path.Format("/api/v2/permissions/%s", uuid), r, &p); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+func (c *CredHubClient) find(ctx context.Context, params url.Values) ([]*resource.CredHubFoundCredential, error) {
+	var res resource.CredHubFindResults
+	if err := c.execute(ctx, http.MethodGet, path.Format("/api/v1/data?%s", params), nil, &res); err != nil {
+		return nil, err
+	}
+	return res.Credentials, nil
+}
+
+func (c *CredHubClient) interpolate(ctx context.Context, v any, resolved map[string]any) (any, error) {
+	if name, ok := resource.CredHubRef(v); ok {
+		if value, ok := resolved[name]; ok {
+			return value, nil
+		}
+		cred, err := c.GetByName(ctx, name)
+		if err != nil {
+			return nil, fmt.Errorf("error resolving %s %s: %w", resource.CredHubRefKey, name, err)
+		}
+		resolved[name] = cred.Value
+		return cred.Value, nil
+	}
+
+	switch t := v.(type) {
+	case map[string]any:
+		m := make(map[string]any, len(t))
+		for k, e := range t {
+			ie, err := c.interpolate(ctx, e, resolved)
+			if err != nil {
+				return nil, err
+			}
+			m[k] = ie
+		}
+		return m, nil
+	case []any:
+		a := make([]any, len(t))
+		for i, e := range t {
+			ie, err := c.interpolate(ctx, e, resolved)
+			if err != nil {
+				return nil, err
+			}
+			a[i] = ie
+		}
+		return a, nil
+	default:
+		return v, nil
+	}
+}
+
+func (c *CredHubClient) execute(ctx context.Context, method, urlPath string, params, result any) error {
+	credHubURL, err := c.client.Root.serviceURL(ctx, "credhub", func(l *resource.RootLinks) resource.Link {
+		return l.Credhub
+	}, urlPath)
+	if err != nil {
+		return err
+	}
+	return c.client.executeExternal(ctx, method, credHubURL, params, result)
+}
+
+// hasCredHubRef returns true if any value within v is a credhub-ref placeholder
+func hasCredHubRef(v any) bool {
+	if _, ok := resource.CredHubRef(v); ok {
+		return true
+	}
+	switch t := v.(type) {
+	case map[string]any:
+		for _, e := range t {
+			if hasCredHubRef(e) {
+				return true
+			}
+		}
+	case []any:
+		for _, e := range t {
+			if hasCredHubRef(e) {
+				return true
+			}
+		}
+	}
+	return false
+}
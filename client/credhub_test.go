@@ -0,0 +1,221 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/cloudfoundry/go-cfclient/v3/config"
+	"github.com/cloudfoundry/go-cfclient/v3/resource"
+	"github.com/cloudfoundry/go-cfclient/v3/testutil"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCredHub(t *testing.T) {
+	g := testutil.NewObjectJSONGenerator(1)
+	cred := g.CredHubCredential()
+	permission := g.CredHubPermission()
+
+	tests := []RouteTest{
+		{
+			Description: "Get credential by id",
+			Route: testutil.MockRoute{
+				Method:   "GET",
+				Endpoint: "/credhub/api/v1/data/" + cred.GUID,
+				Output:   []string{cred.JSON},
+				Status:   http.StatusOK,
+			},
+			Expected: cred.JSON,
+			Action: func(c *Client, t *testing.T) (any, error) {
+				return c.CredHub.GetByID(context.Background(), cred.GUID)
+			},
+		},
+		{
+			Description: "Get credential by name",
+			Route: testutil.MockRoute{
+				Method:      "GET",
+				Endpoint:    "/credhub/api/v1/data",
+				Output:      []string{`{"data":[` + cred.JSON + `]}`},
+				Status:      http.StatusOK,
+				QueryString: "current=true&name=" + cred.Name,
+			},
+			Expected: cred.JSON,
+			Action: func(c *Client, t *testing.T) (any, error) {
+				return c.CredHub.GetByName(context.Background(), cred.Name)
+			},
+		},
+		{
+			Description: "Find credentials by path",
+			Route: testutil.MockRoute{
+				Method:      "GET",
+				Endpoint:    "/credhub/api/v1/data",
+				Output:      []string{`{"credentials":[{"name":"` + cred.Name + `","version_created_at":"2024-03-01T17:02:44Z"}]}`},
+				Status:      http.StatusOK,
+				QueryString: "path=/c/p-broker",
+			},
+			Expected: `[{"name":"` + cred.Name + `","version_created_at":"2024-03-01T17:02:44Z"}]`,
+			Action: func(c *Client, t *testing.T) (any, error) {
+				return c.CredHub.FindByPath(context.Background(), "/c/p-broker")
+			},
+		},
+		{
+			Description: "Set credential",
+			Route: testutil.MockRoute{
+				Method:   "PUT",
+				Endpoint: "/credhub/api/v1/data",
+				Output:   []string{cred.JSON},
+				Status:   http.StatusOK,
+				PostForm: `{
+					"name": "` + cred.Name + `",
+					"type": "json",
+					"value": {"username": "bd4650060752443abe4f9dbabf456184", "password": "9wkbbxk7auviqsqm"}
+				}`,
+			},
+			Expected: cred.JSON,
+			Action: func(c *Client, t *testing.T) (any, error) {
+				r := resource.NewCredHubCredentialSet(cred.Name, resource.CredHubCredentialTypeJSON, map[string]string{
+					"username": "bd4650060752443abe4f9dbabf456184",
+					"password": "9wkbbxk7auviqsqm",
+				})
+				return c.CredHub.Set(context.Background(), r)
+			},
+		},
+		{
+			Description: "Generate password",
+			Route: testutil.MockRoute{
+				Method:   "POST",
+				Endpoint: "/credhub/api/v1/data",
+				Output:   []string{cred.JSON},
+				Status:   http.StatusOK,
+				PostForm: `{ "name": "/db-password", "type": "password", "parameters": { "length": 40 } }`,
+			},
+			Expected: cred.JSON,
+			Action: func(c *Client, t *testing.T) (any, error) {
+				return c.CredHub.Generate(context.Background(), resource.NewCredHubPasswordGenerate("/db-password", 40))
+			},
+		},
+		{
+			Description: "Delete credential",
+			Route: testutil.MockRoute{
+				Method:      "DELETE",
+				Endpoint:    "/credhub/api/v1/data",
+				Status:      http.StatusNoContent,
+				QueryString: "name=" + cred.Name,
+			},
+			Action: func(c *Client, t *testing.T) (any, error) {
+				return nil, c.CredHub.Delete(context.Background(), cred.Name)
+			},
+		},
+		{
+			Description: "Create permission",
+			Route: testutil.MockRoute{
+				Method:   "POST",
+				Endpoint: "/credhub/api/v2/permissions",
+				Output:   []string{permission.JSON},
+				Status:   http.StatusCreated,
+				PostForm: `{ "actor": "mtls-app:app-guid", "path": "/c/p-broker/*", "operations": ["read", "write"] }`,
+			},
+			Expected: permission.JSON,
+			Action: func(c *Client, t *testing.T) (any, error) {
+				r := resource.NewCredHubPermissionCreate("mtls-app:app-guid", "/c/p-broker/*",
+					resource.CredHubPermissionRead, resource.CredHubPermissionWrite)
+				return c.CredHub.CreatePermission(context.Background(), r)
+			},
+		},
+		{
+			Description: "Get permission",
+			Route: testutil.MockRoute{
+				Method:   "GET",
+				Endpoint: "/credhub/api/v2/permissions/" + permission.GUID,
+				Output:   []string{permission.JSON},
+				Status:   http.StatusOK,
+			},
+			Expected: permission.JSON,
+			Action: func(c *Client, t *testing.T) (any, error) {
+				return c.CredHub.GetPermission(context.Background(), permission.GUID)
+			},
+		},
+		{
+			Description: "Get permission by actor and path",
+			Route: testutil.MockRoute{
+				Method:      "GET",
+				Endpoint:    "/credhub/api/v2/permissions",
+				Output:      []string{permission.JSON},
+				Status:      http.StatusOK,
+				QueryString: "actor=mtls-app:app-guid&path=/c/p-broker/*",
+			},
+			Expected: permission.JSON,
+			Action: func(c *Client, t *testing.T) (any, error) {
+				return c.CredHub.GetPermissionByActorAndPath(context.Background(), "mtls-app:app-guid", "/c/p-broker/*")
+			},
+		},
+		{
+			Description: "Update permission",
+			Route: testutil.MockRoute{
+				Method:   "PUT",
+				Endpoint: "/credhub/api/v2/permissions/" + permission.GUID,
+				Output:   []string{permission.JSON},
+				Status:   http.StatusOK,
+				PostForm: `{ "actor": "mtls-app:app-guid", "path": "/c/p-broker/*", "operations": ["read"] }`,
+			},
+			Expected: permission.JSON,
+			Action: func(c *Client, t *testing.T) (any, error) {
+				r := resource.NewCredHubPermissionCreate("mtls-app:app-guid", "/c/p-broker/*", resource.CredHubPermissionRead)
+				return c.CredHub.UpdatePermission(context.Background(), permission.GUID, r)
+			},
+		},
+		{
+			Description: "Delete permission",
+			Route: testutil.MockRoute{
+				Method:   "DELETE",
+				Endpoint: "/credhub/api/v2/permissions/" + permission.GUID,
+				Status:   http.StatusNoContent,
+			},
+			Action: func(c *Client, t *testing.T) (any, error) {
+				return nil, c.CredHub.DeletePermission(context.Background(), permission.GUID)
+			},
+		},
+	}
+	ExecuteTests(tests, t)
+}
+
+func TestServiceCredentialBindingDetailsCredHubInterpolation(t *testing.T) {
+	g := testutil.NewObjectJSONGenerator(1)
+	cred := g.CredHubCredential()
+
+	serverURL := testutil.SetupMultiple([]testutil.MockRoute{
+		{
+			Method:   "GET",
+			Endpoint: "/v3/service_credential_bindings/59ba6d78-6a21-4321-83a9-f7eacd88b08d/details",
+			Output: []string{
+				`{"credentials": {"credhub-ref": "` + cred.Name + `"}}`,
+				`{"credentials": {"credhub-ref": "` + cred.Name + `"}}`,
+			},
+			Status: http.StatusOK,
+		},
+		{
+			Method:      "GET",
+			Endpoint:    "/credhub/api/v1/data",
+			Output:      []string{`{"data":[` + cred.JSON + `]}`},
+			Status:      http.StatusOK,
+			QueryString: "current=true&name=" + cred.Name,
+		},
+	}, t)
+	defer testutil.Teardown()
+
+	c, _ := config.New(serverURL, config.Token("", "fake-refresh-token"))
+	cf, err := New(c)
+	require.NoError(t, err)
+
+	details, err := cf.ServiceCredentialBindings.GetDetails(context.Background(), "59ba6d78-6a21-4321-83a9-f7eacd88b08d")
+	require.NoError(t, err)
+	require.Equal(t, map[string]any{"credhub-ref": cred.Name}, details.Credentials)
+
+	details, err = cf.ServiceCredentialBindings.GetDetailsInterpolated(context.Background(), "59ba6d78-6a21-4321-83a9-f7eacd88b08d")
+	require.NoError(t, err)
+	require.Equal(t, map[string]any{
+		"username": "bd4650060752443abe4f9dbabf456184",
+		"password": "9wkbbxk7auviqsqm",
+	}, details.Credentials)
+}
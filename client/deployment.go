@@ -38,6 +38,16 @@ func (c *DeploymentClient) Cancel(ctx context.Context, guid string) error {
 	return err
 }
 
+// Continue a paused canary deployment to its next step, or to completion if it's at the last step
+func (c *DeploymentClient) Continue(ctx context.Context, guid string) (*resource.Deployment, error) {
+	var d resource.Deployment
+	_, err := c.client.post(ctx, path.Format("/v3/deployments/%s/actions/continue", guid), nil, &d)
+	if err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
 // Create a new deployment
 func (c *DeploymentClient) Create(ctx context.Context, r *resource.DeploymentCreate) (*resource.Deployment, error) {
 	// validate the params
@@ -89,11 +99,48 @@ func (c *DeploymentClient) ListAll(ctx context.Context, opts *DeploymentListOpti
 	if opts == nil {
 		opts = NewDeploymentListOptions()
 	}
-	return AutoPage[*DeploymentListOptions, *resource.Deployment](opts, func(opts *DeploymentListOptions) ([]*resource.Deployment, *Pager, error) {
+	return autoPage[*DeploymentListOptions, *resource.Deployment](ctx, c.client, opts, func(ctx context.Context, opts *DeploymentListOptions) ([]*resource.Deployment, *Pager, error) {
+		return c.List(ctx, opts)
+	})
+}
+
+// IterateAll lazily retrieves all deployments the user has access to, fetching each page only as it's needed
+func (c *DeploymentClient) IterateAll(ctx context.Context, opts *DeploymentListOptions) *Iterator[*DeploymentListOptions, *resource.Deployment] {
+	if opts == nil {
+		opts = NewDeploymentListOptions()
+	}
+	return Iterate[*DeploymentListOptions, *resource.Deployment](ctx, opts, func(opts *DeploymentListOptions) ([]*resource.Deployment, *Pager, error) {
 		return c.List(ctx, opts)
 	})
 }
 
+// PollFinalized waits until the deployment is finalized, the timeout is reached or the context is cancelled.
+// A *DeploymentFailedError is returned if the deployment was cancelled or is degenerate
+func (c *DeploymentClient) PollFinalized(ctx context.Context, guid string, opts *PollingOptions) error {
+	var deployment *resource.Deployment
+	_, err := PollForState(ctx, func(ctx context.Context) (string, error) {
+		var err error
+		deployment, err = c.Get(ctx, guid)
+		if err != nil {
+			return "", err
+		}
+		return deployment.Status.Value, nil
+	}, []string{resource.DeploymentStatusValueFinalized}, nil, opts)
+	if err != nil {
+		return err
+	}
+
+	switch deployment.Status.Reason {
+	case resource.DeploymentStatusReasonCanceled, resource.DeploymentStatusReasonDegenerate:
+		return &DeploymentFailedError{
+			GUID:   guid,
+			Reason: deployment.Status.Reason,
+			Detail: deployment.Status.ErrorDetail(),
+		}
+	}
+	return nil
+}
+
 // Single returns a single deployment matching the options or an error if not exactly 1 match
 func (c *DeploymentClient) Single(ctx context.Context, opts *DeploymentListOptions) (*resource.Deployment, error) {
 	return Single[*DeploymentListOptions, *resource.Deployment](opts, func(opts *DeploymentListOptions) ([]*resource.Deployment, *Pager, error) {
@@ -2,8 +2,11 @@ package client
 
 import (
 	"context"
+	"errors"
 	"net/http"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/cloudfoundry/go-cfclient/v3/resource"
 	"github.com/cloudfoundry/go-cfclient/v3/testutil"
@@ -17,6 +20,10 @@ func TestDeployments(t *testing.T) {
 	deployment2 := g.Deployment().JSON
 	deployment3 := g.Deployment().JSON
 	deployment4 := g.Deployment().JSON
+	deployed := g.DeploymentWithStatus("FINALIZED", "DEPLOYED").JSON
+	degenerate := strings.Replace(g.DeploymentWithStatus("FINALIZED", "DEGENERATE").JSON,
+		`"last_successful_healthcheck": "2018-04-25T22:42:10Z"`,
+		`"last_successful_healthcheck": "2018-04-25T22:42:10Z", "error": "the app was deleted"`, 1)
 
 	tests := []RouteTest{
 		{
@@ -82,6 +89,70 @@ func TestDeployments(t *testing.T) {
 				return nil, c.Deployments.Cancel(context.Background(), "2b56dc7b-2a14-49ea-be29-ca182b14a998")
 			},
 		},
+		{
+			Description: "Create canary deployment",
+			Route: testutil.MockRoute{
+				Method:   "POST",
+				Endpoint: "/v3/deployments",
+				Output:   g.Single(deployment),
+				Status:   http.StatusCreated,
+				PostForm: `{"relationships":{"app":{"data":{"guid":"305cea31-5a44-45ca-b51b-e89c7a8ef8b2"}}}, "droplet": {"guid": "c2941033-4575-486d-bf2c-3ae49e8b4ca1"}, "strategy": "canary", "options": {"max_in_flight": 2, "canary": {"steps": [{"instance_weight": 20}, {"instance_weight": 60}]}}}`,
+			},
+			Expected: deployment,
+			Action: func(c *Client, t *testing.T) (any, error) {
+				r := resource.NewDeploymentCreate("305cea31-5a44-45ca-b51b-e89c7a8ef8b2")
+				r.Droplet = &resource.Relationship{
+					GUID: "c2941033-4575-486d-bf2c-3ae49e8b4ca1",
+				}
+				r.Strategy = resource.DeploymentStrategyCanary
+				r.Options = &resource.DeploymentOptions{
+					MaxInFlight: 2,
+					Canary: &resource.DeploymentCanaryOptions{
+						Steps: []resource.DeploymentCanaryStep{{InstanceWeight: 20}, {InstanceWeight: 60}},
+					},
+				}
+				return c.Deployments.Create(context.Background(), r)
+			},
+		},
+		{
+			Description: "Create deployment with web process overrides",
+			Route: testutil.MockRoute{
+				Method:   "POST",
+				Endpoint: "/v3/deployments",
+				Output:   g.Single(deployment),
+				Status:   http.StatusCreated,
+				PostForm: `{"relationships":{"app":{"data":{"guid":"305cea31-5a44-45ca-b51b-e89c7a8ef8b2"}}}, "droplet": {"guid": "c2941033-4575-486d-bf2c-3ae49e8b4ca1"}, "options": {"max_in_flight": 3, "web_instances": 6, "memory_in_mb": 1024, "disk_in_mb": 2048, "log_rate_limit_in_bytes_per_second": -1}}`,
+			},
+			Expected: deployment,
+			Action: func(c *Client, t *testing.T) (any, error) {
+				instances, memory, disk, logRate := 6, 1024, 2048, -1
+				r := resource.NewDeploymentCreate("305cea31-5a44-45ca-b51b-e89c7a8ef8b2")
+				r.Droplet = &resource.Relationship{
+					GUID: "c2941033-4575-486d-bf2c-3ae49e8b4ca1",
+				}
+				r.Options = &resource.DeploymentOptions{
+					MaxInFlight:                  3,
+					WebInstances:                 &instances,
+					MemoryInMB:                   &memory,
+					DiskInMB:                     &disk,
+					LogRateLimitInBytesPerSecond: &logRate,
+				}
+				return c.Deployments.Create(context.Background(), r)
+			},
+		},
+		{
+			Description: "Continue deployment",
+			Route: testutil.MockRoute{
+				Method:   "POST",
+				Endpoint: "/v3/deployments/2b56dc7b-2a14-49ea-be29-ca182b14a998/actions/continue",
+				Output:   g.Single(deployment),
+				Status:   http.StatusOK,
+			},
+			Expected: deployment,
+			Action: func(c *Client, t *testing.T) (any, error) {
+				return c.Deployments.Continue(context.Background(), "2b56dc7b-2a14-49ea-be29-ca182b14a998")
+			},
+		},
 		{
 			Description: "Get deployment",
 			Route: testutil.MockRoute{
@@ -95,6 +166,38 @@ func TestDeployments(t *testing.T) {
 				return c.Deployments.Get(context.Background(), "2b56dc7b-2a14-49ea-be29-ca182b14a998")
 			},
 		},
+		{
+			Description: "Poll deployment finalized",
+			Route: testutil.MockRoute{
+				Method:   "GET",
+				Endpoint: "/v3/deployments/2b56dc7b-2a14-49ea-be29-ca182b14a998",
+				Output:   []string{deployment, deployed},
+				Status:   http.StatusOK,
+			},
+			Action: func(c *Client, t *testing.T) (any, error) {
+				opts := NewPollingOptions()
+				opts.CheckInterval = time.Millisecond
+				return nil, c.Deployments.PollFinalized(context.Background(), "2b56dc7b-2a14-49ea-be29-ca182b14a998", opts)
+			},
+		},
+		{
+			Description: "Poll deployment degenerate",
+			Route: testutil.MockRoute{
+				Method:   "GET",
+				Endpoint: "/v3/deployments/2b56dc7b-2a14-49ea-be29-ca182b14a998",
+				Output:   []string{degenerate},
+				Status:   http.StatusOK,
+			},
+			Action: func(c *Client, t *testing.T) (any, error) {
+				err := c.Deployments.PollFinalized(context.Background(), "2b56dc7b-2a14-49ea-be29-ca182b14a998", nil)
+				var failedErr *DeploymentFailedError
+				require.ErrorAs(t, err, &failedErr)
+				require.Equal(t, "DEGENERATE", failedErr.Reason)
+				require.Equal(t, "the app was deleted", failedErr.Detail)
+				require.True(t, errors.Is(err, AsyncProcessFailedError))
+				return nil, nil
+			},
+		},
 		{
 			Description: "List first page of deployments",
 			Route: testutil.MockRoute{
@@ -85,7 +85,17 @@ func (c *DomainClient) ListAll(ctx context.Context, opts *DomainListOptions) ([]
 	if opts == nil {
 		opts = NewDomainListOptions()
 	}
-	return AutoPage[*DomainListOptions, *resource.Domain](opts, func(opts *DomainListOptions) ([]*resource.Domain, *Pager, error) {
+	return autoPage[*DomainListOptions, *resource.Domain](ctx, c.client, opts, func(ctx context.Context, opts *DomainListOptions) ([]*resource.Domain, *Pager, error) {
+		return c.List(ctx, opts)
+	})
+}
+
+// IterateAll lazily retrieves all domains the user has access to, fetching each page only as it's needed
+func (c *DomainClient) IterateAll(ctx context.Context, opts *DomainListOptions) *Iterator[*DomainListOptions, *resource.Domain] {
+	if opts == nil {
+		opts = NewDomainListOptions()
+	}
+	return Iterate[*DomainListOptions, *resource.Domain](ctx, opts, func(opts *DomainListOptions) ([]*resource.Domain, *Pager, error) {
 		return c.List(ctx, opts)
 	})
 }
@@ -109,7 +119,7 @@ func (c *DomainClient) ListForOrganizationAll(ctx context.Context, organizationG
 	if opts == nil {
 		opts = NewDomainListOptions()
 	}
-	return AutoPage[*DomainListOptions, *resource.Domain](opts, func(opts *DomainListOptions) ([]*resource.Domain, *Pager, error) {
+	return autoPage[*DomainListOptions, *resource.Domain](ctx, c.client, opts, func(ctx context.Context, opts *DomainListOptions) ([]*resource.Domain, *Pager, error) {
 		return c.ListForOrganization(ctx, organizationGUID, opts)
 	})
 }
@@ -0,0 +1,273 @@
+package client
+
+import (
+	"context"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	internal "github.com/cloudfoundry/go-cfclient/v3/internal/http"
+	"github.com/cloudfoundry/go-cfclient/v3/internal/ios"
+)
+
+// DefaultDownloadResumeAttempts is the default number of times an interrupted download is resumed
+const DefaultDownloadResumeAttempts = 5
+
+// DownloadProgressFunc is called as a download is received with the number of bytes received so far and
+// the total number of bytes, which is -1 if the size isn't known. Bytes received before a download was
+// resumed are included
+type DownloadProgressFunc func(received, total int64)
+
+// DownloadOptions configures how bits are downloaded from the blobstore
+type DownloadOptions struct {
+	ResumeAttempts int                  // the maximum number of times an interrupted download is resumed
+	RetryInterval  time.Duration        // how long to wait before resuming an interrupted download
+	Progress       DownloadProgressFunc // optional func called as the download is received
+}
+
+// NewDownloadOptions creates new options to download bits
+func NewDownloadOptions() *DownloadOptions {
+	return &DownloadOptions{
+		ResumeAttempts: DefaultDownloadResumeAttempts,
+		RetryInterval:  time.Second,
+	}
+}
+
+// ChecksumMismatchError is returned when downloaded bits don't match the checksum recorded by the API
+type ChecksumMismatchError struct {
+	Type     string // the checksum algorithm, sha256 or sha1
+	Expected string
+	Actual   string
+}
+
+func (e ChecksumMismatchError) Error() string {
+	return fmt.Sprintf("downloaded bits %s checksum %s does not match the expected checksum %s",
+		e.Type, e.Actual, e.Expected)
+}
+
+// checksum is the algorithm and hex encoded value the downloaded bits are verified against
+type checksum struct {
+	Type  string
+	Value string
+}
+
+func (c checksum) newHash() (hash.Hash, error) {
+	switch strings.ToLower(c.Type) {
+	case "sha256":
+		return sha256.New(), nil
+	case "sha1":
+		// droplets staged by older API versions only have a sha1 checksum
+		return sha1.New(), nil
+	}
+	return nil, fmt.Errorf("unsupported checksum type %q", c.Type)
+}
+
+// downloadLocation requests the resource's download path, which redirects to the blobstore, and returns
+// the blobstore location without following the redirect
+func (c *Client) downloadLocation(ctx context.Context, resourcePath string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.ApiURL(resourcePath), nil)
+	if err != nil {
+		return "", fmt.Errorf("creating download request for %s failed: %w", resourcePath, err)
+	}
+	resp, err := c.ExecuteAuthRequest(internal.IgnoreRedirect(req))
+	if err != nil {
+		return "", fmt.Errorf("executing download request for %s failed: %w", resourcePath, err)
+	}
+	ios.Close(resp.Body)
+	if !internal.IsResponseRedirect(resp.StatusCode) {
+		return "", fmt.Errorf("error downloading `%s` bits, expected redirect to blobstore", resourcePath)
+	}
+	// get the full URL to the blobstore via the Location header
+	blobStoreLocation := resp.Header.Get("Location")
+	if blobStoreLocation == "" {
+		return "", errors.New("response redirect Location header was empty")
+	}
+	return blobStoreLocation, nil
+}
+
+// downloadTo writes the resource's bits to w, resuming the download from the blobstore with a Range
+// request if it's interrupted and verifying the bits against the checksum when one is given
+func (c *Client) downloadTo(ctx context.Context, resourcePath string, w io.Writer, sum *checksum, opts *DownloadOptions) error {
+	if opts == nil {
+		opts = NewDownloadOptions()
+	}
+	var h hash.Hash
+	if sum != nil {
+		var err error
+		if h, err = sum.newHash(); err != nil {
+			return err
+		}
+		w = io.MultiWriter(w, h)
+	}
+
+	location, err := c.downloadLocation(ctx, resourcePath)
+	if err != nil {
+		return err
+	}
+	d := &blobDownload{
+		client:   c,
+		location: location,
+		w:        w,
+		total:    -1,
+		progress: opts.Progress,
+	}
+	for attempt := 0; ; attempt++ {
+		err = d.get(ctx)
+		if err == nil {
+			break
+		}
+		var interrupted *downloadInterruptedError
+		if !errors.As(err, &interrupted) || attempt >= opts.ResumeAttempts {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(opts.RetryInterval):
+		}
+	}
+
+	if sum != nil {
+		actual := hex.EncodeToString(h.Sum(nil))
+		if !strings.EqualFold(actual, sum.Value) {
+			return ChecksumMismatchError{
+				Type:     sum.Type,
+				Expected: sum.Value,
+				Actual:   actual,
+			}
+		}
+	}
+	return nil
+}
+
+// downloadInterruptedError is returned when the blobstore response body fails part way through, so the
+// download can be resumed
+type downloadInterruptedError struct {
+	received int64
+	err      error
+}
+
+func (e *downloadInterruptedError) Error() string {
+	return fmt.Sprintf("download interrupted after %d bytes: %s", e.received, e.err)
+}
+
+func (e *downloadInterruptedError) Unwrap() error {
+	return e.err
+}
+
+// blobDownload tracks how much of the blob has been written so an interrupted download can be resumed
+type blobDownload struct {
+	client   *Client
+	location string
+	w        io.Writer
+	received int64
+	total    int64
+	progress DownloadProgressFunc
+}
+
+// get requests the remainder of the blob and copies it to the writer
+func (d *blobDownload) get(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, d.location, nil)
+	if err != nil {
+		return fmt.Errorf("creating blob download request for %s failed: %w", d.location, err)
+	}
+	if d.received > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", d.received))
+	}
+	resp, err := d.client.ExecuteRequest(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return err
+		}
+		return &downloadInterruptedError{received: d.received, err: err}
+	}
+	defer ios.Close(resp.Body)
+
+	body := io.Reader(resp.Body)
+	switch resp.StatusCode {
+	case http.StatusOK:
+		if resp.ContentLength >= 0 {
+			d.total = resp.ContentLength
+		}
+		// the blobstore ignored the range, so skip the bytes that were already written
+		if d.received > 0 {
+			if _, err = io.CopyN(io.Discard, body, d.received); err != nil {
+				return &downloadInterruptedError{received: d.received, err: err}
+			}
+		}
+	case http.StatusPartialContent:
+		start, total, err := parseContentRange(resp.Header.Get("Content-Range"))
+		if err != nil {
+			return err
+		}
+		if start != d.received {
+			return fmt.Errorf("blobstore resumed the download from byte %d, expected byte %d", start, d.received)
+		}
+		d.total = total
+	default:
+		return fmt.Errorf("error downloading blob from %s: %s", d.location, resp.Status)
+	}
+
+	buf := make([]byte, 32*1024)
+	for {
+		n, readErr := body.Read(buf)
+		if n > 0 {
+			if _, err = d.w.Write(buf[:n]); err != nil {
+				return fmt.Errorf("error writing downloaded bits: %w", err)
+			}
+			d.received += int64(n)
+			if d.progress != nil {
+				d.progress(d.received, d.total)
+			}
+		}
+		if readErr == io.EOF {
+			break
+		}
+		if readErr != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return &downloadInterruptedError{received: d.received, err: readErr}
+		}
+	}
+	if d.total >= 0 && d.received != d.total {
+		return &downloadInterruptedError{received: d.received, err: io.ErrUnexpectedEOF}
+	}
+	return nil
+}
+
+// parseContentRange parses the start and total size from a Content-Range header like bytes 100-199/200,
+// the total is -1 if the size isn't known
+func parseContentRange(contentRange string) (int64, int64, error) {
+	invalid := fmt.Errorf("invalid Content-Range header %q", contentRange)
+	if !strings.HasPrefix(contentRange, "bytes ") {
+		return 0, 0, invalid
+	}
+	byteRange, size, ok := strings.Cut(strings.TrimPrefix(contentRange, "bytes "), "/")
+	if !ok {
+		return 0, 0, invalid
+	}
+	first, _, ok := strings.Cut(byteRange, "-")
+	if !ok {
+		return 0, 0, invalid
+	}
+	start, err := strconv.ParseInt(first, 10, 64)
+	if err != nil {
+		return 0, 0, invalid
+	}
+	total := int64(-1)
+	if size != "*" {
+		if total, err = strconv.ParseInt(size, 10, 64); err != nil {
+			return 0, 0, invalid
+		}
+	}
+	return start, total, nil
+}
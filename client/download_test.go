@@ -0,0 +1,184 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cloudfoundry/go-cfclient/v3/config"
+	"github.com/cloudfoundry/go-cfclient/v3/testutil"
+
+	"github.com/stretchr/testify/require"
+)
+
+// emptySHA256 is the checksum of the droplet in the droplet template
+const emptySHA256 = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+// newFlakyBlobstore serves the bits but drops the connection after cutAfter bytes of the first request,
+// and records the Range header of each request
+func newFlakyBlobstore(t *testing.T, bits []byte, cutAfter int, honorRange bool) (*httptest.Server, *[]string) {
+	var ranges []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ranges = append(ranges, r.Header.Get("Range"))
+		if len(ranges) == 1 && cutAfter > 0 {
+			w.Header().Set("Content-Length", strconv.Itoa(len(bits)))
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write(bits[:cutAfter])
+			return
+		}
+		if !honorRange {
+			_, _ = w.Write(bits)
+			return
+		}
+		http.ServeContent(w, r, "droplet.tgz", time.Time{}, bytes.NewReader(bits))
+	}))
+	t.Cleanup(server.Close)
+	return server, &ranges
+}
+
+func newDownloadTestClient(t *testing.T, blobstoreURL string, routes ...testutil.MockRoute) *Client {
+	routes = append(routes, testutil.MockRoute{
+		Method:           "GET",
+		Endpoint:         "/v3/droplets/59c3d133-2b83-46f3-960e-7765a129aea4/download",
+		Status:           http.StatusFound,
+		RedirectLocation: blobstoreURL,
+	})
+	serverURL := testutil.SetupMultiple(routes, t)
+	t.Cleanup(testutil.Teardown)
+	c, _ := config.New(serverURL, config.Token("", "fake-refresh-token"))
+	cf, err := New(c)
+	require.NoError(t, err)
+	return cf
+}
+
+func testDownloadOptions(progress DownloadProgressFunc) *DownloadOptions {
+	opts := NewDownloadOptions()
+	opts.RetryInterval = time.Millisecond
+	opts.Progress = progress
+	return opts
+}
+
+func TestDropletDownloadTo(t *testing.T) {
+	bits := []byte(strings.Repeat("droplet bits...", 1000))
+	sum := sha256.Sum256(bits)
+	g := testutil.NewObjectJSONGenerator(1)
+	droplet := strings.Replace(g.Droplet().JSON, emptySHA256, hex.EncodeToString(sum[:]), 1)
+	dropletRoute := testutil.MockRoute{
+		Method:   "GET",
+		Endpoint: "/v3/droplets/59c3d133-2b83-46f3-960e-7765a129aea4",
+		Output:   []string{droplet},
+		Status:   http.StatusOK,
+	}
+
+	t.Run("resumes an interrupted download with a range request", func(t *testing.T) {
+		blobstore, ranges := newFlakyBlobstore(t, bits, 4000, true)
+		cf := newDownloadTestClient(t, blobstore.URL, dropletRoute)
+
+		var received, total int64
+		var buf bytes.Buffer
+		err := cf.Droplets.DownloadTo(context.Background(), "59c3d133-2b83-46f3-960e-7765a129aea4", &buf,
+			testDownloadOptions(func(r, t int64) { received, total = r, t }))
+		require.NoError(t, err)
+		require.Equal(t, bits, buf.Bytes())
+		require.Equal(t, []string{"", "bytes=4000-"}, *ranges)
+		require.Equal(t, int64(len(bits)), received)
+		require.Equal(t, int64(len(bits)), total)
+	})
+
+	t.Run("skips the bytes already received when the blobstore ignores the range", func(t *testing.T) {
+		blobstore, ranges := newFlakyBlobstore(t, bits, 4000, false)
+		cf := newDownloadTestClient(t, blobstore.URL, dropletRoute)
+
+		var buf bytes.Buffer
+		err := cf.Droplets.DownloadTo(context.Background(), "59c3d133-2b83-46f3-960e-7765a129aea4", &buf,
+			testDownloadOptions(nil))
+		require.NoError(t, err)
+		require.Equal(t, bits, buf.Bytes())
+		require.Len(t, *ranges, 2)
+	})
+
+	t.Run("fails when the bits do not match the checksum", func(t *testing.T) {
+		corrupt := append([]byte{}, bits...)
+		corrupt[10] = 'X'
+		blobstore, _ := newFlakyBlobstore(t, corrupt, 0, true)
+		cf := newDownloadTestClient(t, blobstore.URL, dropletRoute)
+
+		var buf bytes.Buffer
+		err := cf.Droplets.DownloadTo(context.Background(), "59c3d133-2b83-46f3-960e-7765a129aea4", &buf,
+			testDownloadOptions(nil))
+		var mismatch ChecksumMismatchError
+		require.ErrorAs(t, err, &mismatch)
+		require.Equal(t, hex.EncodeToString(sum[:]), mismatch.Expected)
+	})
+
+	t.Run("verifies a sha1 checksum", func(t *testing.T) {
+		sha1Sum := sha1.Sum(bits)
+		sha1Droplet := strings.Replace(g.Droplet().JSON, `"sha256"`, `"sha1"`, 1)
+		sha1Droplet = strings.Replace(sha1Droplet, emptySHA256, hex.EncodeToString(sha1Sum[:]), 1)
+		blobstore, _ := newFlakyBlobstore(t, bits, 0, true)
+		cf := newDownloadTestClient(t, blobstore.URL, testutil.MockRoute{
+			Method:   "GET",
+			Endpoint: "/v3/droplets/59c3d133-2b83-46f3-960e-7765a129aea4",
+			Output:   []string{sha1Droplet},
+			Status:   http.StatusOK,
+		})
+
+		var buf bytes.Buffer
+		err := cf.Droplets.DownloadTo(context.Background(), "59c3d133-2b83-46f3-960e-7765a129aea4", &buf,
+			testDownloadOptions(nil))
+		require.NoError(t, err)
+		require.Equal(t, bits, buf.Bytes())
+	})
+
+	t.Run("gives up after the resume attempts", func(t *testing.T) {
+		attempts := 0
+		blobstore := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			attempts++
+			w.Header().Set("Content-Length", strconv.Itoa(len(bits)))
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write(bits[:10])
+		}))
+		t.Cleanup(blobstore.Close)
+		cf := newDownloadTestClient(t, blobstore.URL, dropletRoute)
+
+		opts := testDownloadOptions(nil)
+		opts.ResumeAttempts = 2
+		err := cf.Droplets.DownloadTo(context.Background(), "59c3d133-2b83-46f3-960e-7765a129aea4", &bytes.Buffer{}, opts)
+		require.ErrorContains(t, err, "download interrupted")
+		require.Equal(t, 3, attempts)
+	})
+}
+
+func TestParseContentRange(t *testing.T) {
+	tests := []struct {
+		header string
+		start  int64
+		total  int64
+		err    bool
+	}{
+		{header: "bytes 100-199/200", start: 100, total: 200},
+		{header: "bytes 0-99/*", start: 0, total: -1},
+		{header: "bytes */200", err: true},
+		{header: "items 0-1/2", err: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			start, total, err := parseContentRange(tt.header)
+			if tt.err {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, fmt.Sprint(tt.start, tt.total), fmt.Sprint(start, total))
+		})
+	}
+}
@@ -2,6 +2,7 @@ package client
 
 import (
 	"context"
+	"fmt"
 	"io"
 	"net/url"
 
@@ -107,6 +108,24 @@ func (c *DropletClient) Download(ctx context.Context, guid string) (io.ReadClose
 	return c.client.download(ctx, path.Format("/v3/droplets/%s/download", guid))
 }
 
+// DownloadTo downloads the droplet's gzip compressed tarball to w and verifies it against the droplet's
+// sha256 checksum, returning a ChecksumMismatchError if the bits don't match. An interrupted download
+// is resumed from the blobstore where it left off
+func (c *DropletClient) DownloadTo(ctx context.Context, guid string, w io.Writer, opts *DownloadOptions) error {
+	droplet, err := c.Get(ctx, guid)
+	if err != nil {
+		return fmt.Errorf("error getting droplet %s: %w", guid, err)
+	}
+	if droplet.Checksum.Value == "" {
+		return fmt.Errorf("droplet %s has no checksum, it may not have finished staging", guid)
+	}
+	sum := &checksum{
+		Type:  droplet.Checksum.Type,
+		Value: droplet.Checksum.Value,
+	}
+	return c.client.downloadTo(ctx, path.Format("/v3/droplets/%s/download", guid), w, sum, opts)
+}
+
 // First returns the first droplet matching the options or an error when less than 1 match
 func (c *DropletClient) First(ctx context.Context, opts *DropletListOptions) (*resource.Droplet, error) {
 	return First[*DropletListOptions, *resource.Droplet](opts, func(opts *DropletListOptions) ([]*resource.Droplet, *Pager, error) {
@@ -157,7 +176,17 @@ func (c *DropletClient) ListAll(ctx context.Context, opts *DropletListOptions) (
 	if opts == nil {
 		opts = NewDropletListOptions()
 	}
-	return AutoPage[*DropletListOptions, *resource.Droplet](opts, func(opts *DropletListOptions) ([]*resource.Droplet, *Pager, error) {
+	return autoPage[*DropletListOptions, *resource.Droplet](ctx, c.client, opts, func(ctx context.Context, opts *DropletListOptions) ([]*resource.Droplet, *Pager, error) {
+		return c.List(ctx, opts)
+	})
+}
+
+// IterateAll lazily retrieves all droplets the user has access to, fetching each page only as it's needed
+func (c *DropletClient) IterateAll(ctx context.Context, opts *DropletListOptions) *Iterator[*DropletListOptions, *resource.Droplet] {
+	if opts == nil {
+		opts = NewDropletListOptions()
+	}
+	return Iterate[*DropletListOptions, *resource.Droplet](ctx, opts, func(opts *DropletListOptions) ([]*resource.Droplet, *Pager, error) {
 		return c.List(ctx, opts)
 	})
 }
@@ -181,7 +210,7 @@ func (c *DropletClient) ListForAppAll(ctx context.Context, appGUID string, opts
 	if opts == nil {
 		opts = NewDropletAppListOptions()
 	}
-	return AutoPage[*DropletAppListOptions, *resource.Droplet](opts, func(opts *DropletAppListOptions) ([]*resource.Droplet, *Pager, error) {
+	return autoPage[*DropletAppListOptions, *resource.Droplet](ctx, c.client, opts, func(ctx context.Context, opts *DropletAppListOptions) ([]*resource.Droplet, *Pager, error) {
 		return c.ListForApp(ctx, appGUID, opts)
 	})
 }
@@ -205,7 +234,7 @@ func (c *DropletClient) ListForPackageAll(ctx context.Context, packageGUID strin
 	if opts == nil {
 		opts = NewDropletPackageListOptions()
 	}
-	return AutoPage[*DropletPackageListOptions, *resource.Droplet](opts, func(opts *DropletPackageListOptions) ([]*resource.Droplet, *Pager, error) {
+	return autoPage[*DropletPackageListOptions, *resource.Droplet](ctx, c.client, opts, func(ctx context.Context, opts *DropletPackageListOptions) ([]*resource.Droplet, *Pager, error) {
 		return c.ListForPackage(ctx, packageGUID, opts)
 	})
 }
@@ -55,7 +55,17 @@ func (c *FeatureFlagClient) ListAll(ctx context.Context, opts *FeatureFlagListOp
 	if opts == nil {
 		opts = NewFeatureFlagListOptions()
 	}
-	return AutoPage[*FeatureFlagListOptions, *resource.FeatureFlag](opts, func(opts *FeatureFlagListOptions) ([]*resource.FeatureFlag, *Pager, error) {
+	return autoPage[*FeatureFlagListOptions, *resource.FeatureFlag](ctx, c.client, opts, func(ctx context.Context, opts *FeatureFlagListOptions) ([]*resource.FeatureFlag, *Pager, error) {
+		return c.List(ctx, opts)
+	})
+}
+
+// IterateAll lazily retrieves all feature flags, fetching each page only as it's needed
+func (c *FeatureFlagClient) IterateAll(ctx context.Context, opts *FeatureFlagListOptions) *Iterator[*FeatureFlagListOptions, *resource.FeatureFlag] {
+	if opts == nil {
+		opts = NewFeatureFlagListOptions()
+	}
+	return Iterate[*FeatureFlagListOptions, *resource.FeatureFlag](ctx, opts, func(opts *FeatureFlagListOptions) ([]*resource.FeatureFlag, *Pager, error) {
 		return c.List(ctx, opts)
 	})
 }
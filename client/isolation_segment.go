@@ -115,7 +115,17 @@ func (c *IsolationSegmentClient) ListAll(ctx context.Context, opts *IsolationSeg
 	if opts == nil {
 		opts = NewIsolationSegmentOptions()
 	}
-	return AutoPage[*IsolationSegmentListOptions, *resource.IsolationSegment](opts, func(opts *IsolationSegmentListOptions) ([]*resource.IsolationSegment, *Pager, error) {
+	return autoPage[*IsolationSegmentListOptions, *resource.IsolationSegment](ctx, c.client, opts, func(ctx context.Context, opts *IsolationSegmentListOptions) ([]*resource.IsolationSegment, *Pager, error) {
+		return c.List(ctx, opts)
+	})
+}
+
+// IterateAll lazily retrieves all isolation segments the user has access to, fetching each page only as it's needed
+func (c *IsolationSegmentClient) IterateAll(ctx context.Context, opts *IsolationSegmentListOptions) *Iterator[*IsolationSegmentListOptions, *resource.IsolationSegment] {
+	if opts == nil {
+		opts = NewIsolationSegmentOptions()
+	}
+	return Iterate[*IsolationSegmentListOptions, *resource.IsolationSegment](ctx, opts, func(opts *IsolationSegmentListOptions) ([]*resource.IsolationSegment, *Pager, error) {
 		return c.List(ctx, opts)
 	})
 }
@@ -21,20 +21,19 @@ func (c *JobClient) Get(ctx context.Context, guid string) (*resource.Job, error)
 
 // PollComplete waits until the job completes, fails, or times out
 func (c *JobClient) PollComplete(ctx context.Context, jobGUID string, opts *PollingOptions) error {
-	err := PollForStateOrTimeout(func() (string, error) {
-		job, err := c.Get(ctx, jobGUID)
-		if job != nil {
-			return string(job.State), err
+	var job *resource.Job
+	_, err := PollForState(ctx, func(ctx context.Context) (string, error) {
+		var err error
+		job, err = c.Get(ctx, jobGUID)
+		if err != nil {
+			return "", err
 		}
-		return "", err
-	}, string(resource.JobStateComplete), opts)
+		return string(job.State), nil
+	}, []string{string(resource.JobStateComplete)}, []string{string(resource.JobStateFailed)}, opts)
 
-	// attempt to return the underlying saved job error
-	if err == AsyncProcessFailedError {
-		job, _ := c.Get(ctx, jobGUID)
-		if job != nil && len(job.Errors) > 0 {
-			return job.Errors[0]
-		}
+	// return the underlying saved job error
+	if err == AsyncProcessFailedError && len(job.Errors) > 0 {
+		return job.Errors[0]
 	}
 	return err
 }
@@ -3,9 +3,15 @@ package client
 import (
 	"context"
 	"net/http"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/cloudfoundry/go-cfclient/v3/config"
+	"github.com/cloudfoundry/go-cfclient/v3/resource"
 	"github.com/cloudfoundry/go-cfclient/v3/testutil"
+
+	"github.com/stretchr/testify/require"
 )
 
 func TestJobs(t *testing.T) {
@@ -25,6 +31,47 @@ func TestJobs(t *testing.T) {
 				return c.Jobs.Get(context.Background(), "c33a5caf-77e0-4d6e-b587-5555d339bc9a")
 			},
 		},
+		{
+			Description: "Poll job complete",
+			Route: testutil.MockRoute{
+				Method:   "GET",
+				Endpoint: "/v3/jobs/c33a5caf-77e0-4d6e-b587-5555d339bc9a",
+				Output:   []string{g.Job("PROCESSING").JSON, g.Job("POLLING").JSON, job},
+				Status:   http.StatusOK},
+			Expected: `["PROCESSING","POLLING","COMPLETE"]`,
+			Action: func(c *Client, t *testing.T) (any, error) {
+				var states []string
+				opts := NewPollingOptions()
+				opts.CheckInterval = time.Millisecond
+				opts.OnState = func(state string) {
+					states = append(states, state)
+				}
+				err := c.Jobs.PollComplete(context.Background(), "c33a5caf-77e0-4d6e-b587-5555d339bc9a", opts)
+				return states, err
+			},
+		},
 	}
 	ExecuteTests(tests, t)
 }
+
+func TestPollCompleteFailed(t *testing.T) {
+	g := testutil.NewObjectJSONGenerator(1)
+	job := strings.Replace(g.Job("FAILED").JSON, `"errors": []`,
+		`"errors": [{"code": 10008, "title": "CF-UnprocessableEntity", "detail": "something went wrong"}]`, 1)
+	serverURL := testutil.Setup(testutil.MockRoute{
+		Method:   "GET",
+		Endpoint: "/v3/jobs/c33a5caf-77e0-4d6e-b587-5555d339bc9a",
+		Output:   g.Single(job),
+		Status:   http.StatusOK,
+	}, t)
+	defer testutil.Teardown()
+
+	cfg, _ := config.New(serverURL, config.Token("", "fake-refresh-token"))
+	c, err := New(cfg)
+	require.NoError(t, err)
+
+	err = c.Jobs.PollComplete(context.Background(), "c33a5caf-77e0-4d6e-b587-5555d339bc9a", nil)
+	var cfErr resource.CloudFoundryError
+	require.ErrorAs(t, err, &cfErr)
+	require.Equal(t, "something went wrong", cfErr.Detail)
+}
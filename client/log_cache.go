@@ -0,0 +1,153 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/cloudfoundry/go-cfclient/v3/internal/path"
+	"github.com/cloudfoundry/go-cfclient/v3/resource"
+)
+
+const (
+	DefaultLogCacheLimit = 100
+	MaxLogCacheLimit     = 1000
+)
+
+// LogCacheClient reads app logs and metrics from the log-cache service linked from the API root
+type LogCacheClient commonClient
+
+// LogCacheReadOptions log-cache read filters
+type LogCacheReadOptions struct {
+	StartTime     time.Time                  // oldest envelope timestamp to include, defaults to the beginning of the cache
+	EndTime       time.Time                  // envelope timestamp to read up to (exclusive), defaults to now
+	EnvelopeTypes []resource.LogEnvelopeType // only include envelopes of these types, defaults to all
+	Limit         int                        // max number of envelopes to return per read, log-cache caps this at 1000
+	Descending    bool                       // return the newest envelopes first
+}
+
+// NewLogCacheReadOptions creates new options to pass to read
+func NewLogCacheReadOptions() *LogCacheReadOptions {
+	return &LogCacheReadOptions{
+		Limit: DefaultLogCacheLimit,
+	}
+}
+
+func (o LogCacheReadOptions) ToQueryString() (url.Values, error) {
+	values := url.Values{}
+	if !o.StartTime.IsZero() {
+		values.Set("start_time", strconv.FormatInt(o.StartTime.UnixNano(), 10))
+	}
+	if !o.EndTime.IsZero() {
+		values.Set("end_time", strconv.FormatInt(o.EndTime.UnixNano(), 10))
+	}
+	for _, t := range o.EnvelopeTypes {
+		values.Add("envelope_types", t.String())
+	}
+	if o.Limit > 0 {
+		values.Set("limit", strconv.Itoa(o.Limit))
+	}
+	if o.Descending {
+		values.Set("descending", "true")
+	}
+	return values, nil
+}
+
+// Read retrieves the cached envelopes for the specified source ID, which for an app is the app GUID
+func (c *LogCacheClient) Read(ctx context.Context, sourceID string, opts *LogCacheReadOptions) ([]*resource.LogEnvelope, error) {
+	if opts == nil {
+		opts = NewLogCacheReadOptions()
+	}
+	readURL, err := c.readURL(ctx, sourceID)
+	if err != nil {
+		return nil, err
+	}
+	return c.read(ctx, readURL, opts)
+}
+
+// ReadAllForApp pages back through the cached history of the specified app and returns all the
+// envelopes matching the options, oldest first. The options limit is used as the page size, not as
+// a cap on the total number of envelopes returned. It returns an error rather than a truncated result if
+// more envelopes share a single timestamp than log-cache returns in one read
+func (c *LogCacheClient) ReadAllForApp(ctx context.Context, appGUID string, opts *LogCacheReadOptions) ([]*resource.LogEnvelope, error) {
+	if opts == nil {
+		opts = NewLogCacheReadOptions()
+	}
+	readURL, err := c.readURL(ctx, appGUID)
+	if err != nil {
+		return nil, err
+	}
+
+	// walk backwards from the end time so each page picks up where the last one left off
+	pageOpts := *opts
+	pageOpts.Descending = true
+	if pageOpts.Limit <= 0 || pageOpts.Limit > MaxLogCacheLimit {
+		pageOpts.Limit = MaxLogCacheLimit
+	}
+
+	var all []*resource.LogEnvelope
+	var boundary int64 // the oldest timestamp of the previous page
+	seen := 0          // the number of envelopes at the boundary timestamp already returned
+	for {
+		page, err := c.read(ctx, readURL, &pageOpts)
+		if err != nil {
+			return nil, err
+		}
+		// the end time includes the boundary timestamp, so skip the envelopes at it already returned
+		skip := 0
+		for skip < seen && skip < len(page) && page[skip].Timestamp == boundary {
+			skip++
+		}
+		all = append(all, page[skip:]...)
+		if len(page) < pageOpts.Limit {
+			break
+		}
+
+		// several envelopes can share a timestamp, so the next page has to include the oldest timestamp
+		// of this page to pick up any at that timestamp that didn't fit
+		boundary = page[len(page)-1].Timestamp
+		seen = 0
+		for i := len(page) - 1; i >= 0 && page[i].Timestamp == boundary; i-- {
+			seen++
+		}
+		if seen == len(page) {
+			// the whole page shares the timestamp so paging can't get past it, re-read it with a bigger
+			// page until it's exhausted
+			if pageOpts.Limit >= MaxLogCacheLimit {
+				return nil, fmt.Errorf("more than %d envelopes share the timestamp %d, the result would be truncated", MaxLogCacheLimit, boundary)
+			}
+			pageOpts.Limit = MaxLogCacheLimit
+		}
+		pageOpts.EndTime = time.Unix(0, boundary+1)
+	}
+
+	// reverse into chronological order
+	for i, j := 0, len(all)-1; i < j; i, j = i+1, j-1 {
+		all[i], all[j] = all[j], all[i]
+	}
+	return all, nil
+}
+
+func (c *LogCacheClient) read(ctx context.Context, readURL string, opts *LogCacheReadOptions) ([]*resource.LogEnvelope, error) {
+	params, err := opts.ToQueryString()
+	if err != nil {
+		return nil, err
+	}
+	if len(params) > 0 {
+		readURL = readURL + "?" + params.Encode()
+	}
+	var res resource.LogCacheRead
+	if err = c.client.executeExternal(ctx, http.MethodGet, readURL, nil, &res); err != nil {
+		return nil, err
+	}
+	return res.Envelopes.Batch, nil
+}
+
+func (c *LogCacheClient) readURL(ctx context.Context, sourceID string) (string, error) {
+	return c.client.Root.serviceURL(ctx, "log-cache", func(l *resource.RootLinks) resource.Link {
+		return l.LogCache
+	}, path.Format("/api/v1/read/%s", sourceID))
+}
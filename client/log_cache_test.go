@@ -0,0 +1,109 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/cloudfoundry/go-cfclient/v3/config"
+	"github.com/cloudfoundry/go-cfclient/v3/resource"
+	"github.com/cloudfoundry/go-cfclient/v3/testutil"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogCache(t *testing.T) {
+	g := testutil.NewObjectJSONGenerator(1)
+	appGUID := "1cb006ee-fb05-47e1-b541-c34179ddc446"
+	envelope := g.LogEnvelope(appGUID, "1581447006352020890").JSON
+	envelope2 := g.LogEnvelope(appGUID, "1581447006352020880").JSON
+	envelope3 := g.LogEnvelope(appGUID, "1581447006352020870").JSON
+	envelope4 := g.LogEnvelope(appGUID, "1581447006352020860").JSON
+
+	tests := []RouteTest{
+		{
+			Description: "Read envelopes for app",
+			Route: testutil.MockRoute{
+				Method:      "GET",
+				Endpoint:    "/api/v1/read/1cb006ee-fb05-47e1-b541-c34179ddc446",
+				Output:      []string{g.LogEnvelopeBatch(envelope, envelope2)},
+				Status:      http.StatusOK,
+				QueryString: "descending=true&end_time=1581447006352020900&envelope_types=LOG&envelope_types=EVENT&limit=2",
+			},
+			Expected: g.Array(envelope, envelope2),
+			Action: func(c *Client, t *testing.T) (any, error) {
+				opts := NewLogCacheReadOptions()
+				opts.EndTime = time.Unix(0, 1581447006352020900)
+				opts.EnvelopeTypes = []resource.LogEnvelopeType{resource.LogEnvelopeTypeLog, resource.LogEnvelopeTypeEvent}
+				opts.Limit = 2
+				opts.Descending = true
+				return c.LogCache.Read(context.Background(), appGUID, opts)
+			},
+		},
+		{
+			Description: "Read all envelopes for app",
+			Route: testutil.MockRoute{
+				Method:   "GET",
+				Endpoint: "/api/v1/read/1cb006ee-fb05-47e1-b541-c34179ddc446",
+				Output: []string{
+					g.LogEnvelopeBatch(envelope, envelope2),
+					g.LogEnvelopeBatch(envelope3),
+				},
+				Status: http.StatusOK,
+			},
+			Expected: g.Array(envelope3, envelope2, envelope),
+			Action: func(c *Client, t *testing.T) (any, error) {
+				opts := NewLogCacheReadOptions()
+				opts.Limit = 2
+				return c.LogCache.ReadAllForApp(context.Background(), appGUID, opts)
+			},
+		},
+		{
+			Description: "Read all envelopes for app sharing a timestamp across pages",
+			Route: testutil.MockRoute{
+				Method:   "GET",
+				Endpoint: "/api/v1/read/1cb006ee-fb05-47e1-b541-c34179ddc446",
+				Output: []string{
+					g.LogEnvelopeBatch(envelope, envelope2),
+					g.LogEnvelopeBatch(envelope2, envelope2),
+					g.LogEnvelopeBatch(envelope2, envelope2, envelope2, envelope3, envelope4),
+				},
+				Status: http.StatusOK,
+			},
+			Expected: g.Array(envelope4, envelope3, envelope2, envelope2, envelope2, envelope),
+			Action: func(c *Client, t *testing.T) (any, error) {
+				opts := NewLogCacheReadOptions()
+				opts.Limit = 2
+				return c.LogCache.ReadAllForApp(context.Background(), appGUID, opts)
+			},
+		},
+	}
+	ExecuteTests(tests, t)
+}
+
+func TestLogCacheReadAllForAppTruncated(t *testing.T) {
+	g := testutil.NewObjectJSONGenerator(1)
+	appGUID := "1cb006ee-fb05-47e1-b541-c34179ddc446"
+	envelopes := make([]string, MaxLogCacheLimit)
+	for i := range envelopes {
+		envelopes[i] = g.LogEnvelope(appGUID, "1581447006352020890").JSON
+	}
+	serverURL := testutil.SetupMultiple([]testutil.MockRoute{
+		{
+			Method:   "GET",
+			Endpoint: "/api/v1/read/1cb006ee-fb05-47e1-b541-c34179ddc446",
+			Output:   []string{g.LogEnvelopeBatch(envelopes...)},
+			Status:   http.StatusOK,
+		},
+	}, t)
+	defer testutil.Teardown()
+	c, _ := config.New(serverURL, config.Token("", "fake-refresh-token"))
+	cf, err := New(c)
+	require.NoError(t, err)
+
+	opts := NewLogCacheReadOptions()
+	opts.Limit = MaxLogCacheLimit
+	_, err = cf.LogCache.ReadAllForApp(context.Background(), appGUID, opts)
+	require.ErrorContains(t, err, "more than 1000 envelopes share the timestamp 1581447006352020890")
+}
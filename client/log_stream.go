@@ -0,0 +1,192 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	internal "github.com/cloudfoundry/go-cfclient/v3/internal/http"
+	"github.com/cloudfoundry/go-cfclient/v3/internal/ios"
+	"github.com/cloudfoundry/go-cfclient/v3/resource"
+)
+
+const (
+	DefaultLogStreamMinBackoff = 500 * time.Millisecond
+	DefaultLogStreamMaxBackoff = 30 * time.Second
+)
+
+// LogStreamClient follows live app logs and metrics from the log-stream (RLP gateway) service linked
+// from the API root
+type LogStreamClient commonClient
+
+// LogStreamOptions log-stream selectors and reconnect settings
+type LogStreamOptions struct {
+	EnvelopeTypes []resource.LogEnvelopeType // the envelope types to stream, defaults to logs only
+	ShardID       string                     // streams sharing a shard ID split the envelopes between them
+
+	MinBackoff time.Duration // the initial delay before reconnecting a dropped stream
+	MaxBackoff time.Duration // the max delay between reconnect attempts
+}
+
+// NewLogStreamOptions creates new options to pass to tail
+func NewLogStreamOptions() *LogStreamOptions {
+	return &LogStreamOptions{
+		EnvelopeTypes: []resource.LogEnvelopeType{resource.LogEnvelopeTypeLog},
+		MinBackoff:    DefaultLogStreamMinBackoff,
+		MaxBackoff:    DefaultLogStreamMaxBackoff,
+	}
+}
+
+func (o LogStreamOptions) ToQueryString() (url.Values, error) {
+	values := url.Values{}
+	for _, t := range o.EnvelopeTypes {
+		values.Set(strings.ToLower(t.String()), "")
+	}
+	if o.ShardID != "" {
+		values.Set("shard_id", o.ShardID)
+	}
+	return values, nil
+}
+
+// Tail streams the live envelopes of the specified app until the context is cancelled
+//
+// Dropped connections are re-established with exponential backoff and any stream errors are sent on the
+// error channel, so the caller must drain both channels. Tail stops on errors it can't recover from, like
+// being forbidden from reading the app's logs. Both channels are closed once streaming stops.
+func (c *LogStreamClient) Tail(ctx context.Context, appGUID string, opts *LogStreamOptions) (<-chan *resource.LogEnvelope, <-chan error) {
+	if opts == nil {
+		opts = NewLogStreamOptions()
+	}
+	envelopes := make(chan *resource.LogEnvelope)
+	errs := make(chan error)
+
+	go func() {
+		defer close(errs)
+		defer close(envelopes)
+
+		sendErr := func(err error) bool {
+			select {
+			case errs <- err:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		params, err := opts.ToQueryString()
+		if err != nil {
+			sendErr(err)
+			return
+		}
+		params.Set("source_id", appGUID)
+		streamURL, err := c.client.Root.serviceURL(ctx, "log-stream", func(l *resource.RootLinks) resource.Link {
+			return l.LogStream
+		}, "/v2/read?"+params.Encode())
+		if err != nil {
+			sendErr(err)
+			return
+		}
+
+		backoff := opts.MinBackoff
+		for {
+			received, err := c.stream(ctx, streamURL, envelopes)
+			if ctx.Err() != nil {
+				return
+			}
+			if err != nil {
+				if !sendErr(err) || !isRetryableStreamError(err) {
+					return
+				}
+			}
+			if received {
+				backoff = opts.MinBackoff
+			}
+
+			select {
+			case <-time.After(backoff):
+			case <-ctx.Done():
+				return
+			}
+			if backoff *= 2; backoff > opts.MaxBackoff {
+				backoff = opts.MaxBackoff
+			}
+		}
+	}()
+
+	return envelopes, errs
+}
+
+// stream connects to the log-stream and sends envelopes until the connection is dropped or closed by the server
+func (c *LogStreamClient) stream(ctx context.Context, streamURL string, envelopes chan<- *resource.LogEnvelope) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, streamURL, nil)
+	if err != nil {
+		return false, fmt.Errorf("error creating log stream request: %w", err)
+	}
+	req.Header.Set("Accept", "text/event-stream")
+	req.Header.Set("User-Agent", c.client.UserAgent())
+
+	// the configured request timeout would cut off the long-lived stream, so use the
+	// auth transport directly and rely on the context for cancellation
+	authClient := c.client.HTTPAuthClient()
+	streamClient := &http.Client{
+		Transport:     authClient.Transport,
+		CheckRedirect: authClient.CheckRedirect,
+		Jar:           authClient.Jar,
+	}
+	resp, err := streamClient.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("error connecting to the log stream: %w", err)
+	}
+	defer ios.Close(resp.Body)
+	if !internal.IsStatusSuccess(resp.StatusCode) {
+		return false, internal.DecodeError(resp)
+	}
+
+	received := false
+	reader := internal.NewEventStreamReader(resp.Body)
+	for {
+		event, err := reader.Next()
+		if err == io.EOF {
+			return received, nil
+		}
+		if err != nil {
+			return received, fmt.Errorf("error reading the log stream: %w", err)
+		}
+
+		if event.Name == "closing" {
+			return received, nil
+		}
+		if event.Name != "" && event.Name != "message" {
+			continue // heartbeat
+		}
+
+		var batch resource.LogEnvelopeBatch
+		if err = json.Unmarshal([]byte(event.Data), &batch); err != nil {
+			return received, fmt.Errorf("error decoding log stream envelopes: %w", err)
+		}
+		for _, e := range batch.Batch {
+			select {
+			case envelopes <- e:
+				received = true
+			case <-ctx.Done():
+				return received, ctx.Err()
+			}
+		}
+	}
+}
+
+// isRetryableStreamError returns false for client errors that reconnecting won't fix
+func isRetryableStreamError(err error) bool {
+	var httpErr resource.CloudFoundryHTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.StatusCode == http.StatusTooManyRequests || httpErr.StatusCode >= http.StatusInternalServerError
+	}
+	var cfErr resource.CloudFoundryError
+	return !errors.As(err, &cfErr)
+}
@@ -0,0 +1,76 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/cloudfoundry/go-cfclient/v3/resource"
+	"github.com/cloudfoundry/go-cfclient/v3/testutil"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogStream(t *testing.T) {
+	g := testutil.NewObjectJSONGenerator(1)
+	appGUID := "1cb006ee-fb05-47e1-b541-c34179ddc446"
+	envelope := g.LogEnvelope(appGUID, "1581447006352020870").JSON
+	envelope2 := g.LogEnvelope(appGUID, "1581447006352020880").JSON
+	envelope3 := g.LogEnvelope(appGUID, "1581447006352020890").JSON
+
+	// each event's data must be on a single line
+	batch := func(envelopesJSON ...string) string {
+		var buf bytes.Buffer
+		_ = json.Compact(&buf, []byte(`{"batch":`+g.Array(envelopesJSON...)+"}"))
+		return "data: " + buf.String() + "\n\n"
+	}
+	stream := batch(envelope, envelope2) + "event: heartbeat\ndata: 1581447006\n\n" + batch(envelope3)
+
+	tests := []RouteTest{
+		{
+			Description: "Tail app logs",
+			Route: testutil.MockRoute{
+				Method:      "GET",
+				Endpoint:    "/v2/read",
+				Output:      []string{stream},
+				Status:      http.StatusOK,
+				QueryString: "log=&source_id=1cb006ee-fb05-47e1-b541-c34179ddc446",
+			},
+			Expected: g.Array(envelope, envelope2, envelope3),
+			Action: func(c *Client, t *testing.T) (any, error) {
+				ctx, cancel := context.WithCancel(context.Background())
+				defer cancel()
+
+				opts := NewLogStreamOptions()
+				opts.MinBackoff = time.Minute
+				envelopes, errs := c.LogStream.Tail(ctx, appGUID, opts)
+
+				var received []*resource.LogEnvelope
+				for envelopes != nil || errs != nil {
+					select {
+					case e, ok := <-envelopes:
+						if !ok {
+							envelopes = nil
+							continue
+						}
+						received = append(received, e)
+						if len(received) == 3 {
+							cancel()
+						}
+					case err, ok := <-errs:
+						if !ok {
+							errs = nil
+							continue
+						}
+						require.NoError(t, err)
+					}
+				}
+				return received, nil
+			},
+		},
+	}
+	ExecuteTests(tests, t)
+}
@@ -13,6 +13,7 @@ import (
 func TestManifests(t *testing.T) {
 	g := testutil.NewObjectJSONGenerator(1)
 	manifest := g.Manifest().JSON
+	diff := g.ManifestDiff().JSON
 
 	tests := []RouteTest{
 		{
@@ -29,6 +30,21 @@ func TestManifests(t *testing.T) {
 				return nil, nil
 			},
 		},
+		{
+			Description: "Manifest diff",
+			Route: testutil.MockRoute{
+				Method:   "POST-FILE",
+				Endpoint: "/v3/spaces/8cb4cb8f-1b8d-4e23-9b8e-ae3a4e2e6a76/manifest_diff",
+				Output:   g.Single(diff),
+				Status:   http.StatusCreated,
+				PostForm: "name: spring-music",
+			},
+			Expected: diff,
+			Action: func(c *Client, t *testing.T) (any, error) {
+				return c.Manifests.ManifestDiff(context.Background(), "8cb4cb8f-1b8d-4e23-9b8e-ae3a4e2e6a76",
+					"applications:\n- name: spring-music\n")
+			},
+		},
 	}
 	ExecuteTests(tests, t)
 }
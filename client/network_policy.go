@@ -0,0 +1,155 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/cloudfoundry/go-cfclient/v3/resource"
+)
+
+// maxNetworkPolicyFilterGUIDs limits the number of app GUIDs sent in a single policy server query
+// to keep the request URL to a reasonable length
+const maxNetworkPolicyFilterGUIDs = 100
+
+// NetworkPolicyClient manages container-to-container networking policies via the policy
+// server linked from the API root
+type NetworkPolicyClient commonClient
+
+// NetworkPolicyListOptions list filters
+type NetworkPolicyListOptions struct {
+	AppGUIDs         Filter // list of app guids that are either the source or the destination
+	SourceGUIDs      Filter // list of source app guids to filter by
+	DestinationGUIDs Filter // list of destination app guids to filter by
+	SpaceGUIDs       Filter // list of space guids whose apps are either the source or the destination
+}
+
+// NewNetworkPolicyListOptions creates new options to pass to list
+func NewNetworkPolicyListOptions() *NetworkPolicyListOptions {
+	return &NetworkPolicyListOptions{}
+}
+
+func (o NetworkPolicyListOptions) ToQueryString() (url.Values, error) {
+	values := url.Values{}
+	if err := o.AppGUIDs.Serialize(values, "id"); err != nil {
+		return nil, err
+	}
+	if err := o.SourceGUIDs.Serialize(values, "source_id"); err != nil {
+		return nil, err
+	}
+	if err := o.DestinationGUIDs.Serialize(values, "dest_id"); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+// Create the specified policies
+func (c *NetworkPolicyClient) Create(ctx context.Context, policies ...*resource.NetworkPolicy) error {
+	policiesURL, err := c.policiesURL(ctx, "/policies")
+	if err != nil {
+		return err
+	}
+	r := &resource.NetworkPolicies{Policies: policies}
+	return c.client.executeExternal(ctx, http.MethodPost, policiesURL, r, nil)
+}
+
+// Delete the specified policies
+func (c *NetworkPolicyClient) Delete(ctx context.Context, policies ...*resource.NetworkPolicy) error {
+	policiesURL, err := c.policiesURL(ctx, "/policies/delete")
+	if err != nil {
+		return err
+	}
+	r := &resource.NetworkPolicies{Policies: policies}
+	return c.client.executeExternal(ctx, http.MethodPost, policiesURL, r, nil)
+}
+
+// List retrieves all the policies the user has access to
+//
+// The policy server does not paginate its results, instead any app or space filters are split into
+// batches which are queried one after another.
+func (c *NetworkPolicyClient) List(ctx context.Context, opts *NetworkPolicyListOptions) ([]*resource.NetworkPolicy, error) {
+	if opts == nil {
+		opts = NewNetworkPolicyListOptions()
+	}
+	policiesURL, err := c.policiesURL(ctx, "/policies")
+	if err != nil {
+		return nil, err
+	}
+
+	appGUIDs := append([]string{}, opts.AppGUIDs.Values...)
+	if len(opts.SpaceGUIDs.Values) > 0 {
+		spaceAppGUIDs, err := c.appGUIDsInSpaces(ctx, opts.SpaceGUIDs.Values)
+		if err != nil {
+			return nil, err
+		}
+		// an empty id filter would match every policy
+		if len(appGUIDs) == 0 && len(spaceAppGUIDs) == 0 {
+			return nil, nil
+		}
+		appGUIDs = append(appGUIDs, spaceAppGUIDs...)
+	}
+
+	batchOpts := *opts
+	if len(appGUIDs) == 0 {
+		return c.list(ctx, policiesURL, &batchOpts)
+	}
+
+	// policies between apps in different batches are returned for each batch, so de-duplicate them
+	var all []*resource.NetworkPolicy
+	seen := make(map[resource.NetworkPolicy]bool)
+	for start := 0; start < len(appGUIDs); start += maxNetworkPolicyFilterGUIDs {
+		end := start + maxNetworkPolicyFilterGUIDs
+		if end > len(appGUIDs) {
+			end = len(appGUIDs)
+		}
+		batchOpts.AppGUIDs.EqualTo(appGUIDs[start:end]...)
+		policies, err := c.list(ctx, policiesURL, &batchOpts)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range policies {
+			if !seen[*p] {
+				seen[*p] = true
+				all = append(all, p)
+			}
+		}
+	}
+	return all, nil
+}
+
+func (c *NetworkPolicyClient) list(ctx context.Context, policiesURL string, opts *NetworkPolicyListOptions) ([]*resource.NetworkPolicy, error) {
+	params, err := opts.ToQueryString()
+	if err != nil {
+		return nil, fmt.Errorf("error while generate query params: %w", err)
+	}
+	if len(params) > 0 {
+		policiesURL = policiesURL + "?" + params.Encode()
+	}
+	var res resource.NetworkPolicyList
+	if err = c.client.executeExternal(ctx, http.MethodGet, policiesURL, nil, &res); err != nil {
+		return nil, err
+	}
+	return res.Policies, nil
+}
+
+func (c *NetworkPolicyClient) appGUIDsInSpaces(ctx context.Context, spaceGUIDs []string) ([]string, error) {
+	opts := NewAppListOptions()
+	opts.SpaceGUIDs.EqualTo(spaceGUIDs...)
+	apps, err := c.client.Applications.ListAll(ctx, opts)
+	if err != nil {
+		return nil, fmt.Errorf("error listing apps in spaces %s: %w", strings.Join(spaceGUIDs, ","), err)
+	}
+	guids := make([]string, len(apps))
+	for i, app := range apps {
+		guids[i] = app.GUID
+	}
+	return guids, nil
+}
+
+func (c *NetworkPolicyClient) policiesURL(ctx context.Context, urlPath string) (string, error) {
+	return c.client.Root.serviceURL(ctx, "network policy", func(l *resource.RootLinks) resource.Link {
+		return l.NetworkPolicyV1
+	}, urlPath)
+}
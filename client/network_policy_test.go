@@ -0,0 +1,80 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/cloudfoundry/go-cfclient/v3/resource"
+	"github.com/cloudfoundry/go-cfclient/v3/testutil"
+)
+
+func TestNetworkPolicies(t *testing.T) {
+	g := testutil.NewObjectJSONGenerator(1)
+	policy := g.NetworkPolicy("1cb006ee-fb05-47e1-b541-c34179ddc446", "8d47b68e-c2fb-42b3-8b6c-0d2d1d8e3c21").JSON
+	policy2 := g.NetworkPolicy("8d47b68e-c2fb-42b3-8b6c-0d2d1d8e3c21", "1cb006ee-fb05-47e1-b541-c34179ddc446").JSON
+
+	tests := []RouteTest{
+		{
+			Description: "Create network policy",
+			Route: testutil.MockRoute{
+				Method:   "POST",
+				Endpoint: "/networking/v1/external/policies",
+				Output:   []string{"{}"},
+				Status:   http.StatusOK,
+				PostForm: `{"policies":[` + policy + `]}`,
+			},
+			Action: func(c *Client, t *testing.T) (any, error) {
+				p := resource.NewNetworkPolicy("1cb006ee-fb05-47e1-b541-c34179ddc446",
+					"8d47b68e-c2fb-42b3-8b6c-0d2d1d8e3c21", resource.NetworkPolicyProtocolTCP, 8080, 8090)
+				return nil, c.NetworkPolicies.Create(context.Background(), p)
+			},
+		},
+		{
+			Description: "Delete network policy",
+			Route: testutil.MockRoute{
+				Method:   "POST",
+				Endpoint: "/networking/v1/external/policies/delete",
+				Output:   []string{"{}"},
+				Status:   http.StatusOK,
+				PostForm: `{"policies":[` + policy + `]}`,
+			},
+			Action: func(c *Client, t *testing.T) (any, error) {
+				p := resource.NewNetworkPolicy("1cb006ee-fb05-47e1-b541-c34179ddc446",
+					"8d47b68e-c2fb-42b3-8b6c-0d2d1d8e3c21", resource.NetworkPolicyProtocolTCP, 8080, 8090)
+				return nil, c.NetworkPolicies.Delete(context.Background(), p)
+			},
+		},
+		{
+			Description: "List all network policies",
+			Route: testutil.MockRoute{
+				Method:   "GET",
+				Endpoint: "/networking/v1/external/policies",
+				Output:   []string{g.NetworkPolicies(policy, policy2)},
+				Status:   http.StatusOK,
+			},
+			Expected: g.Array(policy, policy2),
+			Action: func(c *Client, t *testing.T) (any, error) {
+				return c.NetworkPolicies.List(context.Background(), nil)
+			},
+		},
+		{
+			Description: "List network policies for source app",
+			Route: testutil.MockRoute{
+				Method:      "GET",
+				Endpoint:    "/networking/v1/external/policies",
+				Output:      []string{g.NetworkPolicies(policy)},
+				Status:      http.StatusOK,
+				QueryString: "id=8d47b68e-c2fb-42b3-8b6c-0d2d1d8e3c21&source_id=1cb006ee-fb05-47e1-b541-c34179ddc446",
+			},
+			Expected: g.Array(policy),
+			Action: func(c *Client, t *testing.T) (any, error) {
+				opts := NewNetworkPolicyListOptions()
+				opts.AppGUIDs.EqualTo("8d47b68e-c2fb-42b3-8b6c-0d2d1d8e3c21")
+				opts.SourceGUIDs.EqualTo("1cb006ee-fb05-47e1-b541-c34179ddc446")
+				return c.NetworkPolicies.List(context.Background(), opts)
+			},
+		},
+	}
+	ExecuteTests(tests, t)
+}
@@ -136,7 +136,17 @@ func (c *OrganizationClient) ListAll(ctx context.Context, opts *OrganizationList
 	if opts == nil {
 		opts = NewOrganizationListOptions()
 	}
-	return AutoPage[*OrganizationListOptions, *resource.Organization](opts, func(opts *OrganizationListOptions) ([]*resource.Organization, *Pager, error) {
+	return autoPage[*OrganizationListOptions, *resource.Organization](ctx, c.client, opts, func(ctx context.Context, opts *OrganizationListOptions) ([]*resource.Organization, *Pager, error) {
+		return c.List(ctx, opts)
+	})
+}
+
+// IterateAll lazily retrieves all organizations the user has access to, fetching each page only as it's needed
+func (c *OrganizationClient) IterateAll(ctx context.Context, opts *OrganizationListOptions) *Iterator[*OrganizationListOptions, *resource.Organization] {
+	if opts == nil {
+		opts = NewOrganizationListOptions()
+	}
+	return Iterate[*OrganizationListOptions, *resource.Organization](ctx, opts, func(opts *OrganizationListOptions) ([]*resource.Organization, *Pager, error) {
 		return c.List(ctx, opts)
 	})
 }
@@ -160,7 +170,7 @@ func (c *OrganizationClient) ListForIsolationSegmentAll(ctx context.Context, iso
 	if opts == nil {
 		opts = NewOrganizationListOptions()
 	}
-	return AutoPage[*OrganizationListOptions, *resource.Organization](opts, func(opts *OrganizationListOptions) ([]*resource.Organization, *Pager, error) {
+	return autoPage[*OrganizationListOptions, *resource.Organization](ctx, c.client, opts, func(ctx context.Context, opts *OrganizationListOptions) ([]*resource.Organization, *Pager, error) {
 		return c.ListForIsolationSegment(ctx, isolationSegmentGUID, opts)
 	})
 }
@@ -184,7 +194,7 @@ func (c *OrganizationClient) ListUsersAll(ctx context.Context, guid string, opts
 	if opts == nil {
 		opts = NewUserListOptions()
 	}
-	return AutoPage[*UserListOptions, *resource.User](opts, func(opts *UserListOptions) ([]*resource.User, *Pager, error) {
+	return autoPage[*UserListOptions, *resource.User](ctx, c.client, opts, func(ctx context.Context, opts *UserListOptions) ([]*resource.User, *Pager, error) {
 		return c.ListUsers(ctx, guid, opts)
 	})
 }
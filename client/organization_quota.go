@@ -97,7 +97,17 @@ func (c *OrganizationQuotaClient) ListAll(ctx context.Context, opts *Organizatio
 	if opts == nil {
 		opts = NewOrganizationQuotaListOptions()
 	}
-	return AutoPage[*OrganizationQuotaListOptions, *resource.OrganizationQuota](opts, func(opts *OrganizationQuotaListOptions) ([]*resource.OrganizationQuota, *Pager, error) {
+	return autoPage[*OrganizationQuotaListOptions, *resource.OrganizationQuota](ctx, c.client, opts, func(ctx context.Context, opts *OrganizationQuotaListOptions) ([]*resource.OrganizationQuota, *Pager, error) {
+		return c.List(ctx, opts)
+	})
+}
+
+// IterateAll lazily retrieves all organization quotas the user has access to, fetching each page only as it's needed
+func (c *OrganizationQuotaClient) IterateAll(ctx context.Context, opts *OrganizationQuotaListOptions) *Iterator[*OrganizationQuotaListOptions, *resource.OrganizationQuota] {
+	if opts == nil {
+		opts = NewOrganizationQuotaListOptions()
+	}
+	return Iterate[*OrganizationQuotaListOptions, *resource.OrganizationQuota](ctx, opts, func(opts *OrganizationQuotaListOptions) ([]*resource.OrganizationQuota, *Pager, error) {
 		return c.List(ctx, opts)
 	})
 }
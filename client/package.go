@@ -2,6 +2,8 @@ package client
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"io"
 	"net/url"
 
@@ -70,6 +72,24 @@ func (c *PackageClient) Download(ctx context.Context, guid string) (io.ReadClose
 	return c.client.download(ctx, path.Format("/v3/packages/%s/download", guid))
 }
 
+// DownloadTo downloads the bits of an existing package to w and verifies them against the package's
+// checksum, returning a ChecksumMismatchError if the bits don't match. An interrupted download is
+// resumed from the blobstore where it left off
+func (c *PackageClient) DownloadTo(ctx context.Context, guid string, w io.Writer, opts *DownloadOptions) error {
+	pkg, err := c.Get(ctx, guid)
+	if err != nil {
+		return fmt.Errorf("error getting package %s: %w", guid, err)
+	}
+	if pkg.Data.Bits == nil || pkg.Data.Bits.Checksum.Value == nil {
+		return fmt.Errorf("package %s has no bits to download", guid)
+	}
+	sum := &checksum{
+		Type:  pkg.Data.Bits.Checksum.Type,
+		Value: *pkg.Data.Bits.Checksum.Value,
+	}
+	return c.client.downloadTo(ctx, path.Format("/v3/packages/%s/download", guid), w, sum, opts)
+}
+
 // First returns the first package matching the options or an error when less than 1 match
 func (c *PackageClient) First(ctx context.Context, opts *PackageListOptions) (*resource.Package, error) {
 	return First[*PackageListOptions, *resource.Package](opts, func(opts *PackageListOptions) ([]*resource.Package, *Pager, error) {
@@ -112,7 +132,17 @@ func (c *PackageClient) ListAll(ctx context.Context, opts *PackageListOptions) (
 	if opts == nil {
 		opts = NewPackageListOptions()
 	}
-	return AutoPage[*PackageListOptions, *resource.Package](opts, func(opts *PackageListOptions) ([]*resource.Package, *Pager, error) {
+	return autoPage[*PackageListOptions, *resource.Package](ctx, c.client, opts, func(ctx context.Context, opts *PackageListOptions) ([]*resource.Package, *Pager, error) {
+		return c.List(ctx, opts)
+	})
+}
+
+// IterateAll lazily retrieves all packages the user has access to, fetching each page only as it's needed
+func (c *PackageClient) IterateAll(ctx context.Context, opts *PackageListOptions) *Iterator[*PackageListOptions, *resource.Package] {
+	if opts == nil {
+		opts = NewPackageListOptions()
+	}
+	return Iterate[*PackageListOptions, *resource.Package](ctx, opts, func(opts *PackageListOptions) ([]*resource.Package, *Pager, error) {
 		return c.List(ctx, opts)
 	})
 }
@@ -136,20 +166,22 @@ func (c *PackageClient) ListForAppAll(ctx context.Context, appGUID string, opts
 	if opts == nil {
 		opts = NewPackageListOptions()
 	}
-	return AutoPage[*PackageListOptions, *resource.Package](opts, func(opts *PackageListOptions) ([]*resource.Package, *Pager, error) {
+	return autoPage[*PackageListOptions, *resource.Package](ctx, c.client, opts, func(ctx context.Context, opts *PackageListOptions) ([]*resource.Package, *Pager, error) {
 		return c.ListForApp(ctx, appGUID, opts)
 	})
 }
 
-// PollReady waits until the package is ready, fails, or times out
+// PollReady waits until the package is ready, fails, expires or times out
 func (c *PackageClient) PollReady(ctx context.Context, guid string, opts *PollingOptions) error {
-	return PollForStateOrTimeout(func() (string, error) {
+	_, err := PollForState(ctx, func(ctx context.Context) (string, error) {
 		pkg, err := c.Get(ctx, guid)
-		if pkg != nil {
-			return string(pkg.State), err
+		if err != nil {
+			return "", err
 		}
-		return "", err
-	}, string(resource.PackageStateReady), opts)
+		return string(pkg.State), nil
+	}, []string{string(resource.PackageStateReady)},
+		[]string{string(resource.PackageStateFailed), string(resource.PackageStateExpired)}, opts)
+	return err
 }
 
 // Single returns a single package matching the options or an error if not exactly 1 match
@@ -183,3 +215,26 @@ func (c *PackageClient) Upload(ctx context.Context, guid string, zipFile io.Read
 	_, err := c.client.postFileUpload(ctx, p, "bits", "package.zip", zipFile, &pkg)
 	return &pkg, err
 }
+
+// UploadWithResources uploads the zip file of an app's files that aren't already cached by CF, along
+// with the resources previously matched by ResourceMatchClient.Create which CF copies from its cache.
+// The zip file may be nil if every file was matched.
+func (c *PackageClient) UploadWithResources(ctx context.Context, guid string, resources []resource.ResourceMatch, zipFile io.Reader) (*resource.Package, error) {
+	if resources == nil {
+		resources = []resource.ResourceMatch{}
+	}
+	resourcesJSON, err := json.Marshal(resources)
+	if err != nil {
+		return nil, fmt.Errorf("error marshalling package resources: %w", err)
+	}
+	p := path.Format("/v3/packages/%s/upload", guid)
+	fields := map[string]string{
+		"resources": string(resourcesJSON),
+	}
+	var pkg resource.Package
+	_, err = c.client.postMultipartUpload(ctx, p, fields, "bits", "package.zip", zipFile, &pkg)
+	if err != nil {
+		return nil, err
+	}
+	return &pkg, nil
+}
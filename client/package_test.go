@@ -8,6 +8,7 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/cloudfoundry/go-cfclient/v3/resource"
 	"github.com/cloudfoundry/go-cfclient/v3/testutil"
@@ -138,6 +139,20 @@ func TestPackages(t *testing.T) {
 				return c.Packages.Get(context.Background(), "66e89f29-475e-4baf-9675-40c6096c017b")
 			},
 		},
+		{
+			Description: "Poll package ready",
+			Route: testutil.MockRoute{
+				Method:   "GET",
+				Endpoint: "/v3/packages/66e89f29-475e-4baf-9675-40c6096c017b",
+				Output:   []string{g.Package("PROCESSING_UPLOAD").JSON, g.Package("READY").JSON},
+				Status:   http.StatusOK,
+			},
+			Action: func(c *Client, t *testing.T) (any, error) {
+				opts := NewPollingOptions()
+				opts.CheckInterval = time.Millisecond
+				return nil, c.Packages.PollReady(context.Background(), "66e89f29-475e-4baf-9675-40c6096c017b", opts)
+			},
+		},
 		{
 			Description: "List first page of packages",
 			Route: testutil.MockRoute{
@@ -218,6 +233,29 @@ func TestPackages(t *testing.T) {
 				return c.Packages.Upload(context.Background(), "8d1f1d2e-08b1-4a10-a8df-471a1418cb8b", zipFile)
 			},
 		},
+		{
+			Description: "Upload package with resources",
+			Route: testutil.MockRoute{
+				Method:   "POST-FILE",
+				Endpoint: "/v3/packages/8d1f1d2e-08b1-4a10-a8df-471a1418cb8b/upload",
+				Output:   g.Single(pkg),
+				Status:   http.StatusOK,
+				PostForm: `[{"checksum":{"value":"002d760bea1be268e27077412e11a320d0f164d3"},"size_in_bytes":36,"path":"C/path/to/file","mode":"0644"}]`,
+			},
+			Expected: pkg,
+			Action: func(c *Client, t *testing.T) (any, error) {
+				resources := []resource.ResourceMatch{
+					{
+						Checksum:    resource.ResourceMatchChecksum{Value: "002d760bea1be268e27077412e11a320d0f164d3"},
+						SizeInBytes: 36,
+						Path:        "C/path/to/file",
+						Mode:        "0644",
+					},
+				}
+				zipFile := strings.NewReader("package")
+				return c.Packages.UploadWithResources(context.Background(), "8d1f1d2e-08b1-4a10-a8df-471a1418cb8b", resources, zipFile)
+			},
+		},
 	}
 	ExecuteTests(tests, t)
 }
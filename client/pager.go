@@ -1,7 +1,12 @@
 package client
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
 
 	"github.com/cloudfoundry/go-cfclient/v3/internal/path"
 	"github.com/cloudfoundry/go-cfclient/v3/resource"
@@ -72,6 +77,210 @@ func AutoPage[T ListOptioner, R any](opts T, list ListFunc[T, R]) ([]R, error) {
 	return all, nil
 }
 
+// ContextListFunc is a ListFunc that takes the context to list with
+type ContextListFunc[T ListOptioner, R any] func(ctx context.Context, opts T) ([]R, *Pager, error)
+
+// ParallelPaging controls how AutoPageParallel fetches pages
+type ParallelPaging struct {
+	Concurrency    int     // max pages fetched at the same time, less than 2 fetches pages one after another
+	PagesPerSecond float64 // max rate at which pages are fetched, 0 is unlimited
+}
+
+// AutoPageParallel fetches the first page and then, once the total number of pages is known, fetches the
+// remaining pages in parallel. The results are returned in page order.
+//
+// The first error cancels any outstanding page fetches and is returned. Options types that aren't a pointer
+// to a struct can't be copied for each page and are always fetched one page after another.
+func AutoPageParallel[T ListOptioner, R any](ctx context.Context, opts T, list ContextListFunc[T, R], paging ParallelPaging) ([]R, error) {
+	first, pager, err := list(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	if !pager.HasNextPage() {
+		return first, nil
+	}
+	if _, ok := cloneListOptions(opts); !ok || paging.Concurrency < 2 || pager.TotalPages < 2 {
+		pager.NextPage(opts)
+		rest, err := AutoPage[T, R](opts, func(opts T) ([]R, *Pager, error) {
+			return list(ctx, opts)
+		})
+		if err != nil {
+			return nil, err
+		}
+		return append(first, rest...), nil
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var throttle <-chan time.Time
+	if paging.PagesPerSecond > 0 {
+		ticker := time.NewTicker(time.Duration(float64(time.Second) / paging.PagesPerSecond))
+		defer ticker.Stop()
+		throttle = ticker.C
+	}
+
+	perPage := pager.NextPageReader.Int(PerPageField)
+	pages := make([][]R, pager.TotalPages)
+	pages[0] = first
+	sem := make(chan struct{}, paging.Concurrency)
+	var wg sync.WaitGroup
+	var errOnce sync.Once
+	var firstErr error
+	fail := func(err error) {
+		errOnce.Do(func() {
+			firstErr = err
+			cancel()
+		})
+	}
+
+dispatch:
+	for page := 2; page <= pager.TotalPages; page++ {
+		if throttle != nil {
+			select {
+			case <-throttle:
+			case <-ctx.Done():
+				break dispatch
+			}
+		}
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			break dispatch
+		}
+		pageOpts, _ := cloneListOptions(opts)
+		pageOpts.CurrentPage(page, perPage)
+		wg.Add(1)
+		go func(page int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			results, _, err := list(ctx, pageOpts)
+			if err != nil {
+				fail(fmt.Errorf("error listing page %d: %w", page, err))
+				return
+			}
+			pages[page-1] = results
+		}(page)
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	// the parent context was cancelled before all pages were dispatched
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	var all []R
+	for _, p := range pages {
+		all = append(all, p...)
+	}
+	return all, nil
+}
+
+// autoPage lists all pages using the client's configured list concurrency and rate limit
+func autoPage[T ListOptioner, R any](ctx context.Context, c *Client, opts T, list ContextListFunc[T, R]) ([]R, error) {
+	return AutoPageParallel[T, R](ctx, opts, list, ParallelPaging{
+		Concurrency:    c.ListConcurrency(),
+		PagesPerSecond: c.ListPagesPerSecond(),
+	})
+}
+
+// cloneListOptions returns a copy of the options, including any embedded ListOptions, so the page can be set
+// without affecting the original
+func cloneListOptions[T ListOptioner](opts T) (T, bool) {
+	v := reflect.ValueOf(opts)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return opts, false
+	}
+	clone := reflect.New(v.Elem().Type())
+	clone.Elem().Set(v.Elem())
+	for i := 0; i < clone.Elem().NumField(); i++ {
+		f := clone.Elem().Field(i)
+		if f.Type() == listOptionsPtrType && !f.IsNil() && f.CanSet() {
+			lo := *f.Interface().(*ListOptions)
+			f.Set(reflect.ValueOf(&lo))
+		}
+	}
+	return clone.Interface().(T), true
+}
+
+var listOptionsPtrType = reflect.TypeOf((*ListOptions)(nil))
+
+// Iterator lazily retrieves the results of a list function one page at a time
+//
+//	it := client.Iterate(ctx, opts, listFunc)
+//	for it.Next() {
+//		r := it.Value()
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+type Iterator[T ListOptioner, R any] struct {
+	ctx     context.Context
+	opts    T
+	list    ListFunc[T, R]
+	page    []R
+	index   int
+	pager   *Pager
+	value   R
+	err     error
+	fetched bool
+}
+
+// Iterate returns an iterator that calls list for the next page only once the current page has been consumed
+func Iterate[T ListOptioner, R any](ctx context.Context, opts T, list ListFunc[T, R]) *Iterator[T, R] {
+	return &Iterator[T, R]{
+		ctx:  ctx,
+		opts: opts,
+		list: list,
+	}
+}
+
+// Next advances the iterator to the next result, fetching the next page if required, and returns false
+// once there are no more results or an error occurred
+func (i *Iterator[T, R]) Next() bool {
+	if i.err != nil {
+		return false
+	}
+	for i.index >= len(i.page) {
+		if i.fetched && !i.pager.HasNextPage() {
+			return false
+		}
+		if err := i.ctx.Err(); err != nil {
+			i.err = err
+			return false
+		}
+		if i.fetched {
+			i.pager.NextPage(i.opts)
+		}
+		page, pager, err := i.list(i.opts)
+		if err != nil {
+			i.err = err
+			return false
+		}
+		i.page, i.pager, i.index, i.fetched = page, pager, 0, true
+	}
+	i.value = i.page[i.index]
+	i.index++
+	return true
+}
+
+// Value returns the current result
+func (i *Iterator[T, R]) Value() R {
+	return i.value
+}
+
+// Err returns the error, if any, that stopped the iteration
+func (i *Iterator[T, R]) Err() error {
+	return i.err
+}
+
+// Pager returns the pager of the most recently fetched page, or nil before the first call to Next
+func (i *Iterator[T, R]) Pager() *Pager {
+	return i.pager
+}
+
 // Single returns a single object from the call to list or an error if matches > 1 or matches < 1
 func Single[T ListOptioner, R any](opts T, list ListFunc[T, R]) (R, error) {
 	matches, _, err := list(opts)
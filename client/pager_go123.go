@@ -0,0 +1,32 @@
+//go:build go1.23
+
+package client
+
+import (
+	"context"
+	"iter"
+)
+
+// All returns the iterator's remaining results as a sequence for use with range, iteration stops after
+// yielding an error
+//
+//	for app, err := range c.Applications.IterateAll(ctx, nil).All() {
+//		...
+//	}
+func (i *Iterator[T, R]) All() iter.Seq2[R, error] {
+	return func(yield func(R, error) bool) {
+		for i.Next() {
+			if !yield(i.Value(), nil) {
+				return
+			}
+		}
+		if err := i.Err(); err != nil {
+			yield(*new(R), err)
+		}
+	}
+}
+
+// IterateSeq returns a sequence that calls list for the next page only once the current page has been consumed
+func IterateSeq[T ListOptioner, R any](ctx context.Context, opts T, list ListFunc[T, R]) iter.Seq2[R, error] {
+	return Iterate(ctx, opts, list).All()
+}
@@ -0,0 +1,35 @@
+//go:build go1.23
+
+package client
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIteratorAll(t *testing.T) {
+	pages := [][]string{{"a", "b"}, {"c", "d"}, {"e"}}
+
+	var requested []int
+	var values []string
+	IterateSeq(context.Background(), NewAppListOptions(), pagedList(pages, &requested))(func(v string, err error) bool {
+		require.NoError(t, err)
+		values = append(values, v)
+		return v != "c"
+	})
+	require.Equal(t, []string{"a", "b", "c"}, values)
+	require.Equal(t, []int{1, 2}, requested)
+
+	var errs []error
+	Iterate(context.Background(), NewAppListOptions(), func(opts *AppListOptions) ([]string, *Pager, error) {
+		return nil, nil, errors.New("boom")
+	}).All()(func(v string, err error) bool {
+		errs = append(errs, err)
+		return true
+	})
+	require.Len(t, errs, 1)
+	require.EqualError(t, errs[0], "boom")
+}
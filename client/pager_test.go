@@ -1,7 +1,13 @@
 package client
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/cloudfoundry/go-cfclient/v3/resource"
 
@@ -107,3 +113,159 @@ func TestPager(t *testing.T) {
 	require.Equal(t, 1, listOpts.Page)
 	require.Equal(t, 50, listOpts.PerPage)
 }
+
+// pagedList returns a list func over the pages which records the pages requested
+func pagedList(pages [][]string, requested *[]int) ListFunc[*AppListOptions, string] {
+	return func(opts *AppListOptions) ([]string, *Pager, error) {
+		*requested = append(*requested, opts.Page)
+		pagination := resource.Pagination{
+			TotalResults: 5,
+			TotalPages:   len(pages),
+		}
+		if opts.Page < len(pages) {
+			pagination.Next.Href = fmt.Sprintf("https://api.example.org/v3/apps?page=%d&per_page=2", opts.Page+1)
+		}
+		return pages[opts.Page-1], NewPager(pagination), nil
+	}
+}
+
+func TestIterate(t *testing.T) {
+	pages := [][]string{{"a", "b"}, {"c", "d"}, {"e"}}
+
+	t.Run("all pages", func(t *testing.T) {
+		var requested []int
+		it := Iterate(context.Background(), NewAppListOptions(), pagedList(pages, &requested))
+		require.Empty(t, requested)
+
+		var values []string
+		for it.Next() {
+			values = append(values, it.Value())
+		}
+		require.NoError(t, it.Err())
+		require.Equal(t, []string{"a", "b", "c", "d", "e"}, values)
+		require.Equal(t, []int{1, 2, 3}, requested)
+		require.False(t, it.Next())
+	})
+
+	t.Run("fetches pages on demand", func(t *testing.T) {
+		var requested []int
+		it := Iterate(context.Background(), NewAppListOptions(), pagedList(pages, &requested))
+		require.True(t, it.Next())
+		require.True(t, it.Next())
+		require.Equal(t, "b", it.Value())
+		require.Equal(t, []int{1}, requested)
+		require.True(t, it.Next())
+		require.Equal(t, "c", it.Value())
+		require.Equal(t, []int{1, 2}, requested)
+	})
+
+	t.Run("context cancelled", func(t *testing.T) {
+		var requested []int
+		ctx, cancel := context.WithCancel(context.Background())
+		it := Iterate(ctx, NewAppListOptions(), pagedList(pages, &requested))
+		require.True(t, it.Next())
+		cancel()
+		require.True(t, it.Next())
+		require.False(t, it.Next())
+		require.ErrorIs(t, it.Err(), context.Canceled)
+		require.Equal(t, []int{1}, requested)
+	})
+
+	t.Run("list error", func(t *testing.T) {
+		it := Iterate(context.Background(), NewAppListOptions(), func(opts *AppListOptions) ([]string, *Pager, error) {
+			return nil, nil, errors.New("boom")
+		})
+		require.False(t, it.Next())
+		require.EqualError(t, it.Err(), "boom")
+	})
+}
+
+func TestAutoPageParallel(t *testing.T) {
+	pages := [][]string{{"a", "b"}, {"c", "d"}, {"e", "f"}, {"g", "h"}, {"i"}}
+
+	parallelList := func(delay func(page int) time.Duration, inFlight, maxInFlight *int32) ContextListFunc[*AppListOptions, string] {
+		var mu sync.Mutex
+		var requested []int
+		list := pagedList(pages, &requested)
+		return func(ctx context.Context, opts *AppListOptions) ([]string, *Pager, error) {
+			n := atomic.AddInt32(inFlight, 1)
+			defer atomic.AddInt32(inFlight, -1)
+			for {
+				m := atomic.LoadInt32(maxInFlight)
+				if n <= m || atomic.CompareAndSwapInt32(maxInFlight, m, n) {
+					break
+				}
+			}
+			select {
+			case <-time.After(delay(opts.Page)):
+			case <-ctx.Done():
+				return nil, nil, ctx.Err()
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			return list(opts)
+		}
+	}
+
+	t.Run("keeps page order", func(t *testing.T) {
+		var inFlight, maxInFlight int32
+		// later pages finish first
+		delay := func(page int) time.Duration { return time.Duration(len(pages)-page) * 10 * time.Millisecond }
+		opts := NewAppListOptions()
+		all, err := AutoPageParallel(context.Background(), opts, parallelList(delay, &inFlight, &maxInFlight), ParallelPaging{
+			Concurrency: 2,
+		})
+		require.NoError(t, err)
+		require.Equal(t, []string{"a", "b", "c", "d", "e", "f", "g", "h", "i"}, all)
+		require.Equal(t, int32(2), maxInFlight)
+		require.Equal(t, 1, opts.Page)
+	})
+
+	t.Run("sequential", func(t *testing.T) {
+		var inFlight, maxInFlight int32
+		delay := func(page int) time.Duration { return 0 }
+		all, err := AutoPageParallel(context.Background(), NewAppListOptions(), parallelList(delay, &inFlight, &maxInFlight), ParallelPaging{
+			Concurrency: 1,
+		})
+		require.NoError(t, err)
+		require.Equal(t, []string{"a", "b", "c", "d", "e", "f", "g", "h", "i"}, all)
+		require.Equal(t, int32(1), maxInFlight)
+	})
+
+	t.Run("rate limited", func(t *testing.T) {
+		var inFlight, maxInFlight int32
+		delay := func(page int) time.Duration { return 0 }
+		start := time.Now()
+		_, err := AutoPageParallel(context.Background(), NewAppListOptions(), parallelList(delay, &inFlight, &maxInFlight), ParallelPaging{
+			Concurrency:    4,
+			PagesPerSecond: 50,
+		})
+		require.NoError(t, err)
+		// 4 pages after the first at 20ms intervals
+		require.GreaterOrEqual(t, time.Since(start), 80*time.Millisecond)
+	})
+
+	t.Run("first error cancels", func(t *testing.T) {
+		var cancelled int32
+		list := func(ctx context.Context, opts *AppListOptions) ([]string, *Pager, error) {
+			switch opts.Page {
+			case 1:
+				var requested []int
+				return pagedList(pages, &requested)(opts)
+			case 2:
+				// give the other pages time to be dispatched
+				time.Sleep(20 * time.Millisecond)
+				return nil, nil, errors.New("boom")
+			default:
+				<-ctx.Done()
+				atomic.AddInt32(&cancelled, 1)
+				return nil, nil, ctx.Err()
+			}
+		}
+		_, err := AutoPageParallel(context.Background(), NewAppListOptions(), list, ParallelPaging{
+			Concurrency: 4,
+		})
+		require.EqualError(t, err, "error listing page 2: boom")
+		require.Equal(t, int32(3), cancelled)
+	})
+}
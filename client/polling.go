@@ -1,17 +1,37 @@
 package client
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"time"
+
+	"github.com/cloudfoundry/go-cfclient/v3/resource"
 )
 
 var AsyncProcessFailedError = errors.New("received state FAILED while waiting for async process")
 var AsyncProcessTimeoutError = errors.New("timed out after waiting for async process")
 
+// PollingOptions configures how long and how often an asynchronous process is polled
 type PollingOptions struct {
-	Timeout       time.Duration
+	// Timeout is the maximum time to wait for the process, the context deadline also applies
+	Timeout time.Duration
+
+	// CheckInterval is the time to wait between the first and second check
 	CheckInterval time.Duration
-	FailedState   string
+
+	// MaxCheckInterval caps the interval between checks when backing off, 0 for no cap
+	MaxCheckInterval time.Duration
+
+	// BackoffFactor multiplies the interval after each check, values of 1 or less keep a constant interval
+	BackoffFactor float64
+
+	// OnState is called with the state observed by every check, including the terminal state
+	OnState func(state string)
+
+	// Deprecated: FailedState is only used by PollForStateOrTimeout, the failed states of
+	// each resource are always recognized by the client's poll functions
+	FailedState string
 }
 
 func NewPollingOptions() *PollingOptions {
@@ -22,32 +42,178 @@ func NewPollingOptions() *PollingOptions {
 	}
 }
 
+// PollStateFunc returns the current state of the asynchronous process being polled
+type PollStateFunc func(ctx context.Context) (string, error)
+
+// PollForState checks the state of an asynchronous process immediately, and then after each check interval,
+// until it reaches one of the success or failed states, the timeout expires or the context is done.
+//
+// The terminal state is returned along with a nil error for a success state, AsyncProcessFailedError for
+// a failed state, or AsyncProcessTimeoutError if the timeout expired first.
+func PollForState(ctx context.Context, getState PollStateFunc, successStates, failedStates []string, opts *PollingOptions) (string, error) {
+	var state string
+	err := poll(ctx, func(ctx context.Context) (bool, error) {
+		var err error
+		state, err = getState(ctx)
+		if err != nil {
+			return false, err
+		}
+		if opts != nil && opts.OnState != nil {
+			opts.OnState(state)
+		}
+		if containsState(successStates, state) {
+			return true, nil
+		}
+		if containsState(failedStates, state) {
+			return false, AsyncProcessFailedError
+		}
+		return false, nil
+	}, opts)
+	return state, err
+}
+
+func containsState(states []string, state string) bool {
+	for _, s := range states {
+		if s == state {
+			return true
+		}
+	}
+	return false
+}
+
 type getStateFunc func() (string, error)
 
+// Deprecated: PollForStateOrTimeout ignores context cancellation, use PollForState instead
 func PollForStateOrTimeout(getState getStateFunc, successState string, opts *PollingOptions) error {
 	if opts == nil {
 		opts = NewPollingOptions()
 	}
+	_, err := PollForState(context.Background(), func(context.Context) (string, error) {
+		return getState()
+	}, []string{successState}, []string{opts.FailedState}, opts)
+	return err
+}
 
-	timeout := time.After(opts.Timeout)
-	ticker := time.NewTicker(opts.CheckInterval)
-	defer ticker.Stop()
+// checkFunc does a single check of an asynchronous process and returns true once it has completed
+type checkFunc func(ctx context.Context) (done bool, err error)
 
+// poll runs the check immediately and then after each interval until it's done, fails, the
+// timeout expires or the context is done
+func poll(ctx context.Context, check checkFunc, opts *PollingOptions) error {
+	if opts == nil {
+		opts = NewPollingOptions()
+	}
+
+	timeout := time.NewTimer(opts.Timeout)
+	defer timeout.Stop()
+
+	interval := opts.CheckInterval
 	for {
+		done, err := check(ctx)
+		if err != nil || done {
+			return err
+		}
+
+		timer := time.NewTimer(interval)
 		select {
-		case <-timeout:
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timeout.C:
+			timer.Stop()
 			return AsyncProcessTimeoutError
-		case <-ticker.C:
-			state, err := getState()
-			if err != nil {
-				return err
-			}
-			switch state {
-			case successState:
-				return nil
-			case opts.FailedState:
-				return AsyncProcessFailedError
+		case <-timer.C:
+		}
+
+		if opts.BackoffFactor > 1 {
+			interval = time.Duration(float64(interval) * opts.BackoffFactor)
+			if opts.MaxCheckInterval > 0 && interval > opts.MaxCheckInterval {
+				interval = opts.MaxCheckInterval
 			}
 		}
 	}
 }
+
+// LastOperationFailedError is returned when a service broker reports its asynchronous operation failed
+type LastOperationFailedError struct {
+	Type        string // the type of operation, create, update or delete
+	Description string // the broker's description of the failure
+}
+
+func (e *LastOperationFailedError) Error() string {
+	if e.Description == "" {
+		return fmt.Sprintf("service broker %s operation failed", e.Type)
+	}
+	return fmt.Sprintf("service broker %s operation failed: %s", e.Type, e.Description)
+}
+
+// Is allows errors.Is(err, AsyncProcessFailedError) to match a LastOperationFailedError
+func (e *LastOperationFailedError) Is(target error) bool {
+	return target == AsyncProcessFailedError
+}
+
+// DeploymentFailedError is returned when a deployment is finalized without being deployed
+type DeploymentFailedError struct {
+	GUID   string // the deployment's GUID
+	Reason string // the reason the deployment finalized, CANCELED or DEGENERATE
+	Detail string // any error detail reported by the API
+}
+
+func (e *DeploymentFailedError) Error() string {
+	if e.Detail == "" {
+		return fmt.Sprintf("deployment %s finalized with reason %s", e.GUID, e.Reason)
+	}
+	return fmt.Sprintf("deployment %s finalized with reason %s: %s", e.GUID, e.Reason, e.Detail)
+}
+
+// Is allows errors.Is(err, AsyncProcessFailedError) to match a DeploymentFailedError
+func (e *DeploymentFailedError) Is(target error) bool {
+	return target == AsyncProcessFailedError
+}
+
+// getLastOperationFunc returns the last operation of a service resource, or nil once the resource no
+// longer exists
+type getLastOperationFunc func() (*resource.LastOperation, error)
+
+// pollLastOperation polls the last operation until it succeeds or fails. The v3 API doesn't pass on
+// the polling interval suggested by the broker, so the CheckInterval is always used
+func pollLastOperation(ctx context.Context, getLastOperation getLastOperationFunc, opts *PollingOptions) error {
+	return poll(ctx, func(ctx context.Context) (bool, error) {
+		lastOp, err := getLastOperation()
+		if err != nil {
+			return false, err
+		}
+		if lastOp == nil {
+			return true, nil
+		}
+		if opts != nil && opts.OnState != nil {
+			opts.OnState(lastOp.State)
+		}
+		switch lastOp.State {
+		case resource.LastOperationSucceeded:
+			return true, nil
+		case resource.LastOperationFailed:
+			return false, &LastOperationFailedError{
+				Type:        lastOp.Type,
+				Description: lastOp.Description,
+			}
+		}
+		return false, nil
+	}, opts)
+}
+
+// pollResourceLastOperation polls the service resource at the path until its last operation succeeds or fails.
+// A resource that no longer exists is treated as a completed delete
+func pollResourceLastOperation[T any](ctx context.Context, c *Client, resourcePath string, lastOperation func(*T) *resource.LastOperation, opts *PollingOptions) error {
+	return pollLastOperation(ctx, func() (*resource.LastOperation, error) {
+		var r T
+		err := c.get(ctx, resourcePath, &r)
+		if resource.IsResourceNotFoundError(err) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		return lastOperation(&r), nil
+	}, opts)
+}
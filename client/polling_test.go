@@ -1,9 +1,13 @@
 package client
 
 import (
-	"github.com/stretchr/testify/require"
+	"context"
+	"errors"
 	"testing"
 	"time"
+
+	"github.com/cloudfoundry/go-cfclient/v3/resource"
+	"github.com/stretchr/testify/require"
 )
 
 func TestNewPollingOptions(t *testing.T) {
@@ -37,3 +41,108 @@ func TestPollForStateOrTimeout(t *testing.T) {
 	err = PollForStateOrTimeout(timeoutFn, "SUCCESS", noWaitOpts)
 	require.Equal(t, AsyncProcessTimeoutError, err)
 }
+
+func TestPollLastOperation(t *testing.T) {
+	opts := NewPollingOptions()
+	opts.Timeout = time.Second
+	opts.CheckInterval = time.Millisecond
+
+	states := func(lastOps ...*resource.LastOperation) getLastOperationFunc {
+		return func() (*resource.LastOperation, error) {
+			lastOp := lastOps[0]
+			if len(lastOps) > 1 {
+				lastOps = lastOps[1:]
+			}
+			return lastOp, nil
+		}
+	}
+	inProgress := &resource.LastOperation{Type: "create", State: resource.LastOperationInProgress}
+	succeeded := &resource.LastOperation{Type: "create", State: resource.LastOperationSucceeded}
+	failed := &resource.LastOperation{Type: "create", State: resource.LastOperationFailed, Description: "quota exceeded"}
+
+	err := pollLastOperation(context.Background(), states(inProgress, inProgress, succeeded), opts)
+	require.NoError(t, err)
+
+	err = pollLastOperation(context.Background(), states(inProgress, nil), opts)
+	require.NoError(t, err)
+
+	err = pollLastOperation(context.Background(), states(inProgress, failed), opts)
+	var lastOpErr *LastOperationFailedError
+	require.ErrorAs(t, err, &lastOpErr)
+	require.Equal(t, "quota exceeded", lastOpErr.Description)
+	require.ErrorIs(t, err, AsyncProcessFailedError)
+	require.EqualError(t, err, "service broker create operation failed: quota exceeded")
+
+	err = pollLastOperation(context.Background(), states(inProgress), opts)
+	require.Equal(t, AsyncProcessTimeoutError, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = pollLastOperation(ctx, states(inProgress), opts)
+	require.ErrorIs(t, err, context.Canceled)
+
+}
+
+func TestPollForState(t *testing.T) {
+	states := func(states ...string) PollStateFunc {
+		return func(ctx context.Context) (string, error) {
+			state := states[0]
+			if len(states) > 1 {
+				states = states[1:]
+			}
+			return state, nil
+		}
+	}
+
+	// the first check is immediate
+	opts := NewPollingOptions()
+	opts.CheckInterval = time.Hour
+	start := time.Now()
+	state, err := PollForState(context.Background(), states("READY"), []string{"READY"}, nil, opts)
+	require.NoError(t, err)
+	require.Equal(t, "READY", state)
+	require.Less(t, time.Since(start), time.Second)
+
+	// every observed state is reported and any of the terminal states end polling
+	var observed []string
+	opts = NewPollingOptions()
+	opts.CheckInterval = time.Millisecond
+	opts.OnState = func(state string) {
+		observed = append(observed, state)
+	}
+	state, err = PollForState(context.Background(), states("AWAITING_UPLOAD", "PROCESSING_UPLOAD", "EXPIRED"),
+		[]string{"READY"}, []string{"FAILED", "EXPIRED"}, opts)
+	require.Equal(t, AsyncProcessFailedError, err)
+	require.Equal(t, "EXPIRED", state)
+	require.Equal(t, []string{"AWAITING_UPLOAD", "PROCESSING_UPLOAD", "EXPIRED"}, observed)
+
+	// the interval backs off up to the max
+	var checks []time.Time
+	opts = NewPollingOptions()
+	opts.CheckInterval = 10 * time.Millisecond
+	opts.BackoffFactor = 2
+	opts.MaxCheckInterval = 20 * time.Millisecond
+	opts.OnState = func(string) {
+		checks = append(checks, time.Now())
+	}
+	_, err = PollForState(context.Background(), states("STAGING", "STAGING", "STAGING", "STAGED"), []string{"STAGED"}, nil, opts)
+	require.NoError(t, err)
+	require.Len(t, checks, 4)
+	require.GreaterOrEqual(t, checks[1].Sub(checks[0]), 10*time.Millisecond)
+	require.GreaterOrEqual(t, checks[2].Sub(checks[1]), 20*time.Millisecond)
+	require.GreaterOrEqual(t, checks[3].Sub(checks[2]), 20*time.Millisecond)
+
+	// context cancellation stops polling
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	opts = NewPollingOptions()
+	opts.CheckInterval = time.Millisecond
+	_, err = PollForState(ctx, states("STAGING"), []string{"STAGED"}, nil, opts)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	// errors getting the state are returned
+	_, err = PollForState(context.Background(), func(ctx context.Context) (string, error) {
+		return "", errors.New("boom")
+	}, []string{"STAGED"}, nil, nil)
+	require.EqualError(t, err, "boom")
+}
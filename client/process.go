@@ -96,7 +96,17 @@ func (c *ProcessClient) ListAll(ctx context.Context, opts *ProcessListOptions) (
 	if opts == nil {
 		opts = NewProcessOptions()
 	}
-	return AutoPage[*ProcessListOptions, *resource.Process](opts, func(opts *ProcessListOptions) ([]*resource.Process, *Pager, error) {
+	return autoPage[*ProcessListOptions, *resource.Process](ctx, c.client, opts, func(ctx context.Context, opts *ProcessListOptions) ([]*resource.Process, *Pager, error) {
+		return c.List(ctx, opts)
+	})
+}
+
+// IterateAll lazily retrieves all processes, fetching each page only as it's needed
+func (c *ProcessClient) IterateAll(ctx context.Context, opts *ProcessListOptions) *Iterator[*ProcessListOptions, *resource.Process] {
+	if opts == nil {
+		opts = NewProcessOptions()
+	}
+	return Iterate[*ProcessListOptions, *resource.Process](ctx, opts, func(opts *ProcessListOptions) ([]*resource.Process, *Pager, error) {
 		return c.List(ctx, opts)
 	})
 }
@@ -121,7 +131,7 @@ func (c *ProcessClient) ListForAppAll(ctx context.Context, appGUID string, opts
 	if opts == nil {
 		opts = NewProcessOptions()
 	}
-	return AutoPage[*ProcessListOptions, *resource.Process](opts, func(opts *ProcessListOptions) ([]*resource.Process, *Pager, error) {
+	return autoPage[*ProcessListOptions, *resource.Process](ctx, c.client, opts, func(ctx context.Context, opts *ProcessListOptions) ([]*resource.Process, *Pager, error) {
 		return c.ListForApp(ctx, appGUID, opts)
 	})
 }
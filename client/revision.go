@@ -74,7 +74,7 @@ func (c *RevisionClient) ListForAppAll(ctx context.Context, appGUID string, opts
 	if opts == nil {
 		opts = NewRevisionListOptions()
 	}
-	return AutoPage[*RevisionListOptions, *resource.Revision](opts, func(opts *RevisionListOptions) ([]*resource.Revision, *Pager, error) {
+	return autoPage[*RevisionListOptions, *resource.Revision](ctx, c.client, opts, func(ctx context.Context, opts *RevisionListOptions) ([]*resource.Revision, *Pager, error) {
 		return c.ListForApp(ctx, appGUID, opts)
 	})
 }
@@ -98,7 +98,7 @@ func (c *RevisionClient) ListForAppDeployedAll(ctx context.Context, appGUID stri
 	if opts == nil {
 		opts = NewRevisionListOptions()
 	}
-	return AutoPage[*RevisionListOptions, *resource.Revision](opts, func(opts *RevisionListOptions) ([]*resource.Revision, *Pager, error) {
+	return autoPage[*RevisionListOptions, *resource.Revision](ctx, c.client, opts, func(ctx context.Context, opts *RevisionListOptions) ([]*resource.Revision, *Pager, error) {
 		return c.ListForAppDeployed(ctx, appGUID, opts)
 	})
 }
@@ -185,7 +185,17 @@ func (c *RoleClient) ListAll(ctx context.Context, opts *RoleListOptions) ([]*res
 	if opts == nil {
 		opts = NewRoleListOptions()
 	}
-	return AutoPage[*RoleListOptions, *resource.Role](opts, func(opts *RoleListOptions) ([]*resource.Role, *Pager, error) {
+	return autoPage[*RoleListOptions, *resource.Role](ctx, c.client, opts, func(ctx context.Context, opts *RoleListOptions) ([]*resource.Role, *Pager, error) {
+		return c.List(ctx, opts)
+	})
+}
+
+// IterateAll lazily retrieves all roles the user has access to, fetching each page only as it's needed
+func (c *RoleClient) IterateAll(ctx context.Context, opts *RoleListOptions) *Iterator[*RoleListOptions, *resource.Role] {
+	if opts == nil {
+		opts = NewRoleListOptions()
+	}
+	return Iterate[*RoleListOptions, *resource.Role](ctx, opts, func(opts *RoleListOptions) ([]*resource.Role, *Pager, error) {
 		return c.List(ctx, opts)
 	})
 }
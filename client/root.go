@@ -2,7 +2,9 @@ package client
 
 import (
 	"context"
+	"fmt"
 
+	"github.com/cloudfoundry/go-cfclient/v3/internal/path"
 	"github.com/cloudfoundry/go-cfclient/v3/resource"
 )
 
@@ -35,3 +37,17 @@ func (c *RootClient) GetV3(ctx context.Context) (*resource.V3Root, error) {
 	}
 	return &v3Root, nil
 }
+
+// serviceURL discovers the base URL of a platform service linked from the global API root, like log-cache,
+// and joins it with the specified path
+func (c *RootClient) serviceURL(ctx context.Context, serviceName string, link func(*resource.RootLinks) resource.Link, urlPath string) (string, error) {
+	root, err := c.Get(ctx)
+	if err != nil {
+		return "", fmt.Errorf("error discovering the %s URL: %w", serviceName, err)
+	}
+	href := link(&root.Links).Href
+	if href == "" {
+		return "", fmt.Errorf("the CF API root does not link to a %s service", serviceName)
+	}
+	return path.Join(href, urlPath), nil
+}
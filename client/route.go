@@ -204,7 +204,17 @@ func (c *RouteClient) ListAll(ctx context.Context, opts *RouteListOptions) ([]*r
 	if opts == nil {
 		opts = NewRouteListOptions()
 	}
-	return AutoPage[*RouteListOptions, *resource.Route](opts, func(opts *RouteListOptions) ([]*resource.Route, *Pager, error) {
+	return autoPage[*RouteListOptions, *resource.Route](ctx, c.client, opts, func(ctx context.Context, opts *RouteListOptions) ([]*resource.Route, *Pager, error) {
+		return c.List(ctx, opts)
+	})
+}
+
+// IterateAll lazily retrieves all routes the user has access to, fetching each page only as it's needed
+func (c *RouteClient) IterateAll(ctx context.Context, opts *RouteListOptions) *Iterator[*RouteListOptions, *resource.Route] {
+	if opts == nil {
+		opts = NewRouteListOptions()
+	}
+	return Iterate[*RouteListOptions, *resource.Route](ctx, opts, func(opts *RouteListOptions) ([]*resource.Route, *Pager, error) {
 		return c.List(ctx, opts)
 	})
 }
@@ -230,7 +240,7 @@ func (c *RouteClient) ListForAppAll(ctx context.Context, appGUID string, opts *R
 	if opts == nil {
 		opts = NewRouteListOptions()
 	}
-	return AutoPage[*RouteListOptions, *resource.Route](opts, func(opts *RouteListOptions) ([]*resource.Route, *Pager, error) {
+	return autoPage[*RouteListOptions, *resource.Route](ctx, c.client, opts, func(ctx context.Context, opts *RouteListOptions) ([]*resource.Route, *Pager, error) {
 		return c.ListForApp(ctx, appGUID, opts)
 	})
 }
@@ -0,0 +1,139 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/cloudfoundry/go-cfclient/v3/internal/path"
+	"github.com/cloudfoundry/go-cfclient/v3/resource"
+)
+
+// RoutingClient manages router groups and TCP routes via the routing API linked from the API root
+type RoutingClient commonClient
+
+// RouterGroupListOptions list filters
+type RouterGroupListOptions struct {
+	Name string // the router group name to filter by
+}
+
+// NewRouterGroupListOptions creates new options to pass to list
+func NewRouterGroupListOptions() *RouterGroupListOptions {
+	return &RouterGroupListOptions{}
+}
+
+func (o RouterGroupListOptions) ToQueryString() (url.Values, error) {
+	values := url.Values{}
+	if o.Name != "" {
+		values.Set("name", o.Name)
+	}
+	return values, nil
+}
+
+// GetRouterGroup retrieves the specified router group
+func (c *RoutingClient) GetRouterGroup(ctx context.Context, guid string) (*resource.RouterGroup, error) {
+	groups, err := c.ListRouterGroups(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	for _, g := range groups {
+		if g.GUID == guid {
+			return g, nil
+		}
+	}
+	return nil, fmt.Errorf("router group %s: %w", guid, ErrNoResultsReturned)
+}
+
+// IsTCPPortAvailable returns true if the port is one of the router group's reservable ports and
+// no TCP route is already registered on it
+func (c *RoutingClient) IsTCPPortAvailable(ctx context.Context, routerGroupGUID string, port int) (bool, error) {
+	group, err := c.GetRouterGroup(ctx, routerGroupGUID)
+	if err != nil {
+		return false, err
+	}
+	reservable, err := group.IsPortReservable(port)
+	if err != nil || !reservable {
+		return false, err
+	}
+	routes, err := c.ListTCPRoutes(ctx)
+	if err != nil {
+		return false, err
+	}
+	for _, r := range routes {
+		if r.RouterGroupGUID == routerGroupGUID && r.Port == port {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// ListRouterGroups retrieves all the router groups
+func (c *RoutingClient) ListRouterGroups(ctx context.Context, opts *RouterGroupListOptions) ([]*resource.RouterGroup, error) {
+	if opts == nil {
+		opts = NewRouterGroupListOptions()
+	}
+	params, err := opts.ToQueryString()
+	if err != nil {
+		return nil, fmt.Errorf("error while generate query params: %w", err)
+	}
+	routerGroupsURL, err := c.routingURL(ctx, path.Format("/v1/router_groups?%s", params))
+	if err != nil {
+		return nil, err
+	}
+	var groups []*resource.RouterGroup
+	if err = c.client.executeExternal(ctx, http.MethodGet, routerGroupsURL, nil, &groups); err != nil {
+		return nil, err
+	}
+	return groups, nil
+}
+
+// ListTCPRoutes retrieves all the registered TCP routes
+func (c *RoutingClient) ListTCPRoutes(ctx context.Context) ([]*resource.TCPRoute, error) {
+	tcpRoutesURL, err := c.routingURL(ctx, "/v1/tcp_routes")
+	if err != nil {
+		return nil, err
+	}
+	var routes []*resource.TCPRoute
+	if err = c.client.executeExternal(ctx, http.MethodGet, tcpRoutesURL, nil, &routes); err != nil {
+		return nil, err
+	}
+	return routes, nil
+}
+
+// RegisterTCPRoutes creates or refreshes the specified TCP routes
+func (c *RoutingClient) RegisterTCPRoutes(ctx context.Context, routes ...*resource.TCPRoute) error {
+	tcpRoutesURL, err := c.routingURL(ctx, "/v1/tcp_routes/create")
+	if err != nil {
+		return err
+	}
+	return c.client.executeExternal(ctx, http.MethodPost, tcpRoutesURL, routes, nil)
+}
+
+// UnregisterTCPRoutes deletes the specified TCP routes
+func (c *RoutingClient) UnregisterTCPRoutes(ctx context.Context, routes ...*resource.TCPRoute) error {
+	tcpRoutesURL, err := c.routingURL(ctx, "/v1/tcp_routes/delete")
+	if err != nil {
+		return err
+	}
+	return c.client.executeExternal(ctx, http.MethodPost, tcpRoutesURL, routes, nil)
+}
+
+// UpdateRouterGroup updates the reservable ports of the specified router group
+func (c *RoutingClient) UpdateRouterGroup(ctx context.Context, guid string, r *resource.RouterGroupUpdate) (*resource.RouterGroup, error) {
+	routerGroupURL, err := c.routingURL(ctx, path.Format("/v1/router_groups/%s", guid))
+	if err != nil {
+		return nil, err
+	}
+	var group resource.RouterGroup
+	if err = c.client.executeExternal(ctx, http.MethodPut, routerGroupURL, r, &group); err != nil {
+		return nil, err
+	}
+	return &group, nil
+}
+
+func (c *RoutingClient) routingURL(ctx context.Context, urlPath string) (string, error) {
+	return c.client.Root.serviceURL(ctx, "routing", func(l *resource.RootLinks) resource.Link {
+		return l.Routing
+	}, urlPath)
+}
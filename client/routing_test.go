@@ -0,0 +1,119 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/cloudfoundry/go-cfclient/v3/resource"
+	"github.com/cloudfoundry/go-cfclient/v3/testutil"
+)
+
+func TestRouting(t *testing.T) {
+	g := testutil.NewObjectJSONGenerator(1)
+	routerGroup := g.RouterGroup()
+	routerGroup2 := g.RouterGroup()
+	tcpRoute := g.TCPRoute(routerGroup.GUID, 1024).JSON
+	tcpRoute2 := g.TCPRoute(routerGroup.GUID, 1025).JSON
+
+	tests := []RouteTest{
+		{
+			Description: "List router groups",
+			Route: testutil.MockRoute{
+				Method:   "GET",
+				Endpoint: "/routing/v1/router_groups",
+				Output:   []string{g.Array(routerGroup.JSON, routerGroup2.JSON)},
+				Status:   http.StatusOK,
+			},
+			Expected: g.Array(routerGroup.JSON, routerGroup2.JSON),
+			Action: func(c *Client, t *testing.T) (any, error) {
+				return c.Routing.ListRouterGroups(context.Background(), nil)
+			},
+		},
+		{
+			Description: "List router groups by name",
+			Route: testutil.MockRoute{
+				Method:      "GET",
+				Endpoint:    "/routing/v1/router_groups",
+				Output:      []string{g.Array(routerGroup.JSON)},
+				Status:      http.StatusOK,
+				QueryString: "name=" + routerGroup.Name,
+			},
+			Expected: g.Array(routerGroup.JSON),
+			Action: func(c *Client, t *testing.T) (any, error) {
+				opts := NewRouterGroupListOptions()
+				opts.Name = routerGroup.Name
+				return c.Routing.ListRouterGroups(context.Background(), opts)
+			},
+		},
+		{
+			Description: "Get router group",
+			Route: testutil.MockRoute{
+				Method:   "GET",
+				Endpoint: "/routing/v1/router_groups",
+				Output:   []string{g.Array(routerGroup.JSON, routerGroup2.JSON)},
+				Status:   http.StatusOK,
+			},
+			Expected: routerGroup2.JSON,
+			Action: func(c *Client, t *testing.T) (any, error) {
+				return c.Routing.GetRouterGroup(context.Background(), routerGroup2.GUID)
+			},
+		},
+		{
+			Description: "Update router group",
+			Route: testutil.MockRoute{
+				Method:   "PUT",
+				Endpoint: "/routing/v1/router_groups/" + routerGroup.GUID,
+				Output:   []string{routerGroup.JSON},
+				Status:   http.StatusOK,
+				PostForm: `{"reservable_ports":"1024-1033,2000"}`,
+			},
+			Expected: routerGroup.JSON,
+			Action: func(c *Client, t *testing.T) (any, error) {
+				return c.Routing.UpdateRouterGroup(context.Background(), routerGroup.GUID, &resource.RouterGroupUpdate{
+					ReservablePorts: "1024-1033,2000",
+				})
+			},
+		},
+		{
+			Description: "List TCP routes",
+			Route: testutil.MockRoute{
+				Method:   "GET",
+				Endpoint: "/routing/v1/tcp_routes",
+				Output:   []string{g.Array(tcpRoute, tcpRoute2)},
+				Status:   http.StatusOK,
+			},
+			Expected: g.Array(tcpRoute, tcpRoute2),
+			Action: func(c *Client, t *testing.T) (any, error) {
+				return c.Routing.ListTCPRoutes(context.Background())
+			},
+		},
+		{
+			Description: "Register TCP routes",
+			Route: testutil.MockRoute{
+				Method:   "POST",
+				Endpoint: "/routing/v1/tcp_routes/create",
+				Status:   http.StatusCreated,
+				PostForm: `[{"router_group_guid":"` + routerGroup.GUID + `","port":1024,"backend_ip":"10.1.1.12","backend_port":60000}]`,
+			},
+			Action: func(c *Client, t *testing.T) (any, error) {
+				r := resource.NewTCPRoute(routerGroup.GUID, 1024, "10.1.1.12", 60000)
+				return nil, c.Routing.RegisterTCPRoutes(context.Background(), r)
+			},
+		},
+		{
+			Description: "Unregister TCP routes",
+			Route: testutil.MockRoute{
+				Method:   "POST",
+				Endpoint: "/routing/v1/tcp_routes/delete",
+				Status:   http.StatusNoContent,
+				PostForm: `[{"router_group_guid":"` + routerGroup.GUID + `","port":1024,"backend_ip":"10.1.1.12","backend_port":60000}]`,
+			},
+			Action: func(c *Client, t *testing.T) (any, error) {
+				r := resource.NewTCPRoute(routerGroup.GUID, 1024, "10.1.1.12", 60000)
+				return nil, c.Routing.UnregisterTCPRoutes(context.Background(), r)
+			},
+		},
+	}
+	ExecuteTests(tests, t)
+}
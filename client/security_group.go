@@ -146,7 +146,17 @@ func (c *SecurityGroupClient) ListAll(ctx context.Context, opts *SecurityGroupLi
 	if opts == nil {
 		opts = NewSecurityGroupListOptions()
 	}
-	return AutoPage[*SecurityGroupListOptions, *resource.SecurityGroup](opts, func(opts *SecurityGroupListOptions) ([]*resource.SecurityGroup, *Pager, error) {
+	return autoPage[*SecurityGroupListOptions, *resource.SecurityGroup](ctx, c.client, opts, func(ctx context.Context, opts *SecurityGroupListOptions) ([]*resource.SecurityGroup, *Pager, error) {
+		return c.List(ctx, opts)
+	})
+}
+
+// IterateAll lazily retrieves all SecurityGroups the user has access to, fetching each page only as it's needed
+func (c *SecurityGroupClient) IterateAll(ctx context.Context, opts *SecurityGroupListOptions) *Iterator[*SecurityGroupListOptions, *resource.SecurityGroup] {
+	if opts == nil {
+		opts = NewSecurityGroupListOptions()
+	}
+	return Iterate[*SecurityGroupListOptions, *resource.SecurityGroup](ctx, opts, func(opts *SecurityGroupListOptions) ([]*resource.SecurityGroup, *Pager, error) {
 		return c.List(ctx, opts)
 	})
 }
@@ -177,7 +187,7 @@ func (c *SecurityGroupClient) ListRunningForSpaceAll(ctx context.Context, spaceG
 	if opts == nil {
 		opts = NewSecurityGroupSpaceListOptions()
 	}
-	return AutoPage[*SecurityGroupSpaceListOptions, *resource.SecurityGroup](opts, func(opts *SecurityGroupSpaceListOptions) ([]*resource.SecurityGroup, *Pager, error) {
+	return autoPage[*SecurityGroupSpaceListOptions, *resource.SecurityGroup](ctx, c.client, opts, func(ctx context.Context, opts *SecurityGroupSpaceListOptions) ([]*resource.SecurityGroup, *Pager, error) {
 		return c.ListRunningForSpace(ctx, spaceGUID, opts)
 	})
 }
@@ -201,7 +211,7 @@ func (c *SecurityGroupClient) ListStagingForSpaceAll(ctx context.Context, spaceG
 	if opts == nil {
 		opts = NewSecurityGroupSpaceListOptions()
 	}
-	return AutoPage[*SecurityGroupSpaceListOptions, *resource.SecurityGroup](opts, func(opts *SecurityGroupSpaceListOptions) ([]*resource.SecurityGroup, *Pager, error) {
+	return autoPage[*SecurityGroupSpaceListOptions, *resource.SecurityGroup](ctx, c.client, opts, func(ctx context.Context, opts *SecurityGroupSpaceListOptions) ([]*resource.SecurityGroup, *Pager, error) {
 		return c.ListStagingForSpace(ctx, spaceGUID, opts)
 	})
 }
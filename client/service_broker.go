@@ -76,7 +76,17 @@ func (c *ServiceBrokerClient) ListAll(ctx context.Context, opts *ServiceBrokerLi
 	if opts == nil {
 		opts = NewServiceBrokerListOptions()
 	}
-	return AutoPage[*ServiceBrokerListOptions, *resource.ServiceBroker](opts, func(opts *ServiceBrokerListOptions) ([]*resource.ServiceBroker, *Pager, error) {
+	return autoPage[*ServiceBrokerListOptions, *resource.ServiceBroker](ctx, c.client, opts, func(ctx context.Context, opts *ServiceBrokerListOptions) ([]*resource.ServiceBroker, *Pager, error) {
+		return c.List(ctx, opts)
+	})
+}
+
+// IterateAll lazily retrieves all service brokers the user has access to, fetching each page only as it's needed
+func (c *ServiceBrokerClient) IterateAll(ctx context.Context, opts *ServiceBrokerListOptions) *Iterator[*ServiceBrokerListOptions, *resource.ServiceBroker] {
+	if opts == nil {
+		opts = NewServiceBrokerListOptions()
+	}
+	return Iterate[*ServiceBrokerListOptions, *resource.ServiceBroker](ctx, opts, func(opts *ServiceBrokerListOptions) ([]*resource.ServiceBroker, *Pager, error) {
 		return c.List(ctx, opts)
 	})
 }
@@ -2,6 +2,7 @@ package client
 
 import (
 	"context"
+	"fmt"
 	"net/url"
 
 	"github.com/cloudfoundry/go-cfclient/v3/internal/path"
@@ -85,6 +86,22 @@ func (c *ServiceCredentialBindingClient) GetDetails(ctx context.Context, guid st
 	return &d, nil
 }
 
+// GetDetailsInterpolated the specified service credential binding details with any credhub-ref
+// placeholders in the credentials replaced with the referenced CredHub values
+func (c *ServiceCredentialBindingClient) GetDetailsInterpolated(ctx context.Context, guid string) (*resource.ServiceCredentialBindingDetails, error) {
+	d, err := c.GetDetails(ctx, guid)
+	if err != nil {
+		return nil, err
+	}
+	if hasCredHubRef(d.Credentials) {
+		d.Credentials, err = c.client.CredHub.Interpolate(ctx, d.Credentials)
+		if err != nil {
+			return nil, fmt.Errorf("error interpolating service credential binding %s credentials: %w", guid, err)
+		}
+	}
+	return d, nil
+}
+
 // GetParameters the specified service credential binding details
 func (c *ServiceCredentialBindingClient) GetParameters(ctx context.Context, guid string) (map[string]string, error) {
 	var p map[string]string
@@ -131,7 +148,17 @@ func (c *ServiceCredentialBindingClient) ListAll(ctx context.Context, opts *Serv
 	if opts == nil {
 		opts = NewServiceCredentialBindingListOptions()
 	}
-	return AutoPage[*ServiceCredentialBindingListOptions, *resource.ServiceCredentialBinding](opts, func(opts *ServiceCredentialBindingListOptions) ([]*resource.ServiceCredentialBinding, *Pager, error) {
+	return autoPage[*ServiceCredentialBindingListOptions, *resource.ServiceCredentialBinding](ctx, c.client, opts, func(ctx context.Context, opts *ServiceCredentialBindingListOptions) ([]*resource.ServiceCredentialBinding, *Pager, error) {
+		return c.List(ctx, opts)
+	})
+}
+
+// IterateAll lazily retrieves all ServiceCredentialBindings the user has access to, fetching each page only as it's needed
+func (c *ServiceCredentialBindingClient) IterateAll(ctx context.Context, opts *ServiceCredentialBindingListOptions) *Iterator[*ServiceCredentialBindingListOptions, *resource.ServiceCredentialBinding] {
+	if opts == nil {
+		opts = NewServiceCredentialBindingListOptions()
+	}
+	return Iterate[*ServiceCredentialBindingListOptions, *resource.ServiceCredentialBinding](ctx, opts, func(opts *ServiceCredentialBindingListOptions) ([]*resource.ServiceCredentialBinding, *Pager, error) {
 		return c.List(ctx, opts)
 	})
 }
@@ -214,6 +241,16 @@ func (c *ServiceCredentialBindingClient) ListIncludeServiceInstancesAll(ctx cont
 	return all, allServiceInstances, nil
 }
 
+// PollLastOperation waits until the service broker finishes the asynchronous create or delete operation
+// on the service credential binding. If the broker reports the operation failed a LastOperationFailedError
+// with the broker's description is returned
+func (c *ServiceCredentialBindingClient) PollLastOperation(ctx context.Context, guid string, opts *PollingOptions) error {
+	return pollResourceLastOperation(ctx, c.client, path.Format("/v3/service_credential_bindings/%s", guid),
+		func(b *resource.ServiceCredentialBinding) *resource.LastOperation {
+			return &b.LastOperation
+		}, opts)
+}
+
 // Single returns a single service credential binding matching the options or an error if not exactly 1 match
 func (c *ServiceCredentialBindingClient) Single(ctx context.Context, opts *ServiceCredentialBindingListOptions) (*resource.ServiceCredentialBinding, error) {
 	return Single[*ServiceCredentialBindingListOptions, *resource.ServiceCredentialBinding](opts, func(opts *ServiceCredentialBindingListOptions) ([]*resource.ServiceCredentialBinding, *Pager, error) {
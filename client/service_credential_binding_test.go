@@ -3,7 +3,9 @@ package client
 import (
 	"context"
 	"net/http"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/cloudfoundry/go-cfclient/v3/resource"
 	"github.com/cloudfoundry/go-cfclient/v3/testutil"
@@ -142,6 +144,22 @@ func TestServiceCredentialBindings(t *testing.T) {
 				return c.ServiceCredentialBindings.Get(context.Background(), "59ba6d78-6a21-4321-83a9-f7eacd88b08d")
 			},
 		},
+		{
+			Description: "Poll service credential binding last operation",
+			Route: testutil.MockRoute{
+				Method:   "GET",
+				Endpoint: "/v3/service_credential_bindings/59ba6d78-6a21-4321-83a9-f7eacd88b08d",
+				Output: []string{
+					strings.Replace(scb, `"state": "succeeded"`, `"state": "in progress"`, 1),
+					scb,
+				},
+				Status: http.StatusOK},
+			Action: func(c *Client, t *testing.T) (any, error) {
+				opts := NewPollingOptions()
+				opts.CheckInterval = time.Millisecond
+				return nil, c.ServiceCredentialBindings.PollLastOperation(context.Background(), "59ba6d78-6a21-4321-83a9-f7eacd88b08d", opts)
+			},
+		},
 		{
 			Description: "Get service credential binding detail",
 			Route: testutil.MockRoute{
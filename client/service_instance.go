@@ -157,11 +157,31 @@ func (c *ServiceInstanceClient) ListAll(ctx context.Context, opts *ServiceInstan
 	if opts == nil {
 		opts = NewServiceInstanceListOptions()
 	}
-	return AutoPage[*ServiceInstanceListOptions, *resource.ServiceInstance](opts, func(opts *ServiceInstanceListOptions) ([]*resource.ServiceInstance, *Pager, error) {
+	return autoPage[*ServiceInstanceListOptions, *resource.ServiceInstance](ctx, c.client, opts, func(ctx context.Context, opts *ServiceInstanceListOptions) ([]*resource.ServiceInstance, *Pager, error) {
 		return c.List(ctx, opts)
 	})
 }
 
+// IterateAll lazily retrieves all service instances the user has access to, fetching each page only as it's needed
+func (c *ServiceInstanceClient) IterateAll(ctx context.Context, opts *ServiceInstanceListOptions) *Iterator[*ServiceInstanceListOptions, *resource.ServiceInstance] {
+	if opts == nil {
+		opts = NewServiceInstanceListOptions()
+	}
+	return Iterate[*ServiceInstanceListOptions, *resource.ServiceInstance](ctx, opts, func(opts *ServiceInstanceListOptions) ([]*resource.ServiceInstance, *Pager, error) {
+		return c.List(ctx, opts)
+	})
+}
+
+// PollLastOperation waits until the service broker finishes the asynchronous create, update or delete
+// operation on the managed service instance. If the broker reports the operation failed a
+// LastOperationFailedError with the broker's description is returned
+func (c *ServiceInstanceClient) PollLastOperation(ctx context.Context, guid string, opts *PollingOptions) error {
+	return pollResourceLastOperation(ctx, c.client, path.Format("/v3/service_instances/%s", guid),
+		func(si *resource.ServiceInstance) *resource.LastOperation {
+			return &si.LastOperation
+		}, opts)
+}
+
 // ShareWithSpace shares the service instance with the specified space
 //
 // In order to share into a space the requesting user must be a space developer in the target space
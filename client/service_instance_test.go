@@ -4,7 +4,9 @@ import (
 	"context"
 	"encoding/json"
 	"net/http"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/cloudfoundry/go-cfclient/v3/resource"
 	"github.com/cloudfoundry/go-cfclient/v3/testutil"
@@ -181,6 +183,22 @@ func TestServiceInstances(t *testing.T) {
 				return c.ServiceInstances.Get(context.Background(), "62a3c0fe-5751-4f8f-97c4-28de85962ef8")
 			},
 		},
+		{
+			Description: "Poll service instance last operation",
+			Route: testutil.MockRoute{
+				Method:   "GET",
+				Endpoint: "/v3/service_instances/62a3c0fe-5751-4f8f-97c4-28de85962ef8",
+				Output: []string{
+					strings.Replace(si, `"state": "succeeded"`, `"state": "in progress"`, 1),
+					si,
+				},
+				Status: http.StatusOK},
+			Action: func(c *Client, t *testing.T) (any, error) {
+				opts := NewPollingOptions()
+				opts.CheckInterval = time.Millisecond
+				return nil, c.ServiceInstances.PollLastOperation(context.Background(), "62a3c0fe-5751-4f8f-97c4-28de85962ef8", opts)
+			},
+		},
 		{
 			Description: "Get service instance shared space relationships",
 			Route: testutil.MockRoute{
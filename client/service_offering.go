@@ -76,7 +76,17 @@ func (c *ServiceOfferingClient) ListAll(ctx context.Context, opts *ServiceOfferi
 	if opts == nil {
 		opts = NewServiceOfferingListOptions()
 	}
-	return AutoPage[*ServiceOfferingListOptions, *resource.ServiceOffering](opts, func(opts *ServiceOfferingListOptions) ([]*resource.ServiceOffering, *Pager, error) {
+	return autoPage[*ServiceOfferingListOptions, *resource.ServiceOffering](ctx, c.client, opts, func(ctx context.Context, opts *ServiceOfferingListOptions) ([]*resource.ServiceOffering, *Pager, error) {
+		return c.List(ctx, opts)
+	})
+}
+
+// IterateAll lazily retrieves all service offerings the user has access to, fetching each page only as it's needed
+func (c *ServiceOfferingClient) IterateAll(ctx context.Context, opts *ServiceOfferingListOptions) *Iterator[*ServiceOfferingListOptions, *resource.ServiceOffering] {
+	if opts == nil {
+		opts = NewServiceOfferingListOptions()
+	}
+	return Iterate[*ServiceOfferingListOptions, *resource.ServiceOffering](ctx, opts, func(opts *ServiceOfferingListOptions) ([]*resource.ServiceOffering, *Pager, error) {
 		return c.List(ctx, opts)
 	})
 }
@@ -103,7 +103,17 @@ func (c *ServicePlanClient) ListAll(ctx context.Context, opts *ServicePlanListOp
 	if opts == nil {
 		opts = NewServicePlanListOptions()
 	}
-	return AutoPage[*ServicePlanListOptions, *resource.ServicePlan](opts, func(opts *ServicePlanListOptions) ([]*resource.ServicePlan, *Pager, error) {
+	return autoPage[*ServicePlanListOptions, *resource.ServicePlan](ctx, c.client, opts, func(ctx context.Context, opts *ServicePlanListOptions) ([]*resource.ServicePlan, *Pager, error) {
+		return c.List(ctx, opts)
+	})
+}
+
+// IterateAll lazily retrieves all service plans the user has access to, fetching each page only as it's needed
+func (c *ServicePlanClient) IterateAll(ctx context.Context, opts *ServicePlanListOptions) *Iterator[*ServicePlanListOptions, *resource.ServicePlan] {
+	if opts == nil {
+		opts = NewServicePlanListOptions()
+	}
+	return Iterate[*ServicePlanListOptions, *resource.ServicePlan](ctx, opts, func(opts *ServicePlanListOptions) ([]*resource.ServicePlan, *Pager, error) {
 		return c.List(ctx, opts)
 	})
 }
@@ -121,7 +121,17 @@ func (c *ServiceRouteBindingClient) ListAll(ctx context.Context, opts *ServiceRo
 	if opts == nil {
 		opts = NewServiceRouteBindingListOptions()
 	}
-	return AutoPage[*ServiceRouteBindingListOptions, *resource.ServiceRouteBinding](opts, func(opts *ServiceRouteBindingListOptions) ([]*resource.ServiceRouteBinding, *Pager, error) {
+	return autoPage[*ServiceRouteBindingListOptions, *resource.ServiceRouteBinding](ctx, c.client, opts, func(ctx context.Context, opts *ServiceRouteBindingListOptions) ([]*resource.ServiceRouteBinding, *Pager, error) {
+		return c.List(ctx, opts)
+	})
+}
+
+// IterateAll lazily retrieves all service route bindings the user has access to, fetching each page only as it's needed
+func (c *ServiceRouteBindingClient) IterateAll(ctx context.Context, opts *ServiceRouteBindingListOptions) *Iterator[*ServiceRouteBindingListOptions, *resource.ServiceRouteBinding] {
+	if opts == nil {
+		opts = NewServiceRouteBindingListOptions()
+	}
+	return Iterate[*ServiceRouteBindingListOptions, *resource.ServiceRouteBinding](ctx, opts, func(opts *ServiceRouteBindingListOptions) ([]*resource.ServiceRouteBinding, *Pager, error) {
 		return c.List(ctx, opts)
 	})
 }
@@ -206,6 +216,16 @@ func (c *ServiceRouteBindingClient) ListIncludeServiceInstancesAll(ctx context.C
 	return all, allSIs, nil
 }
 
+// PollLastOperation waits until the service broker finishes the asynchronous create or delete operation
+// on the service route binding. If the broker reports the operation failed a LastOperationFailedError
+// with the broker's description is returned
+func (c *ServiceRouteBindingClient) PollLastOperation(ctx context.Context, guid string, opts *PollingOptions) error {
+	return pollResourceLastOperation(ctx, c.client, path.Format("/v3/service_route_bindings/%s", guid),
+		func(b *resource.ServiceRouteBinding) *resource.LastOperation {
+			return &b.LastOperation
+		}, opts)
+}
+
 // Single returns a single service route binding matching the options or an error if not exactly 1 match
 func (c *ServiceRouteBindingClient) Single(ctx context.Context, opts *ServiceRouteBindingListOptions) (*resource.ServiceRouteBinding, error) {
 	return Single[*ServiceRouteBindingListOptions, *resource.ServiceRouteBinding](opts, func(opts *ServiceRouteBindingListOptions) ([]*resource.ServiceRouteBinding, *Pager, error) {
@@ -3,7 +3,9 @@ package client
 import (
 	"context"
 	"net/http"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/cloudfoundry/go-cfclient/v3/resource"
 	"github.com/cloudfoundry/go-cfclient/v3/testutil"
@@ -115,6 +117,22 @@ func TestServiceRouteBindings(t *testing.T) {
 				return c.ServiceRouteBindings.Get(context.Background(), "3458647f-8358-4427-9a64-9f90392b02f7")
 			},
 		},
+		{
+			Description: "Poll service route binding last operation",
+			Route: testutil.MockRoute{
+				Method:   "GET",
+				Endpoint: "/v3/service_route_bindings/3458647f-8358-4427-9a64-9f90392b02f7",
+				Output: []string{
+					strings.Replace(svcRouteBinding, `"state": "succeeded"`, `"state": "in progress"`, 1),
+					svcRouteBinding,
+				},
+				Status: http.StatusOK},
+			Action: func(c *Client, t *testing.T) (any, error) {
+				opts := NewPollingOptions()
+				opts.CheckInterval = time.Millisecond
+				return nil, c.ServiceRouteBindings.PollLastOperation(context.Background(), "3458647f-8358-4427-9a64-9f90392b02f7", opts)
+			},
+		},
 		{
 			Description: "Get service route binding include route",
 			Route: testutil.MockRoute{
@@ -67,7 +67,17 @@ func (c *ServiceUsageClient) ListAll(ctx context.Context, opts *ServiceUsageList
 	if opts == nil {
 		opts = NewServiceUsageOptions()
 	}
-	return AutoPage[*ServiceUsageListOptions, *resource.ServiceUsage](opts, func(opts *ServiceUsageListOptions) ([]*resource.ServiceUsage, *Pager, error) {
+	return autoPage[*ServiceUsageListOptions, *resource.ServiceUsage](ctx, c.client, opts, func(ctx context.Context, opts *ServiceUsageListOptions) ([]*resource.ServiceUsage, *Pager, error) {
+		return c.List(ctx, opts)
+	})
+}
+
+// IterateAll lazily retrieves all service usage events, fetching each page only as it's needed
+func (c *ServiceUsageClient) IterateAll(ctx context.Context, opts *ServiceUsageListOptions) *Iterator[*ServiceUsageListOptions, *resource.ServiceUsage] {
+	if opts == nil {
+		opts = NewServiceUsageOptions()
+	}
+	return Iterate[*ServiceUsageListOptions, *resource.ServiceUsage](ctx, opts, func(opts *ServiceUsageListOptions) ([]*resource.ServiceUsage, *Pager, error) {
 		return c.List(ctx, opts)
 	})
 }
@@ -85,7 +85,7 @@ func (c *SidecarClient) ListForAppAll(ctx context.Context, appGUID string, opts
 	if opts == nil {
 		opts = NewSidecarListOptions()
 	}
-	return AutoPage[*SidecarListOptions, *resource.Sidecar](opts, func(opts *SidecarListOptions) ([]*resource.Sidecar, *Pager, error) {
+	return autoPage[*SidecarListOptions, *resource.Sidecar](ctx, c.client, opts, func(ctx context.Context, opts *SidecarListOptions) ([]*resource.Sidecar, *Pager, error) {
 		return c.ListForApp(ctx, appGUID, opts)
 	})
 }
@@ -109,7 +109,7 @@ func (c *SidecarClient) ListForProcessAll(ctx context.Context, processGUID strin
 	if opts == nil {
 		opts = NewSidecarListOptions()
 	}
-	return AutoPage[*SidecarListOptions, *resource.Sidecar](opts, func(opts *SidecarListOptions) ([]*resource.Sidecar, *Pager, error) {
+	return autoPage[*SidecarListOptions, *resource.Sidecar](ctx, c.client, opts, func(ctx context.Context, opts *SidecarListOptions) ([]*resource.Sidecar, *Pager, error) {
 		return c.ListForProcess(ctx, processGUID, opts)
 	})
 }
@@ -125,7 +125,17 @@ func (c *SpaceClient) ListAll(ctx context.Context, opts *SpaceListOptions) ([]*r
 	if opts == nil {
 		opts = NewSpaceListOptions()
 	}
-	return AutoPage[*SpaceListOptions, *resource.Space](opts, func(opts *SpaceListOptions) ([]*resource.Space, *Pager, error) {
+	return autoPage[*SpaceListOptions, *resource.Space](ctx, c.client, opts, func(ctx context.Context, opts *SpaceListOptions) ([]*resource.Space, *Pager, error) {
+		return c.List(ctx, opts)
+	})
+}
+
+// IterateAll lazily retrieves all spaces the user has access to, fetching each page only as it's needed
+func (c *SpaceClient) IterateAll(ctx context.Context, opts *SpaceListOptions) *Iterator[*SpaceListOptions, *resource.Space] {
+	if opts == nil {
+		opts = NewSpaceListOptions()
+	}
+	return Iterate[*SpaceListOptions, *resource.Space](ctx, opts, func(opts *SpaceListOptions) ([]*resource.Space, *Pager, error) {
 		return c.List(ctx, opts)
 	})
 }
@@ -188,7 +198,7 @@ func (c *SpaceClient) ListUsersAll(ctx context.Context, spaceGUID string, opts *
 	if opts == nil {
 		opts = NewUserListOptions()
 	}
-	return AutoPage[*UserListOptions, *resource.User](opts, func(opts *UserListOptions) ([]*resource.User, *Pager, error) {
+	return autoPage[*UserListOptions, *resource.User](ctx, c.client, opts, func(ctx context.Context, opts *UserListOptions) ([]*resource.User, *Pager, error) {
 		return c.ListUsers(ctx, spaceGUID, opts)
 	})
 }
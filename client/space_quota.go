@@ -98,7 +98,17 @@ func (c *SpaceQuotaClient) ListAll(ctx context.Context, opts *SpaceQuotaListOpti
 	if opts == nil {
 		opts = NewSpaceQuotaListOptions()
 	}
-	return AutoPage[*SpaceQuotaListOptions, *resource.SpaceQuota](opts, func(opts *SpaceQuotaListOptions) ([]*resource.SpaceQuota, *Pager, error) {
+	return autoPage[*SpaceQuotaListOptions, *resource.SpaceQuota](ctx, c.client, opts, func(ctx context.Context, opts *SpaceQuotaListOptions) ([]*resource.SpaceQuota, *Pager, error) {
+		return c.List(ctx, opts)
+	})
+}
+
+// IterateAll lazily retrieves all space quotas the user has access to, fetching each page only as it's needed
+func (c *SpaceQuotaClient) IterateAll(ctx context.Context, opts *SpaceQuotaListOptions) *Iterator[*SpaceQuotaListOptions, *resource.SpaceQuota] {
+	if opts == nil {
+		opts = NewSpaceQuotaListOptions()
+	}
+	return Iterate[*SpaceQuotaListOptions, *resource.SpaceQuota](ctx, opts, func(opts *SpaceQuotaListOptions) ([]*resource.SpaceQuota, *Pager, error) {
 		return c.List(ctx, opts)
 	})
 }
@@ -81,7 +81,17 @@ func (c *StackClient) ListAll(ctx context.Context, opts *StackListOptions) ([]*r
 	if opts == nil {
 		opts = NewStackListOptions()
 	}
-	return AutoPage[*StackListOptions, *resource.Stack](opts, func(opts *StackListOptions) ([]*resource.Stack, *Pager, error) {
+	return autoPage[*StackListOptions, *resource.Stack](ctx, c.client, opts, func(ctx context.Context, opts *StackListOptions) ([]*resource.Stack, *Pager, error) {
+		return c.List(ctx, opts)
+	})
+}
+
+// IterateAll lazily retrieves all stacks the user has access to, fetching each page only as it's needed
+func (c *StackClient) IterateAll(ctx context.Context, opts *StackListOptions) *Iterator[*StackListOptions, *resource.Stack] {
+	if opts == nil {
+		opts = NewStackListOptions()
+	}
+	return Iterate[*StackListOptions, *resource.Stack](ctx, opts, func(opts *StackListOptions) ([]*resource.Stack, *Pager, error) {
 		return c.List(ctx, opts)
 	})
 }
@@ -105,7 +115,7 @@ func (c *StackClient) ListAppsOnStackAll(ctx context.Context, guid string, opts
 	if opts == nil {
 		opts = NewStackListOptions()
 	}
-	return AutoPage[*StackListOptions, *resource.App](opts, func(opts *StackListOptions) ([]*resource.App, *Pager, error) {
+	return autoPage[*StackListOptions, *resource.App](ctx, c.client, opts, func(ctx context.Context, opts *StackListOptions) ([]*resource.App, *Pager, error) {
 		return c.ListAppsOnStack(ctx, guid, opts)
 	})
 }
@@ -101,7 +101,17 @@ func (c *TaskClient) ListAll(ctx context.Context, opts *TaskListOptions) ([]*res
 	if opts == nil {
 		opts = NewTaskListOptions()
 	}
-	return AutoPage[*TaskListOptions, *resource.Task](opts, func(opts *TaskListOptions) ([]*resource.Task, *Pager, error) {
+	return autoPage[*TaskListOptions, *resource.Task](ctx, c.client, opts, func(ctx context.Context, opts *TaskListOptions) ([]*resource.Task, *Pager, error) {
+		return c.List(ctx, opts)
+	})
+}
+
+// IterateAll lazily retrieves all tasks the user has access to, fetching each page only as it's needed
+func (c *TaskClient) IterateAll(ctx context.Context, opts *TaskListOptions) *Iterator[*TaskListOptions, *resource.Task] {
+	if opts == nil {
+		opts = NewTaskListOptions()
+	}
+	return Iterate[*TaskListOptions, *resource.Task](ctx, opts, func(opts *TaskListOptions) ([]*resource.Task, *Pager, error) {
 		return c.List(ctx, opts)
 	})
 }
@@ -128,7 +138,7 @@ func (c *TaskClient) ListForAppAll(ctx context.Context, appGUID string, opts *Ta
 	if opts == nil {
 		opts = NewTaskListOptions()
 	}
-	return AutoPage[*TaskListOptions, *resource.Task](opts, func(opts *TaskListOptions) ([]*resource.Task, *Pager, error) {
+	return autoPage[*TaskListOptions, *resource.Task](ctx, c.client, opts, func(ctx context.Context, opts *TaskListOptions) ([]*resource.Task, *Pager, error) {
 		return c.ListForApp(ctx, appGUID, opts)
 	})
 }
@@ -0,0 +1,163 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+
+	"github.com/cloudfoundry/go-cfclient/v3/internal/path"
+	"github.com/cloudfoundry/go-cfclient/v3/resource"
+)
+
+const DefaultUAAPageSize = 100
+
+// UAAClient manages UAA users, groups and OAuth clients via the configured UAA endpoint
+//
+// The client's token must have the appropriate scim and clients scopes.
+type UAAClient commonClient
+
+// UAAListOptions SCIM list filters
+type UAAListOptions struct {
+	Filter     string `qs:"filter"`     // SCIM filter expression, e.g. userName eq "jdoe"
+	SortBy     string `qs:"sortBy"`     // attribute to sort by
+	SortOrder  string `qs:"sortOrder"`  // ascending or descending
+	Attributes string `qs:"attributes"` // comma separated list of attributes to return
+	StartIndex int    `qs:"startIndex"` // 1-based index of the first result
+	Count      int    `qs:"count"`      // max results per page
+}
+
+// NewUAAListOptions creates new options to pass to list
+func NewUAAListOptions() *UAAListOptions {
+	return &UAAListOptions{
+		StartIndex: 1,
+		Count:      DefaultUAAPageSize,
+	}
+}
+
+func (o UAAListOptions) ToQueryString() (url.Values, error) {
+	values := url.Values{}
+	err := serializeField(values, reflect.ValueOf(o))
+	return values, err
+}
+
+// AddGroupMember adds a user or group to the specified group
+func (c *UAAClient) AddGroupMember(ctx context.Context, groupID string, member *resource.UAAGroupMember) (*resource.UAAGroupMember, error) {
+	var m resource.UAAGroupMember
+	err := c.client.executeExternal(ctx, http.MethodPost, c.client.AuthURL(path.Format("/Groups/%s/members", groupID)), member, &m)
+	if err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// ChangeUserPassword changes the password of the specified user, the old password is
+// not required when the caller is an admin
+func (c *UAAClient) ChangeUserPassword(ctx context.Context, id string, r *resource.UAAPasswordChange) error {
+	return c.client.executeExternal(ctx, http.MethodPut, c.client.AuthURL(path.Format("/Users/%s/password", id)), r, nil)
+}
+
+// CreateOAuthClient registers a new OAuth client
+func (c *UAAClient) CreateOAuthClient(ctx context.Context, r *resource.UAAOAuthClient) (*resource.UAAOAuthClient, error) {
+	var oc resource.UAAOAuthClient
+	err := c.client.executeExternal(ctx, http.MethodPost, c.client.AuthURL("/oauth/clients"), r, &oc)
+	if err != nil {
+		return nil, err
+	}
+	return &oc, nil
+}
+
+// CreateUser creates a new UAA user identity
+func (c *UAAClient) CreateUser(ctx context.Context, r *resource.UAAUserCreate) (*resource.UAAUser, error) {
+	var u resource.UAAUser
+	err := c.client.executeExternal(ctx, http.MethodPost, c.client.AuthURL("/Users"), r, &u)
+	if err != nil {
+		return nil, err
+	}
+	return &u, nil
+}
+
+// DeleteOAuthClient deletes the specified OAuth client
+func (c *UAAClient) DeleteOAuthClient(ctx context.Context, clientID string) error {
+	return c.client.executeExternal(ctx, http.MethodDelete, c.client.AuthURL(path.Format("/oauth/clients/%s", clientID)), nil, nil)
+}
+
+// DeleteUser deletes the specified UAA user identity
+func (c *UAAClient) DeleteUser(ctx context.Context, id string) error {
+	return c.client.executeExternal(ctx, http.MethodDelete, c.client.AuthURL(path.Format("/Users/%s", id)), nil, nil)
+}
+
+// GetGroupByName retrieves the group with the specified display name
+func (c *UAAClient) GetGroupByName(ctx context.Context, name string) (*resource.UAAGroup, error) {
+	opts := NewUAAListOptions()
+	opts.Filter = fmt.Sprintf("displayName eq %q", name)
+	groups, err := c.ListGroups(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	if len(groups) != 1 {
+		return nil, ErrExactlyOneResultNotReturned
+	}
+	return groups[0], nil
+}
+
+// GetUser retrieves the specified UAA user identity
+func (c *UAAClient) GetUser(ctx context.Context, id string) (*resource.UAAUser, error) {
+	var u resource.UAAUser
+	err := c.client.executeExternal(ctx, http.MethodGet, c.client.AuthURL(path.Format("/Users/%s", id)), nil, &u)
+	if err != nil {
+		return nil, err
+	}
+	return &u, nil
+}
+
+// ListGroups retrieves all the groups matching the options from every page
+func (c *UAAClient) ListGroups(ctx context.Context, opts *UAAListOptions) ([]*resource.UAAGroup, error) {
+	return uaaListAll[*resource.UAAGroup](ctx, c, "/Groups", opts)
+}
+
+// ListOAuthClients retrieves all the OAuth clients matching the options from every page
+func (c *UAAClient) ListOAuthClients(ctx context.Context, opts *UAAListOptions) ([]*resource.UAAOAuthClient, error) {
+	return uaaListAll[*resource.UAAOAuthClient](ctx, c, "/oauth/clients", opts)
+}
+
+// ListUsers retrieves all the users matching the options from every page
+func (c *UAAClient) ListUsers(ctx context.Context, opts *UAAListOptions) ([]*resource.UAAUser, error) {
+	return uaaListAll[*resource.UAAUser](ctx, c, "/Users", opts)
+}
+
+// RemoveGroupMember removes the user or group from the specified group
+func (c *UAAClient) RemoveGroupMember(ctx context.Context, groupID, memberID string) error {
+	return c.client.executeExternal(ctx, http.MethodDelete, c.client.AuthURL(path.Format("/Groups/%s/members/%s", groupID, memberID)), nil, nil)
+}
+
+// uaaListAll pages through the SCIM list endpoint using the 1-based start index
+func uaaListAll[R any](ctx context.Context, c *UAAClient, urlPath string, opts *UAAListOptions) ([]R, error) {
+	if opts == nil {
+		opts = NewUAAListOptions()
+	}
+	pageOpts := *opts
+	if pageOpts.StartIndex < 1 {
+		pageOpts.StartIndex = 1
+	}
+
+	var all []R
+	for {
+		params, err := pageOpts.ToQueryString()
+		if err != nil {
+			return nil, fmt.Errorf("error while generate query params: %w", err)
+		}
+		var page resource.UAAList[R]
+		err = c.client.executeExternal(ctx, http.MethodGet, c.client.AuthURL(path.Format(urlPath+"?%s", params)), nil, &page)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, page.Resources...)
+		if len(page.Resources) == 0 || len(all) >= page.TotalResults {
+			break
+		}
+		pageOpts.StartIndex += len(page.Resources)
+	}
+	return all, nil
+}
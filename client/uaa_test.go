@@ -0,0 +1,209 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/cloudfoundry/go-cfclient/v3/resource"
+	"github.com/cloudfoundry/go-cfclient/v3/testutil"
+)
+
+func TestUAA(t *testing.T) {
+	g := testutil.NewObjectJSONGenerator(1)
+	user := g.UAAUser()
+	user2 := g.UAAUser()
+	group := g.UAAGroup(user.GUID)
+	oauthClient := g.UAAOAuthClient()
+
+	tests := []RouteTest{
+		{
+			Description: "Create UAA user",
+			Route: testutil.MockRoute{
+				Method:   "POST",
+				Endpoint: "/Users",
+				Output:   []string{user.JSON},
+				Status:   http.StatusCreated,
+				PostForm: `{
+					"userName": "jdoe",
+					"password": "secret",
+					"emails": [{ "value": "jdoe@example.org", "primary": true }],
+					"origin": "uaa",
+					"active": true,
+					"verified": true
+				}`,
+			},
+			Expected: user.JSON,
+			Action: func(c *Client, t *testing.T) (any, error) {
+				r := resource.NewUAAUserCreate("jdoe", "secret", "jdoe@example.org")
+				return c.UAA.CreateUser(context.Background(), r)
+			},
+		},
+		{
+			Description: "Get UAA user",
+			Route: testutil.MockRoute{
+				Method:   "GET",
+				Endpoint: "/Users/" + user.GUID,
+				Output:   []string{user.JSON},
+				Status:   http.StatusOK,
+			},
+			Expected: user.JSON,
+			Action: func(c *Client, t *testing.T) (any, error) {
+				return c.UAA.GetUser(context.Background(), user.GUID)
+			},
+		},
+		{
+			Description: "List UAA users by filter",
+			Route: testutil.MockRoute{
+				Method:      "GET",
+				Endpoint:    "/Users",
+				Output:      []string{g.UAAList(1, 1, user.JSON)},
+				Status:      http.StatusOK,
+				QueryString: `count=100&filter=userName eq "` + user.Name + `"&startIndex=1`,
+			},
+			Expected: g.Array(user.JSON),
+			Action: func(c *Client, t *testing.T) (any, error) {
+				opts := NewUAAListOptions()
+				opts.Filter = `userName eq "` + user.Name + `"`
+				return c.UAA.ListUsers(context.Background(), opts)
+			},
+		},
+		{
+			Description: "List all UAA users across pages",
+			Route: testutil.MockRoute{
+				Method:   "GET",
+				Endpoint: "/Users",
+				Output: []string{
+					g.UAAList(1, 2, user.JSON),
+					g.UAAList(2, 2, user2.JSON),
+				},
+				Status: http.StatusOK,
+			},
+			Expected: g.Array(user.JSON, user2.JSON),
+			Action: func(c *Client, t *testing.T) (any, error) {
+				opts := NewUAAListOptions()
+				opts.Count = 1
+				return c.UAA.ListUsers(context.Background(), opts)
+			},
+		},
+		{
+			Description: "Delete UAA user",
+			Route: testutil.MockRoute{
+				Method:   "DELETE",
+				Endpoint: "/Users/" + user.GUID,
+				Output:   []string{user.JSON},
+				Status:   http.StatusOK,
+			},
+			Action: func(c *Client, t *testing.T) (any, error) {
+				return nil, c.UAA.DeleteUser(context.Background(), user.GUID)
+			},
+		},
+		{
+			Description: "Change UAA user password",
+			Route: testutil.MockRoute{
+				Method:   "PUT",
+				Endpoint: "/Users/" + user.GUID + "/password",
+				Output:   []string{`{"status":"ok","message":"password updated"}`},
+				Status:   http.StatusOK,
+				PostForm: `{ "oldPassword": "old", "password": "new" }`,
+			},
+			Action: func(c *Client, t *testing.T) (any, error) {
+				return nil, c.UAA.ChangeUserPassword(context.Background(), user.GUID, &resource.UAAPasswordChange{
+					OldPassword: "old",
+					Password:    "new",
+				})
+			},
+		},
+		{
+			Description: "Get UAA group by name",
+			Route: testutil.MockRoute{
+				Method:      "GET",
+				Endpoint:    "/Groups",
+				Output:      []string{g.UAAList(1, 1, group.JSON)},
+				Status:      http.StatusOK,
+				QueryString: `count=100&filter=displayName eq "` + group.Name + `"&startIndex=1`,
+			},
+			Expected: group.JSON,
+			Action: func(c *Client, t *testing.T) (any, error) {
+				return c.UAA.GetGroupByName(context.Background(), group.Name)
+			},
+		},
+		{
+			Description: "Add UAA group member",
+			Route: testutil.MockRoute{
+				Method:   "POST",
+				Endpoint: "/Groups/" + group.GUID + "/members",
+				Output:   []string{`{"origin":"uaa","type":"USER","value":"` + user.GUID + `"}`},
+				Status:   http.StatusCreated,
+				PostForm: `{"origin":"uaa","type":"USER","value":"` + user.GUID + `"}`,
+			},
+			Expected: `{"origin":"uaa","type":"USER","value":"` + user.GUID + `"}`,
+			Action: func(c *Client, t *testing.T) (any, error) {
+				m := resource.NewUAAGroupUserMember(user.GUID, resource.UAAOriginUAA)
+				return c.UAA.AddGroupMember(context.Background(), group.GUID, m)
+			},
+		},
+		{
+			Description: "Remove UAA group member",
+			Route: testutil.MockRoute{
+				Method:   "DELETE",
+				Endpoint: "/Groups/" + group.GUID + "/members/" + user.GUID,
+				Output:   []string{`{"origin":"uaa","type":"USER","value":"` + user.GUID + `"}`},
+				Status:   http.StatusOK,
+			},
+			Action: func(c *Client, t *testing.T) (any, error) {
+				return nil, c.UAA.RemoveGroupMember(context.Background(), group.GUID, user.GUID)
+			},
+		},
+		{
+			Description: "List OAuth clients",
+			Route: testutil.MockRoute{
+				Method:   "GET",
+				Endpoint: "/oauth/clients",
+				Output:   []string{g.UAAList(1, 1, oauthClient.JSON)},
+				Status:   http.StatusOK,
+			},
+			Expected: g.Array(oauthClient.JSON),
+			Action: func(c *Client, t *testing.T) (any, error) {
+				return c.UAA.ListOAuthClients(context.Background(), nil)
+			},
+		},
+		{
+			Description: "Create OAuth client",
+			Route: testutil.MockRoute{
+				Method:   "POST",
+				Endpoint: "/oauth/clients",
+				Output:   []string{oauthClient.JSON},
+				Status:   http.StatusCreated,
+				PostForm: `{
+					"client_id": "my-client",
+					"client_secret": "secret",
+					"authorized_grant_types": ["client_credentials"],
+					"authorities": ["cloud_controller.admin"]
+				}`,
+			},
+			Expected: oauthClient.JSON,
+			Action: func(c *Client, t *testing.T) (any, error) {
+				return c.UAA.CreateOAuthClient(context.Background(), &resource.UAAOAuthClient{
+					ClientID:             "my-client",
+					ClientSecret:         "secret",
+					AuthorizedGrantTypes: []string{"client_credentials"},
+					Authorities:          []string{"cloud_controller.admin"},
+				})
+			},
+		},
+		{
+			Description: "Delete OAuth client",
+			Route: testutil.MockRoute{
+				Method:   "DELETE",
+				Endpoint: "/oauth/clients/" + oauthClient.Name,
+				Output:   []string{oauthClient.JSON},
+				Status:   http.StatusOK,
+			},
+			Action: func(c *Client, t *testing.T) (any, error) {
+				return nil, c.UAA.DeleteOAuthClient(context.Background(), oauthClient.Name)
+			},
+		},
+	}
+	ExecuteTests(tests, t)
+}
@@ -0,0 +1,276 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/cloudfoundry/go-cfclient/v3/internal/ios"
+)
+
+// ErrUploadNotReplayable is returned when an upload has to be resent, for example after re-authenticating,
+// but its content could only be read once. Upload an *os.File or an io.ReaderAt with a Size method, like a
+// *bytes.Reader, so the content can be read again
+var ErrUploadNotReplayable = errors.New("the upload content can only be read once so it can't be resent, " +
+	"upload a file or an io.ReaderAt with a Size method instead")
+
+// UploadProgressFunc is called as an upload's file content is sent with the number of bytes sent so far and
+// the total number of bytes, which is -1 if the size isn't known. If the upload is resent the number of
+// bytes sent starts again from 0
+type UploadProgressFunc func(sent, total int64)
+
+type uploadProgressKey struct{}
+
+// WithUploadProgress returns a context that reports the progress of any file uploads made with it to the
+// progress func
+func WithUploadProgress(ctx context.Context, progress UploadProgressFunc) context.Context {
+	return context.WithValue(ctx, uploadProgressKey{}, progress)
+}
+
+func uploadProgress(ctx context.Context) UploadProgressFunc {
+	progress, _ := ctx.Value(uploadProgressKey{}).(UploadProgressFunc)
+	return progress
+}
+
+// uploadSource opens the content of an uploaded file, ideally more than once so the upload can be resent
+type uploadSource interface {
+	// Open returns a reader of the content from the beginning
+	Open() (io.ReadCloser, error)
+
+	// Size returns the size of the content or -1 if it isn't known
+	Size() int64
+}
+
+// newUploadSource wraps the content in a source that can be read again when the content is a file,
+// an io.ReaderAt with a Size or an io.Seeker, otherwise the content can only be read once
+func newUploadSource(content io.Reader) uploadSource {
+	switch r := content.(type) {
+	case *os.File:
+		if fi, err := r.Stat(); err == nil && fi.Mode().IsRegular() {
+			if offset, err := r.Seek(0, io.SeekCurrent); err == nil {
+				return &readerAtSource{r: r, offset: offset, size: fi.Size() - offset}
+			}
+		}
+	case interface {
+		io.ReaderAt
+		Size() int64
+	}:
+		// bytes.Reader, strings.Reader and io.SectionReader, where Size is the total size
+		offset := int64(0)
+		if s, ok := content.(io.Seeker); ok {
+			if o, err := s.Seek(0, io.SeekCurrent); err == nil {
+				offset = o
+			}
+		}
+		return &readerAtSource{r: r, offset: offset, size: r.Size() - offset}
+	case io.ReadSeeker:
+		if start, err := r.Seek(0, io.SeekCurrent); err == nil {
+			if end, err := r.Seek(0, io.SeekEnd); err == nil {
+				if _, err = r.Seek(start, io.SeekStart); err == nil {
+					return &seekerSource{r: r, start: start, size: end - start}
+				}
+			}
+		}
+	}
+	return &onceSource{r: content}
+}
+
+// readerAtSource reads the content through independent section readers
+type readerAtSource struct {
+	r      io.ReaderAt
+	offset int64
+	size   int64
+}
+
+func (s *readerAtSource) Open() (io.ReadCloser, error) {
+	return io.NopCloser(io.NewSectionReader(s.r, s.offset, s.size)), nil
+}
+
+func (s *readerAtSource) Size() int64 {
+	return s.size
+}
+
+// seekerSource seeks back to the start of the content each time it's opened
+type seekerSource struct {
+	r     io.ReadSeeker
+	start int64
+	size  int64
+}
+
+func (s *seekerSource) Open() (io.ReadCloser, error) {
+	if _, err := s.r.Seek(s.start, io.SeekStart); err != nil {
+		return nil, fmt.Errorf("error seeking to the start of the upload content: %w", err)
+	}
+	return io.NopCloser(io.LimitReader(s.r, s.size)), nil
+}
+
+func (s *seekerSource) Size() int64 {
+	return s.size
+}
+
+// onceSource can only be opened once
+type onceSource struct {
+	r      io.Reader
+	opened bool
+}
+
+func (s *onceSource) Open() (io.ReadCloser, error) {
+	if s.opened {
+		return nil, ErrUploadNotReplayable
+	}
+	s.opened = true
+	return io.NopCloser(s.r), nil
+}
+
+func (s *onceSource) Size() int64 {
+	return -1
+}
+
+// multipartUpload streams a multipart form of fields and an optional file through a pipe, so the file
+// is never held in memory
+type multipartUpload struct {
+	boundary  string
+	fields    map[string]string
+	fieldName string
+	fileName  string
+	file      uploadSource
+	progress  UploadProgressFunc
+}
+
+func newMultipartUpload(ctx context.Context, fields map[string]string, fieldName, fileName string, fileContent io.Reader) *multipartUpload {
+	u := &multipartUpload{
+		boundary:  multipart.NewWriter(io.Discard).Boundary(),
+		fields:    fields,
+		fieldName: fieldName,
+		fileName:  filepath.Base(fileName),
+		progress:  uploadProgress(ctx),
+	}
+	if fileContent != nil {
+		u.file = newUploadSource(fileContent)
+	}
+	return u
+}
+
+// newRequest creates a POST request that streams the multipart form and can recreate the body
+// if the request needs to be resent
+func (u *multipartUpload) newRequest(ctx context.Context, url string) (*http.Request, error) {
+	body, err := u.body()
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, body)
+	if err != nil {
+		ios.Close(body)
+		return nil, fmt.Errorf("error creating request: %w", err)
+	}
+	req.GetBody = u.body
+	req.ContentLength = u.contentLength()
+	req.Header.Set("Content-Type", "multipart/form-data; boundary="+u.boundary)
+	return req, nil
+}
+
+// body opens the file and returns a reader of the multipart form that's written as it's read
+func (u *multipartUpload) body() (io.ReadCloser, error) {
+	var file io.ReadCloser
+	if u.file != nil {
+		var err error
+		if file, err = u.file.Open(); err != nil {
+			return nil, err
+		}
+	}
+	var content io.Reader = file
+	if file != nil && u.progress != nil {
+		content = &progressReader{r: file, total: u.file.Size(), progress: u.progress}
+	}
+	pr, pw := io.Pipe()
+	go func() {
+		err := u.write(pw, content)
+		if file != nil {
+			ios.Close(file)
+		}
+		_ = pw.CloseWithError(err)
+	}()
+	return pr, nil
+}
+
+// write writes the multipart form, returning early if the reader is closed
+func (u *multipartUpload) write(w io.Writer, file io.Reader) error {
+	formWriter := multipart.NewWriter(w)
+	if err := formWriter.SetBoundary(u.boundary); err != nil {
+		return err
+	}
+
+	// sort the fields so the form is the same each time it's written
+	names := make([]string, 0, len(u.fields))
+	for name := range u.fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := formWriter.WriteField(name, u.fields[name]); err != nil {
+			return fmt.Errorf("failed to write %s field: %w", name, err)
+		}
+	}
+
+	if file != nil {
+		part, err := formWriter.CreateFormFile(u.fieldName, u.fileName)
+		if err != nil {
+			return err
+		}
+		if _, err = io.Copy(part, file); err != nil {
+			return fmt.Errorf("failed on copy: %w", err)
+		}
+	}
+	return formWriter.Close()
+}
+
+// contentLength calculates the length of the form from the size of the file, or returns -1 if the size
+// of the file isn't known
+func (u *multipartUpload) contentLength() int64 {
+	var fileSize int64
+	var emptyFile io.Reader
+	if u.file != nil {
+		if fileSize = u.file.Size(); fileSize < 0 {
+			return -1
+		}
+		emptyFile = strings.NewReader("")
+	}
+	cw := &countingWriter{}
+	if err := u.write(cw, emptyFile); err != nil {
+		return -1
+	}
+	return cw.n + fileSize
+}
+
+type countingWriter struct {
+	n int64
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	w.n += int64(len(p))
+	return len(p), nil
+}
+
+// progressReader reports the number of bytes read to the progress func
+type progressReader struct {
+	r        io.Reader
+	sent     int64
+	total    int64
+	progress UploadProgressFunc
+}
+
+func (r *progressReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	if n > 0 {
+		r.sent += int64(n)
+		r.progress(r.sent, r.total)
+	}
+	return n, err
+}
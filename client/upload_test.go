@@ -0,0 +1,128 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cloudfoundry/go-cfclient/v3/config"
+	"github.com/cloudfoundry/go-cfclient/v3/testutil"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMultipartUpload(t *testing.T) {
+	var progress [][2]int64
+	ctx := WithUploadProgress(context.Background(), func(sent, total int64) {
+		progress = append(progress, [2]int64{sent, total})
+	})
+	fields := map[string]string{"resources": `[]`}
+	upload := newMultipartUpload(ctx, fields, "bits", "dir/package.zip", bytes.NewReader([]byte("zip contents")))
+	req, err := upload.newRequest(ctx, "https://api.example.org/v3/packages/guid/upload")
+	require.NoError(t, err)
+	require.NotNil(t, req.GetBody)
+
+	body, err := io.ReadAll(req.Body)
+	require.NoError(t, err)
+	require.Equal(t, int64(len(body)), req.ContentLength)
+	require.Equal(t, [2]int64{12, 12}, progress[len(progress)-1])
+
+	// the form is multipart with the field and the file
+	_, params, err := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	require.NoError(t, err)
+	form, err := multipart.NewReader(bytes.NewReader(body), params["boundary"]).ReadForm(1024)
+	require.NoError(t, err)
+	require.Equal(t, []string{"[]"}, form.Value["resources"])
+	require.Equal(t, "package.zip", form.File["bits"][0].Filename)
+	f, err := form.File["bits"][0].Open()
+	require.NoError(t, err)
+	content, err := io.ReadAll(f)
+	require.NoError(t, err)
+	require.Equal(t, "zip contents", string(content))
+
+	// the body can be recreated to resend the request
+	replay, err := req.GetBody()
+	require.NoError(t, err)
+	replayed, err := io.ReadAll(replay)
+	require.NoError(t, err)
+	require.Equal(t, body, replayed)
+
+	// a plain reader is streamed once with an unknown length
+	upload = newMultipartUpload(ctx, nil, "bits", "droplet.tgz", struct{ io.Reader }{strings.NewReader("droplet")})
+	req, err = upload.newRequest(ctx, "https://api.example.org/v3/droplets/guid/upload")
+	require.NoError(t, err)
+	require.Equal(t, int64(-1), req.ContentLength)
+	body, err = io.ReadAll(req.Body)
+	require.NoError(t, err)
+	require.Contains(t, string(body), "droplet")
+	_, err = req.GetBody()
+	require.ErrorIs(t, err, ErrUploadNotReplayable)
+}
+
+func TestUploadSource(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "droplet.tgz")
+	require.NoError(t, os.WriteFile(path, []byte("headerdroplet"), 0644))
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+	_, err = f.Seek(6, io.SeekStart)
+	require.NoError(t, err)
+
+	sources := map[string]io.Reader{
+		"file":         f,
+		"readerAt":     bytes.NewReader([]byte("droplet")),
+		"seeker":       struct{ io.ReadSeeker }{strings.NewReader("droplet")},
+		"sectionStart": io.NewSectionReader(strings.NewReader("droplet"), 0, 7),
+	}
+	for name, r := range sources {
+		src := newUploadSource(r)
+		require.Equal(t, int64(7), src.Size(), name)
+		for i := 0; i < 2; i++ {
+			rc, err := src.Open()
+			require.NoError(t, err, name)
+			b, err := io.ReadAll(rc)
+			require.NoError(t, err, name)
+			require.Equal(t, "droplet", string(b), name)
+		}
+	}
+
+	src := newUploadSource(struct{ io.Reader }{strings.NewReader("droplet")})
+	require.Equal(t, int64(-1), src.Size())
+	_, err = src.Open()
+	require.NoError(t, err)
+	_, err = src.Open()
+	require.ErrorIs(t, err, ErrUploadNotReplayable)
+}
+
+func TestUploadResentAfterReauthenticating(t *testing.T) {
+	g := testutil.NewObjectJSONGenerator(1)
+	droplet := g.Droplet().JSON
+	serverURL := testutil.Setup(testutil.MockRoute{
+		Method:   "POST-FILE",
+		Endpoint: "/v3/droplets/59c3d133-2b83-46f3-960e-7765a129aea4/upload",
+		Output:   []string{"", droplet},
+		Statuses: []int{http.StatusUnauthorized, http.StatusOK},
+		PostForm: "droplet bits",
+	}, t)
+	defer testutil.Teardown()
+
+	c, _ := config.New(serverURL, config.Token("", "fake-refresh-token"))
+	cf, err := New(c)
+	require.NoError(t, err)
+
+	var sent int64
+	ctx := WithUploadProgress(context.Background(), func(s, total int64) {
+		sent = s
+	})
+	_, d, err := cf.Droplets.Upload(ctx, "59c3d133-2b83-46f3-960e-7765a129aea4", strings.NewReader("droplet bits"))
+	require.NoError(t, err)
+	require.NotEmpty(t, d.GUID)
+	require.Equal(t, int64(12), sent)
+}
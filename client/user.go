@@ -92,7 +92,17 @@ func (c *UserClient) ListAll(ctx context.Context, opts *UserListOptions) ([]*res
 	if opts == nil {
 		opts = NewUserListOptions()
 	}
-	return AutoPage[*UserListOptions, *resource.User](opts, func(opts *UserListOptions) ([]*resource.User, *Pager, error) {
+	return autoPage[*UserListOptions, *resource.User](ctx, c.client, opts, func(ctx context.Context, opts *UserListOptions) ([]*resource.User, *Pager, error) {
+		return c.List(ctx, opts)
+	})
+}
+
+// IterateAll lazily retrieves all users the user has access to, fetching each page only as it's needed
+func (c *UserClient) IterateAll(ctx context.Context, opts *UserListOptions) *Iterator[*UserListOptions, *resource.User] {
+	if opts == nil {
+		opts = NewUserListOptions()
+	}
+	return Iterate[*UserListOptions, *resource.User](ctx, opts, func(opts *UserListOptions) ([]*resource.User, *Pager, error) {
 		return c.List(ctx, opts)
 	})
 }
@@ -9,6 +9,7 @@ import (
 	"net/url"
 	"os"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"golang.org/x/oauth2"
@@ -30,6 +31,8 @@ const (
 	DefaultUserAgent      = "Go-CF-Client/3.0"
 	DefaultClientID       = "cf"
 	DefaultSSHClientID    = "ssh-proxy"
+
+	DefaultRateLimitThreshold = 0.1
 )
 
 var ErrConfigInvalid = errors.New("configuration is invalid")
@@ -54,6 +57,12 @@ type Config struct {
 	skipTLSValidation bool
 	requestTimeout    time.Duration
 	userAgent         string
+	listConcurrency   int
+	listPagesPerSec   float64
+	retryPolicy       *RetryPolicy
+	rateLimitThresh   float64
+	rateLimiter       *internal.RateLimiter
+	retryCount        atomic.Int64
 
 	initialized bool
 }
@@ -65,11 +74,12 @@ func New(apiRootURL string, options ...Option) (*Config, error) {
 		return nil, fmt.Errorf("expected an http(s) CF API root URI, but got %s: %w", apiRootURL, err)
 	}
 	cfg := &Config{
-		apiEndpointURL: strings.TrimRight(u.String(), "/"),
-		userAgent:      DefaultUserAgent,
-		requestTimeout: DefaultRequestTimeout,
-		clientID:       DefaultClientID,
-		sshOAuthClient: DefaultSSHClientID,
+		apiEndpointURL:  strings.TrimRight(u.String(), "/"),
+		userAgent:       DefaultUserAgent,
+		requestTimeout:  DefaultRequestTimeout,
+		clientID:        DefaultClientID,
+		sshOAuthClient:  DefaultSSHClientID,
+		rateLimitThresh: DefaultRateLimitThreshold,
 	}
 	err = initConfig(cfg, options...)
 	if err != nil {
@@ -186,6 +196,35 @@ func (c *Config) HTTPAuthClient() *http.Client {
 	return c.httpAuthClient
 }
 
+// ListConcurrency returns the maximum number of pages fetched in parallel by ListAll, 1 if pages are
+// fetched one after another.
+func (c *Config) ListConcurrency() int {
+	if c.listConcurrency < 1 {
+		return 1
+	}
+	return c.listConcurrency
+}
+
+// ListPagesPerSecond returns the maximum rate at which ListAll fetches pages, 0 if unlimited.
+func (c *Config) ListPagesPerSecond() float64 {
+	return c.listPagesPerSec
+}
+
+// RateLimit returns the API request rate limit reported by the most recent response, false if the API hasn't
+// reported a rate limit yet.
+func (c *Config) RateLimit() (RateLimit, bool) {
+	if c.rateLimiter == nil {
+		return RateLimit{}, false
+	}
+	l, ok := c.rateLimiter.Current()
+	return RateLimit(l), ok
+}
+
+// RetryCount returns the total number of requests retried by this config's clients.
+func (c *Config) RetryCount() int64 {
+	return c.retryCount.Load()
+}
+
 // SSHOAuthClientID returns the clientID used to request an SSH code, typically 'ssh-proxy'.
 func (c *Config) SSHOAuthClientID() string {
 	return c.sshOAuthClient
@@ -289,7 +328,19 @@ func configureHTTPClient(c *Config) {
 // createHTTPAuthClient creates the http.Client used for any API calls that require authentication.
 func createHTTPAuthClient(ctx context.Context, c *Config) (err error) {
 	c.httpAuthClient, err = internal.NewAuthenticatedClient(ctx, c.httpClient, c)
-	return err
+	if err != nil {
+		return err
+	}
+	apiURL, err := url.Parse(c.apiEndpointURL)
+	if err != nil {
+		return fmt.Errorf("expected an http(s) CF API root URI, but got %s: %w", c.apiEndpointURL, err)
+	}
+	c.rateLimiter = internal.NewRateLimiter(c.rateLimitThresh)
+	c.httpAuthClient.Transport = internal.NewRateLimitTransport(c.httpAuthClient.Transport, c.rateLimiter, apiURL.Host)
+	if c.retryPolicy != nil {
+		c.httpAuthClient.Transport = internal.NewRetryTransport(c.httpAuthClient.Transport, c.internalRetryPolicy())
+	}
+	return nil
 }
 
 // discoverAuthConfig configures the UAA and Login config properties from the CF API if none were supplied in the
@@ -354,6 +405,7 @@ func createConfigFromCFCLIConfig(cfHomeDir string) (*Config, error) {
 		requestTimeout:    DefaultRequestTimeout,
 		username:          os.Getenv("CF_USERNAME"),
 		password:          os.Getenv("CF_PASSWORD"),
+		rateLimitThresh:   DefaultRateLimitThreshold,
 	}
 	cfg.oAuthToken, _ = jwt.ToOAuth2Token(cf.AccessToken, cf.RefreshToken)
 
@@ -4,6 +4,7 @@ import (
 	"net/http"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/cloudfoundry/go-cfclient/v3/testutil"
 
@@ -224,3 +225,95 @@ func TestNewConfigFromCFHomeDir(t *testing.T) {
 		require.Equal(t, GrantTypePassword, cfg.grantType)
 	})
 }
+
+func TestListPaging(t *testing.T) {
+	t.Run("with defaults", func(t *testing.T) {
+		c, err := New("https://api.example.com",
+			Token(accessToken, refreshToken),
+			AuthTokenURL("https://login.cf.example.com", "https://token.cf.example.com"))
+		require.NoError(t, err)
+		require.Equal(t, 1, c.ListConcurrency())
+		require.Equal(t, 0.0, c.ListPagesPerSecond())
+	})
+
+	t.Run("with concurrency and rate limit", func(t *testing.T) {
+		c, err := New("https://api.example.com",
+			Token(accessToken, refreshToken),
+			AuthTokenURL("https://login.cf.example.com", "https://token.cf.example.com"),
+			ListConcurrency(8),
+			ListRateLimit(20))
+		require.NoError(t, err)
+		require.Equal(t, 8, c.ListConcurrency())
+		require.Equal(t, 20.0, c.ListPagesPerSecond())
+	})
+
+	t.Run("with invalid concurrency", func(t *testing.T) {
+		_, err := New("https://api.example.com",
+			Token(accessToken, refreshToken),
+			AuthTokenURL("https://login.cf.example.com", "https://token.cf.example.com"),
+			ListConcurrency(0))
+		require.EqualError(t, err, "expected a list concurrency of 1 or more, but got 0")
+	})
+}
+
+func TestRetry(t *testing.T) {
+	t.Run("with invalid jitter", func(t *testing.T) {
+		p := DefaultRetryPolicy()
+		p.Jitter = 2
+		_, err := New("https://api.example.com",
+			Token(accessToken, refreshToken),
+			AuthTokenURL("https://login.cf.example.com", "https://token.cf.example.com"),
+			Retry(p))
+		require.EqualError(t, err, "expected a retry jitter between 0 and 1, but got 2")
+	})
+
+	t.Run("with retry policy", func(t *testing.T) {
+		serverURL := testutil.Setup(testutil.MockRoute{
+			Method:    "GET",
+			Endpoint:  "/v3/apps",
+			Output:    []string{"", "", `{"resources":[]}`},
+			Statuses:  []int{http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusOK},
+			UserAgent: "Go-http-client/1.1",
+		}, t)
+		defer testutil.Teardown()
+
+		var attempts []RetryAttempt
+		p := DefaultRetryPolicy()
+		p.MinBackoff = time.Millisecond
+		p.OnRetry = func(a RetryAttempt) {
+			attempts = append(attempts, a)
+		}
+		c, err := New(serverURL, Token("", refreshToken), Retry(p))
+		require.NoError(t, err)
+
+		resp, err := c.HTTPAuthClient().Get(serverURL + "/v3/apps")
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.Equal(t, int64(2), c.RetryCount())
+		require.Len(t, attempts, 2)
+		require.Equal(t, 1, attempts[0].Attempt)
+		require.Equal(t, http.StatusBadGateway, attempts[0].StatusCode)
+		require.Equal(t, http.MethodGet, attempts[1].Method)
+		require.Equal(t, http.StatusServiceUnavailable, attempts[1].StatusCode)
+	})
+}
+
+func TestRateLimit(t *testing.T) {
+	t.Run("with invalid threshold", func(t *testing.T) {
+		_, err := New("https://api.example.com",
+			Token(accessToken, refreshToken),
+			AuthTokenURL("https://login.cf.example.com", "https://token.cf.example.com"),
+			RateLimitThreshold(1.5))
+		require.EqualError(t, err, "expected a rate limit threshold between 0 and 1, but got 1.5")
+	})
+
+	t.Run("before any responses", func(t *testing.T) {
+		c, err := New("https://api.example.com",
+			Token(accessToken, refreshToken),
+			AuthTokenURL("https://login.cf.example.com", "https://token.cf.example.com"),
+			RateLimitThreshold(0.2))
+		require.NoError(t, err)
+		_, ok := c.RateLimit()
+		require.False(t, ok)
+	})
+}
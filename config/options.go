@@ -138,3 +138,57 @@ func SSHOAuthClient(clientID string) Option {
 		return nil
 	}
 }
+
+// ListConcurrency is a functional option to fetch up to the specified number of pages in parallel when
+// listing all resources. Once the first page reports the total number of pages, the remaining pages are
+// fetched concurrently. The default of 1 fetches pages one after another.
+func ListConcurrency(concurrency int) Option {
+	return func(c *Config) error {
+		if concurrency < 1 {
+			return fmt.Errorf("expected a list concurrency of 1 or more, but got %d", concurrency)
+		}
+		c.listConcurrency = concurrency
+		return nil
+	}
+}
+
+// ListRateLimit is a functional option to limit the rate at which pages are fetched when listing all
+// resources in parallel. The default of 0 is unlimited.
+func ListRateLimit(pagesPerSecond float64) Option {
+	return func(c *Config) error {
+		if pagesPerSecond < 0 {
+			return fmt.Errorf("expected a list rate limit of 0 or more pages per second, but got %v", pagesPerSecond)
+		}
+		c.listPagesPerSec = pagesPerSecond
+		return nil
+	}
+}
+
+// Retry is a functional option to automatically retry requests that fail with a transport error or a transient
+// server error, see DefaultRetryPolicy.
+func Retry(policy RetryPolicy) Option {
+	return func(c *Config) error {
+		if policy.MaxRetries < 0 {
+			return fmt.Errorf("expected max retries of 0 or more, but got %d", policy.MaxRetries)
+		}
+		if policy.Jitter < 0 || policy.Jitter > 1 {
+			return fmt.Errorf("expected a retry jitter between 0 and 1, but got %v", policy.Jitter)
+		}
+		c.retryPolicy = &policy
+		return nil
+	}
+}
+
+// RateLimitThreshold is a functional option to set the fraction of the API rate limit below which requests are
+// spread evenly over the remainder of the rate limit window, see DefaultRateLimitThreshold. With a threshold of
+// 0 requests are only delayed once no requests remain. Requests rejected with a 429 are always retried once the
+// rate limit window resets.
+func RateLimitThreshold(threshold float64) Option {
+	return func(c *Config) error {
+		if threshold < 0 || threshold > 1 {
+			return fmt.Errorf("expected a rate limit threshold between 0 and 1, but got %v", threshold)
+		}
+		c.rateLimitThresh = threshold
+		return nil
+	}
+}
@@ -0,0 +1,12 @@
+package config
+
+import (
+	"time"
+)
+
+// RateLimit is the per-user API request rate limit reported by the Cloud Controller
+type RateLimit struct {
+	Limit     int       // the number of requests allowed per window
+	Remaining int       // the number of requests remaining in the current window
+	Reset     time.Time // when the current window ends and remaining is reset to the limit
+}
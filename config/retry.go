@@ -0,0 +1,88 @@
+package config
+
+import (
+	"net/http"
+	"time"
+
+	internal "github.com/cloudfoundry/go-cfclient/v3/internal/http"
+)
+
+const (
+	DefaultMaxRetries = 3
+	DefaultMinBackoff = 500 * time.Millisecond
+	DefaultMaxBackoff = 30 * time.Second
+	DefaultJitter     = 0.2
+)
+
+// RetryPolicy configures the automatic retry of requests that fail with a transport error, like a connection
+// reset, or with a transient server error status code.
+//
+// Only idempotent requests are retried unless RetryNonIdempotent is set, in which case POST and PATCH requests
+// are retried too. The delay between attempts starts at MinBackoff and doubles after every attempt up to
+// MaxBackoff, unless the response has a Retry-After header. Retries stop once the request context is done or
+// the next attempt would start after the context deadline. Note that the configured RequestTimeout applies to
+// all attempts of a request combined.
+type RetryPolicy struct {
+	MaxRetries         int                // max number of retries after the first attempt
+	MinBackoff         time.Duration      // delay before the first retry
+	MaxBackoff         time.Duration      // max delay between retries
+	Jitter             float64            // fraction, between 0 and 1, of the delay that's randomized
+	RetryNonIdempotent bool               // also retry POST and PATCH requests, which may not be safe to resend
+	StatusCodes        []int              // response status codes that are retried
+	OnRetry            func(RetryAttempt) // optionally called before each retry, e.g. for logging
+}
+
+// RetryAttempt describes a failed request that's about to be retried
+type RetryAttempt struct {
+	Attempt    int           // the number of the failed attempt, starting at 1
+	Method     string        // the request method
+	URL        string        // the request URL
+	StatusCode int           // the response status code, 0 if the request failed with Err
+	Err        error         // the transport error, if any
+	Delay      time.Duration // the delay before the next attempt
+}
+
+// DefaultRetryPolicy returns a RetryPolicy that retries idempotent requests up to 3 times on transport errors
+// and 502, 503 and 504 responses
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxRetries: DefaultMaxRetries,
+		MinBackoff: DefaultMinBackoff,
+		MaxBackoff: DefaultMaxBackoff,
+		Jitter:     DefaultJitter,
+		StatusCodes: []int{
+			http.StatusBadGateway,
+			http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout,
+		},
+	}
+}
+
+func (c *Config) internalRetryPolicy() internal.RetryPolicy {
+	p := c.retryPolicy
+	return internal.RetryPolicy{
+		MaxRetries:         p.MaxRetries,
+		MinBackoff:         p.MinBackoff,
+		MaxBackoff:         p.MaxBackoff,
+		Jitter:             p.Jitter,
+		RetryNonIdempotent: p.RetryNonIdempotent,
+		StatusCodes:        p.StatusCodes,
+		OnRetry: func(attempt int, req *http.Request, resp *http.Response, err error, delay time.Duration) {
+			c.retryCount.Add(1)
+			if p.OnRetry == nil {
+				return
+			}
+			a := RetryAttempt{
+				Attempt: attempt,
+				Method:  req.Method,
+				URL:     req.URL.String(),
+				Err:     err,
+				Delay:   delay,
+			}
+			if resp != nil {
+				a.StatusCode = resp.StatusCode
+			}
+			p.OnRetry(a)
+		},
+	}
+}
@@ -10,11 +10,12 @@ import (
 	"github.com/cloudfoundry/go-cfclient/v3/client"
 	"github.com/cloudfoundry/go-cfclient/v3/config"
 	"github.com/cloudfoundry/go-cfclient/v3/operation"
+	"github.com/cloudfoundry/go-cfclient/v3/resource"
 )
 
 func main() {
 	if len(os.Args) != 4 {
-		fmt.Println("expected arguments: org, space, /path/to/spring-music.jar")
+		fmt.Println("expected arguments: org, space, /path/to/spring-music.jar or /path/to/app/dir")
 		os.Exit(1)
 	}
 	org := os.Args[1]
@@ -46,14 +47,24 @@ func runPush(org, space, pathToZip string) error {
 		return err
 	}
 
-	zipFile, err := os.Open(pathToZip)
-	if err != nil {
-		return err
-	}
 	pushOp := operation.NewAppPushOperation(cf, org, space)
-	app, err := pushOp.Push(ctx, manifest.Applications[0], zipFile)
-	if err != nil {
-		return err
+
+	// push an app directory uploading only the files CF hasn't cached, otherwise push the zip
+	var app *resource.App
+	if fi, err := os.Stat(pathToZip); err == nil && fi.IsDir() {
+		app, err = pushOp.PushDir(ctx, manifest.Applications[0], pathToZip)
+		if err != nil {
+			return err
+		}
+	} else {
+		zipFile, err := os.Open(pathToZip)
+		if err != nil {
+			return err
+		}
+		app, err = pushOp.Push(ctx, manifest.Applications[0], zipFile)
+		if err != nil {
+			return err
+		}
 	}
 	fmt.Printf("successfully pushed %s, state: %s\n", app.Name, app.State)
 	return nil
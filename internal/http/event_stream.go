@@ -0,0 +1,65 @@
+package http
+
+import (
+	"bufio"
+	"io"
+	"strings"
+)
+
+const maxEventStreamLineSize = 10 * 1024 * 1024
+
+// Event is a single server-sent event read from a text/event-stream response
+type Event struct {
+	Name string
+	Data string
+}
+
+// EventStreamReader reads server-sent events from a text/event-stream response body
+type EventStreamReader struct {
+	scanner *bufio.Scanner
+}
+
+// NewEventStreamReader creates a new EventStreamReader that reads events from r
+func NewEventStreamReader(r io.Reader) *EventStreamReader {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxEventStreamLineSize)
+	return &EventStreamReader{
+		scanner: scanner,
+	}
+}
+
+// Next blocks until the next complete event is read, returning io.EOF when the stream ends
+func (r *EventStreamReader) Next() (*Event, error) {
+	var event Event
+	var data []string
+	for r.scanner.Scan() {
+		line := r.scanner.Text()
+
+		// a blank line dispatches the event, unless nothing has been read yet
+		if line == "" {
+			if event.Name == "" && len(data) == 0 {
+				continue
+			}
+			event.Data = strings.Join(data, "\n")
+			return &event, nil
+		}
+
+		// lines starting with a colon are comments
+		if strings.HasPrefix(line, ":") {
+			continue
+		}
+
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+		switch field {
+		case "event":
+			event.Name = value
+		case "data":
+			data = append(data, value)
+		}
+	}
+	if err := r.scanner.Err(); err != nil {
+		return nil, err
+	}
+	return nil, io.EOF
+}
@@ -0,0 +1,40 @@
+package http
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventStreamReader(t *testing.T) {
+	stream := ": comment\n\n" +
+		"data: {\"batch\":[]}\n\n" +
+		"event: heartbeat\n" +
+		"data: 1581447006\n\n" +
+		"data: line1\n" +
+		"data: line2\n\n" +
+		"event: closing\n" +
+		"data: closing"
+
+	r := NewEventStreamReader(strings.NewReader(stream))
+
+	e, err := r.Next()
+	require.NoError(t, err)
+	require.Equal(t, "", e.Name)
+	require.Equal(t, `{"batch":[]}`, e.Data)
+
+	e, err = r.Next()
+	require.NoError(t, err)
+	require.Equal(t, "heartbeat", e.Name)
+	require.Equal(t, "1581447006", e.Data)
+
+	e, err = r.Next()
+	require.NoError(t, err)
+	require.Equal(t, "line1\nline2", e.Data)
+
+	// an incomplete event at the end of the stream is discarded
+	_, err = r.Next()
+	require.ErrorIs(t, err, io.EOF)
+}
@@ -0,0 +1,165 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	RateLimitLimitHeader     = "X-RateLimit-Limit"
+	RateLimitRemainingHeader = "X-RateLimit-Remaining"
+	RateLimitResetHeader     = "X-RateLimit-Reset"
+
+	maxRateLimitRetries = 3
+)
+
+// RateLimit is the most recent request rate limit reported by the API
+type RateLimit struct {
+	Limit     int       // the number of requests allowed per window
+	Remaining int       // the number of requests remaining in the current window
+	Reset     time.Time // when the current window ends and remaining is reset to the limit
+}
+
+// RateLimiter tracks the rate limit reported by API responses and delays requests to stay within it
+type RateLimiter struct {
+	// Threshold is the fraction of the limit below which requests are spread evenly over the
+	// rest of the window, 0 only waits once no requests remain
+	Threshold float64
+
+	mu    sync.Mutex
+	limit RateLimit
+	known bool
+}
+
+// NewRateLimiter creates a new RateLimiter that starts slowing down requests once the remaining
+// requests drop below the threshold fraction of the limit
+func NewRateLimiter(threshold float64) *RateLimiter {
+	return &RateLimiter{
+		Threshold: threshold,
+	}
+}
+
+// Current returns the most recently reported rate limit and false if no response has reported one yet
+func (l *RateLimiter) Current() (RateLimit, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.limit, l.known
+}
+
+// reserve returns how long to wait before sending the next request and counts it against the remaining requests
+func (l *RateLimiter) reserve(now time.Time) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if !l.known || !now.Before(l.limit.Reset) {
+		return 0
+	}
+	untilReset := l.limit.Reset.Sub(now)
+	if l.limit.Remaining <= 0 {
+		return untilReset
+	}
+	var delay time.Duration
+	if float64(l.limit.Remaining) <= l.Threshold*float64(l.limit.Limit) {
+		delay = untilReset / time.Duration(l.limit.Remaining)
+	}
+	l.limit.Remaining--
+	return delay
+}
+
+// update records the rate limit reported by the response, if any
+func (l *RateLimiter) update(resp *http.Response) {
+	limit, err := strconv.Atoi(resp.Header.Get(RateLimitLimitHeader))
+	if err != nil {
+		return
+	}
+	remaining, err := strconv.Atoi(resp.Header.Get(RateLimitRemainingHeader))
+	if err != nil {
+		return
+	}
+	reset, err := strconv.ParseInt(resp.Header.Get(RateLimitResetHeader), 10, 64)
+	if err != nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.limit = RateLimit{
+		Limit:     limit,
+		Remaining: remaining,
+		Reset:     time.Unix(reset, 0),
+	}
+	l.known = true
+}
+
+// untilReset returns how long until the current window ends
+func (l *RateLimiter) untilReset(now time.Time) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if !l.known || !now.Before(l.limit.Reset) {
+		return 0
+	}
+	return l.limit.Reset.Sub(now)
+}
+
+// rateLimitTransport wraps a http.RoundTripper and uses a RateLimiter to delay requests to the API host
+// and retry any that are rejected with a 429
+type rateLimitTransport struct {
+	transport http.RoundTripper
+	limiter   *RateLimiter
+	host      string
+}
+
+// NewRateLimitTransport creates a new http.RoundTripper that keeps requests to the API host within the API's
+// rate limit. Requests to any other host, like UAA or log-cache, are sent as is because they neither report
+// nor count against the API's rate limit
+func NewRateLimitTransport(transport http.RoundTripper, limiter *RateLimiter, host string) http.RoundTripper {
+	return &rateLimitTransport{
+		transport: transport,
+		limiter:   limiter,
+		host:      host,
+	}
+}
+
+func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !strings.EqualFold(req.URL.Host, t.host) {
+		return t.transport.RoundTrip(req)
+	}
+
+	// Clone the request body so it can be resent after a 429
+	if err := backupRequestBody(req); err != nil {
+		return nil, err
+	}
+
+	for attempt := 0; ; attempt++ {
+		if err := sleep(req.Context(), t.limiter.reserve(time.Now())); err != nil {
+			return nil, err
+		}
+		resp, err := t.transport.RoundTrip(req)
+		if err != nil {
+			return nil, err
+		}
+		t.limiter.update(resp)
+		if resp.StatusCode != http.StatusTooManyRequests || attempt >= maxRateLimitRetries {
+			return resp, nil
+		}
+
+		// Wait until the window resets, or as long as the API asks
+		delay, ok := RetryAfter(resp)
+		if !ok {
+			delay = t.limiter.untilReset(time.Now())
+		}
+		if deadline, ok := req.Context().Deadline(); ok && time.Until(deadline) < delay {
+			return resp, nil
+		}
+		drainBody(resp)
+		if err := sleep(req.Context(), delay); err != nil {
+			return nil, err
+		}
+		if req.GetBody != nil {
+			if req.Body, err = req.GetBody(); err != nil {
+				return nil, err
+			}
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package http
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func rateLimitResponse(status, limit, remaining int, reset time.Time) *http.Response {
+	h := http.Header{}
+	h.Set(RateLimitLimitHeader, strconv.Itoa(limit))
+	h.Set(RateLimitRemainingHeader, strconv.Itoa(remaining))
+	h.Set(RateLimitResetHeader, strconv.FormatInt(reset.Unix(), 10))
+	return &http.Response{StatusCode: status, Header: h, Body: http.NoBody}
+}
+
+func TestRateLimiter(t *testing.T) {
+	now := time.Now()
+	l := NewRateLimiter(0.1)
+
+	_, ok := l.Current()
+	require.False(t, ok)
+	require.Zero(t, l.reserve(now))
+
+	// no response headers leaves the limit unknown
+	l.update(&http.Response{Header: http.Header{}})
+	_, ok = l.Current()
+	require.False(t, ok)
+
+	reset := now.Add(100 * time.Second).Truncate(time.Second)
+	l.update(rateLimitResponse(http.StatusOK, 100, 50, reset))
+	rl, ok := l.Current()
+	require.True(t, ok)
+	require.Equal(t, RateLimit{Limit: 100, Remaining: 50, Reset: reset}, rl)
+
+	// plenty remaining
+	require.Zero(t, l.reserve(now))
+	rl, _ = l.Current()
+	require.Equal(t, 49, rl.Remaining)
+
+	// below the threshold the rest of the window is spread over the remaining requests
+	l.update(rateLimitResponse(http.StatusOK, 100, 10, reset))
+	require.Equal(t, reset.Sub(now)/10, l.reserve(now))
+
+	// none remaining waits for the reset
+	l.update(rateLimitResponse(http.StatusOK, 100, 0, reset))
+	require.Equal(t, reset.Sub(now), l.reserve(now))
+
+	// once the window has reset there's no delay
+	require.Zero(t, l.reserve(reset.Add(time.Second)))
+}
+
+func TestRateLimitTransport(t *testing.T) {
+	t.Run("retries 429 after the reset", func(t *testing.T) {
+		var calls int32
+		var bodies []string
+		s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			n := atomic.AddInt32(&calls, 1)
+			b, _ := io.ReadAll(r.Body)
+			bodies = append(bodies, string(b))
+			w.Header().Set(RateLimitLimitHeader, "100")
+			w.Header().Set(RateLimitResetHeader, strconv.FormatInt(time.Now().Add(time.Second).Unix(), 10))
+			if n == 1 {
+				w.Header().Set(RateLimitRemainingHeader, "0")
+				w.Header().Set("Retry-After", "1")
+				w.WriteHeader(http.StatusTooManyRequests)
+				return
+			}
+			w.Header().Set(RateLimitRemainingHeader, "99")
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer s.Close()
+
+		l := NewRateLimiter(0.1)
+		u, _ := url.Parse(s.URL)
+		c := &http.Client{Transport: NewRateLimitTransport(http.DefaultTransport, l, u.Host)}
+		start := time.Now()
+		resp, err := c.Post(s.URL, "application/json", strings.NewReader("{}"))
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.Equal(t, int32(2), calls)
+		require.Equal(t, []string{"{}", "{}"}, bodies)
+		require.GreaterOrEqual(t, time.Since(start), time.Second)
+
+		rl, ok := l.Current()
+		require.True(t, ok)
+		require.Equal(t, 100, rl.Limit)
+		require.Equal(t, 99, rl.Remaining)
+	})
+
+	t.Run("gives up after repeated 429s", func(t *testing.T) {
+		var calls int32
+		transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			atomic.AddInt32(&calls, 1)
+			resp := rateLimitResponse(http.StatusTooManyRequests, 100, 0, time.Now())
+			resp.Header.Set("Retry-After", "0")
+			return resp, nil
+		})
+		req, _ := http.NewRequest(http.MethodGet, "https://api.example.org/v3/apps", nil)
+		resp, err := NewRateLimitTransport(transport, NewRateLimiter(0.1), "api.example.org").RoundTrip(req)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+		require.Equal(t, int32(maxRateLimitRetries+1), calls)
+	})
+
+	t.Run("only limits requests to the API host", func(t *testing.T) {
+		l := NewRateLimiter(0.1)
+		l.update(rateLimitResponse(http.StatusOK, 100, 0, time.Now().Add(time.Hour)))
+		var calls int32
+		transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			atomic.AddInt32(&calls, 1)
+			return rateLimitResponse(http.StatusTooManyRequests, 10, 0, time.Now().Add(time.Hour)), nil
+		})
+		req, _ := http.NewRequest(http.MethodGet, "https://log-cache.example.org/api/v1/read/guid", nil)
+		resp, err := NewRateLimitTransport(transport, l, "api.example.org").RoundTrip(req)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+		require.Equal(t, int32(1), calls)
+
+		// the API's rate limit is left as is
+		rl, _ := l.Current()
+		require.Equal(t, 100, rl.Limit)
+	})
+}
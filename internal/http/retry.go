@@ -0,0 +1,159 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy controls which failed requests the retryTransport retries and how long it waits between attempts
+type RetryPolicy struct {
+	MaxRetries         int
+	MinBackoff         time.Duration
+	MaxBackoff         time.Duration
+	Jitter             float64
+	RetryNonIdempotent bool
+	StatusCodes        []int
+	OnRetry            func(attempt int, req *http.Request, resp *http.Response, err error, delay time.Duration)
+}
+
+// retryTransport wraps a http.RoundTripper and retries transport errors and transient server errors with
+// exponential backoff
+type retryTransport struct {
+	transport http.RoundTripper
+	policy    RetryPolicy
+}
+
+// NewRetryTransport creates a new http.RoundTripper that retries requests according to the policy
+func NewRetryTransport(transport http.RoundTripper, policy RetryPolicy) http.RoundTripper {
+	return &retryTransport{
+		transport: transport,
+		policy:    policy,
+	}
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !t.retryable(req) {
+		return t.transport.RoundTrip(req)
+	}
+
+	// Clone the request body so it can be resent
+	if err := backupRequestBody(req); err != nil {
+		return nil, err
+	}
+
+	for attempt := 1; ; attempt++ {
+		resp, err := t.transport.RoundTrip(req)
+		if attempt > t.policy.MaxRetries || !t.shouldRetry(req.Context(), resp, err) {
+			if err != nil && attempt > 1 {
+				err = fmt.Errorf("giving up after %d retries: %w", attempt-1, err)
+			}
+			return resp, err
+		}
+
+		delay := t.backoff(attempt, resp)
+		if deadline, ok := req.Context().Deadline(); ok && time.Until(deadline) < delay {
+			// waiting would exceed the deadline so return what we have
+			return resp, err
+		}
+		if t.policy.OnRetry != nil {
+			t.policy.OnRetry(attempt, req, resp, err, delay)
+		}
+
+		// We're going to retry, consume any response to reuse the connection.
+		if resp != nil {
+			drainBody(resp)
+		}
+		if err := sleep(req.Context(), delay); err != nil {
+			return nil, err
+		}
+
+		// Clone the request body again
+		if req.GetBody != nil {
+			if req.Body, err = req.GetBody(); err != nil {
+				return nil, err
+			}
+		}
+	}
+}
+
+// retryable returns true if the policy allows the request's method to be retried
+func (t *retryTransport) retryable(req *http.Request) bool {
+	if t.policy.MaxRetries < 1 {
+		return false
+	}
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete, http.MethodTrace:
+		return true
+	default:
+		return t.policy.RetryNonIdempotent
+	}
+}
+
+func (t *retryTransport) shouldRetry(ctx context.Context, resp *http.Response, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	if err != nil {
+		return true
+	}
+	for _, code := range t.policy.StatusCodes {
+		if resp.StatusCode == code {
+			return true
+		}
+	}
+	return false
+}
+
+// backoff returns the delay before the next attempt, any Retry-After header takes precedence
+func (t *retryTransport) backoff(attempt int, resp *http.Response) time.Duration {
+	if resp != nil {
+		if d, ok := RetryAfter(resp); ok {
+			return d
+		}
+	}
+	d := t.policy.MinBackoff << (attempt - 1)
+	if d <= 0 || (t.policy.MaxBackoff > 0 && d > t.policy.MaxBackoff) {
+		d = t.policy.MaxBackoff
+	}
+	if t.policy.Jitter > 0 {
+		d += time.Duration(t.policy.Jitter * float64(d) * (2*rand.Float64() - 1))
+	}
+	return d
+}
+
+// RetryAfter parses the response's Retry-After header, which is either a number of seconds or a date
+func RetryAfter(resp *http.Response) (time.Duration, bool) {
+	v := resp.Header.Get("Retry-After")
+	if v == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(v); err == nil {
+		if secs < 0 {
+			return 0, false
+		}
+		return time.Duration(secs) * time.Second, true
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		d := time.Until(t)
+		if d < 0 {
+			d = 0
+		}
+		return d, true
+	}
+	return 0, false
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
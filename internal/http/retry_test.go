@@ -0,0 +1,187 @@
+package http
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetryTransport(t *testing.T) {
+	policy := RetryPolicy{
+		MaxRetries:  3,
+		MinBackoff:  time.Millisecond,
+		MaxBackoff:  10 * time.Millisecond,
+		Jitter:      0.2,
+		StatusCodes: []int{http.StatusBadGateway, http.StatusServiceUnavailable},
+	}
+
+	// server fails with the statuses before succeeding, recording the request bodies
+	server := func(statuses []int, header http.Header, bodies *[]string) (*httptest.Server, *int32) {
+		var calls int32
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			n := atomic.AddInt32(&calls, 1)
+			b, _ := io.ReadAll(r.Body)
+			*bodies = append(*bodies, string(b))
+			if int(n) <= len(statuses) {
+				for k, v := range header {
+					w.Header()[k] = v
+				}
+				w.WriteHeader(statuses[n-1])
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		})), &calls
+	}
+
+	t.Run("retries transient errors", func(t *testing.T) {
+		var bodies []string
+		s, calls := server([]int{http.StatusBadGateway, http.StatusServiceUnavailable}, nil, &bodies)
+		defer s.Close()
+
+		var attempts []int
+		p := policy
+		p.OnRetry = func(attempt int, req *http.Request, resp *http.Response, err error, delay time.Duration) {
+			attempts = append(attempts, attempt)
+			require.LessOrEqual(t, delay, 12*time.Millisecond)
+		}
+		c := &http.Client{Transport: NewRetryTransport(http.DefaultTransport, p)}
+		req, _ := http.NewRequest(http.MethodPut, s.URL, strings.NewReader("body"))
+		resp, err := c.Do(req)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.Equal(t, int32(3), *calls)
+		require.Equal(t, []int{1, 2}, attempts)
+		require.Equal(t, []string{"body", "body", "body"}, bodies)
+	})
+
+	t.Run("gives up after max retries", func(t *testing.T) {
+		var bodies []string
+		s, calls := server([]int{502, 502, 502, 502, 502}, nil, &bodies)
+		defer s.Close()
+
+		c := &http.Client{Transport: NewRetryTransport(http.DefaultTransport, policy)}
+		resp, err := c.Get(s.URL)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusBadGateway, resp.StatusCode)
+		require.Equal(t, int32(4), *calls)
+	})
+
+	t.Run("does not retry non-idempotent requests by default", func(t *testing.T) {
+		var bodies []string
+		s, calls := server([]int{502}, nil, &bodies)
+		defer s.Close()
+
+		c := &http.Client{Transport: NewRetryTransport(http.DefaultTransport, policy)}
+		resp, err := c.Post(s.URL, "application/json", strings.NewReader("{}"))
+		require.NoError(t, err)
+		require.Equal(t, http.StatusBadGateway, resp.StatusCode)
+		require.Equal(t, int32(1), *calls)
+
+		p := policy
+		p.RetryNonIdempotent = true
+		c = &http.Client{Transport: NewRetryTransport(http.DefaultTransport, p)}
+		resp, err = c.Post(s.URL, "application/json", strings.NewReader("{}"))
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.Equal(t, int32(2), *calls)
+		require.Equal(t, []string{"{}", "{}"}, bodies)
+	})
+
+	t.Run("does not retry other statuses", func(t *testing.T) {
+		var bodies []string
+		s, calls := server([]int{http.StatusInternalServerError}, nil, &bodies)
+		defer s.Close()
+
+		c := &http.Client{Transport: NewRetryTransport(http.DefaultTransport, policy)}
+		resp, err := c.Get(s.URL)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+		require.Equal(t, int32(1), *calls)
+	})
+
+	t.Run("honors retry-after", func(t *testing.T) {
+		var bodies []string
+		s, _ := server([]int{http.StatusServiceUnavailable}, http.Header{"Retry-After": {"1"}}, &bodies)
+		defer s.Close()
+
+		var delay time.Duration
+		p := policy
+		p.OnRetry = func(attempt int, req *http.Request, resp *http.Response, err error, d time.Duration) {
+			delay = d
+		}
+		c := &http.Client{Transport: NewRetryTransport(http.DefaultTransport, p)}
+		start := time.Now()
+		resp, err := c.Get(s.URL)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.Equal(t, time.Second, delay)
+		require.GreaterOrEqual(t, time.Since(start), time.Second)
+	})
+
+	t.Run("stops before the context deadline", func(t *testing.T) {
+		var bodies []string
+		s, calls := server([]int{http.StatusServiceUnavailable}, http.Header{"Retry-After": {"60"}}, &bodies)
+		defer s.Close()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		c := &http.Client{Transport: NewRetryTransport(http.DefaultTransport, policy)}
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, s.URL, nil)
+		resp, err := c.Do(req)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+		require.Equal(t, int32(1), *calls)
+	})
+
+	t.Run("retries transport errors", func(t *testing.T) {
+		var calls int32
+		transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			if atomic.AddInt32(&calls, 1) < 3 {
+				return nil, io.ErrUnexpectedEOF
+			}
+			return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody, Header: http.Header{}}, nil
+		})
+		req, _ := http.NewRequest(http.MethodGet, "https://api.example.org/v3/apps", nil)
+		resp, err := NewRetryTransport(transport, policy).RoundTrip(req)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.Equal(t, int32(3), calls)
+
+		calls = -10
+		_, err = NewRetryTransport(transport, policy).RoundTrip(req)
+		require.EqualError(t, err, "giving up after 3 retries: unexpected EOF")
+	})
+}
+
+func TestRetryAfter(t *testing.T) {
+	resp := &http.Response{Header: http.Header{}}
+	_, ok := RetryAfter(resp)
+	require.False(t, ok)
+
+	resp.Header.Set("Retry-After", "120")
+	d, ok := RetryAfter(resp)
+	require.True(t, ok)
+	require.Equal(t, 2*time.Minute, d)
+
+	resp.Header.Set("Retry-After", time.Now().Add(time.Hour).UTC().Format(http.TimeFormat))
+	d, ok = RetryAfter(resp)
+	require.True(t, ok)
+	require.InDelta(t, time.Hour, d, float64(2*time.Second))
+
+	resp.Header.Set("Retry-After", "soon")
+	_, ok = RetryAfter(resp)
+	require.False(t, ok)
+}
+
+type roundTripFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
@@ -0,0 +1,268 @@
+package operation
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/cloudfoundry/go-cfclient/v3/client"
+	"github.com/cloudfoundry/go-cfclient/v3/resource"
+)
+
+// AppFile is a single file or symlink in an app directory to push
+type AppFile struct {
+	Path string      // the slash separated path relative to the app directory
+	SHA1 string      // the hex encoded SHA1 of the file contents, empty for symlinks
+	Size int64       // the size of the file in bytes
+	Mode fs.FileMode // the file mode and permissions
+}
+
+// IsSymlink returns true if the file is a symlink, which is archived as a link instead of the target's contents
+func (f AppFile) IsSymlink() bool {
+	return f.Mode&fs.ModeSymlink != 0
+}
+
+// Resource returns the file's resource match used to check if CF has already cached it
+func (f AppFile) Resource() resource.ResourceMatch {
+	return resource.ResourceMatch{
+		Checksum: resource.ResourceMatchChecksum{
+			Value: f.SHA1,
+		},
+		SizeInBytes: int(f.Size),
+		Path:        f.Path,
+		Mode:        fmt.Sprintf("%04o", f.Mode.Perm()),
+	}
+}
+
+// AppBits is the set of files in an app directory to push, excluding any ignored by the .cfignore file
+type AppBits struct {
+	Dir   string
+	Files []AppFile
+}
+
+// NewAppBits walks the app directory applying any .cfignore rules and fingerprints every file to push
+func NewAppBits(dir string) (*AppBits, error) {
+	ignore, err := LoadCFIgnore(dir)
+	if err != nil {
+		return nil, fmt.Errorf("error reading %s in %s: %w", CFIgnoreFileName, dir, err)
+	}
+	bits := &AppBits{
+		Dir: dir,
+	}
+	err = filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		if rel == "." {
+			return nil
+		}
+		rel = filepath.ToSlash(rel)
+		if ignore.Ignored(rel, d.IsDir()) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.Type()&fs.ModeSymlink != 0 {
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			bits.Files = append(bits.Files, AppFile{Path: rel, Size: info.Size(), Mode: info.Mode()})
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		f, err := fingerprint(p, rel, d)
+		if err != nil {
+			return err
+		}
+		bits.Files = append(bits.Files, f)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error reading app directory %s: %w", dir, err)
+	}
+	return bits, nil
+}
+
+// Resources returns the resource matches for all the regular files, symlinks are always uploaded
+func (b *AppBits) Resources() []resource.ResourceMatch {
+	resources := make([]resource.ResourceMatch, 0, len(b.Files))
+	for _, f := range b.Files {
+		if !f.IsSymlink() {
+			resources = append(resources, f.Resource())
+		}
+	}
+	return resources
+}
+
+// WriteZip writes a zip archive of the files to w
+func (b *AppBits) WriteZip(w io.Writer, files []AppFile) error {
+	zw := zip.NewWriter(w)
+	for _, f := range files {
+		if err := b.addToZip(zw, f); err != nil {
+			return fmt.Errorf("error adding %s to zip: %w", f.Path, err)
+		}
+	}
+	return zw.Close()
+}
+
+// Zip streams a zip archive of the files as it's read, the caller must close the returned reader
+func (b *AppBits) Zip(files []AppFile) io.ReadCloser {
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(b.WriteZip(pw, files))
+	}()
+	return pr
+}
+
+func (b *AppBits) addToZip(zw *zip.Writer, f AppFile) error {
+	header := &zip.FileHeader{
+		Name:   f.Path,
+		Method: zip.Deflate,
+	}
+	header.SetMode(f.Mode)
+	fullPath := filepath.Join(b.Dir, filepath.FromSlash(f.Path))
+	if f.IsSymlink() {
+		// a symlink's entry contains the link target
+		target, err := os.Readlink(fullPath)
+		if err != nil {
+			return err
+		}
+		header.Method = zip.Store
+		fw, err := zw.CreateHeader(header)
+		if err != nil {
+			return err
+		}
+		_, err = io.WriteString(fw, filepath.ToSlash(target))
+		return err
+	}
+
+	fw, err := zw.CreateHeader(header)
+	if err != nil {
+		return err
+	}
+	src, err := os.Open(fullPath)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+	_, err = io.Copy(fw, src)
+	return err
+}
+
+// Upload uploads the files to the package, first asking CF which files it already has cached so that
+// only the remaining files are uploaded
+func (b *AppBits) Upload(ctx context.Context, cf *client.Client, packageGUID string) (*resource.Package, error) {
+	matched, err := cf.ResourceMatches.Create(ctx, &resource.ResourceMatches{
+		Resources: b.Resources(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error matching cached resources: %w", err)
+	}
+
+	cached := make(map[string]bool, len(matched.Resources))
+	for _, r := range matched.Resources {
+		cached[r.Path+"\x00"+r.Checksum.Value] = true
+	}
+	var resources []resource.ResourceMatch
+	var remaining []AppFile
+	for _, f := range b.Files {
+		if cached[f.Path+"\x00"+f.SHA1] {
+			resources = append(resources, f.Resource())
+		} else {
+			remaining = append(remaining, f)
+		}
+	}
+	if len(remaining) == 0 {
+		return cf.Packages.UploadWithResources(ctx, packageGUID, resources, nil)
+	}
+
+	zipFile := b.Zip(remaining)
+	defer zipFile.Close()
+	return cf.Packages.UploadWithResources(ctx, packageGUID, resources, zipFile)
+}
+
+// fingerprint computes the SHA1 of the file contents
+func fingerprint(fullPath, relPath string, d fs.DirEntry) (AppFile, error) {
+	info, err := d.Info()
+	if err != nil {
+		return AppFile{}, err
+	}
+	f, err := os.Open(fullPath)
+	if err != nil {
+		return AppFile{}, err
+	}
+	defer f.Close()
+	h := sha1.New()
+	if _, err = io.Copy(h, f); err != nil {
+		return AppFile{}, err
+	}
+	return AppFile{
+		Path: relPath,
+		SHA1: hex.EncodeToString(h.Sum(nil)),
+		Size: info.Size(),
+		Mode: info.Mode(),
+	}, nil
+}
+
+// OpenAppZip streams a zip archive of the app at the path, which can be an app directory, an existing
+// zip, jar or war archive, or any other single file. The .cfignore rules are applied to directories.
+// The caller must close the returned reader
+func OpenAppZip(appPath string) (io.ReadCloser, error) {
+	fi, err := os.Stat(appPath)
+	if err != nil {
+		return nil, err
+	}
+	if fi.IsDir() {
+		bits, err := NewAppBits(appPath)
+		if err != nil {
+			return nil, err
+		}
+		return bits.Zip(bits.Files), nil
+	}
+
+	archive, err := isZipFile(appPath)
+	if err != nil {
+		return nil, err
+	}
+	if archive {
+		return os.Open(appPath)
+	}
+	f, err := fingerprint(appPath, fi.Name(), fs.FileInfoToDirEntry(fi))
+	if err != nil {
+		return nil, err
+	}
+	bits := &AppBits{
+		Dir:   filepath.Dir(appPath),
+		Files: []AppFile{f},
+	}
+	return bits.Zip(bits.Files), nil
+}
+
+// isZipFile returns true if the file starts with a zip file signature
+func isZipFile(p string) (bool, error) {
+	f, err := os.Open(p)
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+	sig := make([]byte, 4)
+	if _, err = io.ReadFull(f, sig); err != nil {
+		return false, nil
+	}
+	return bytes.Equal(sig, []byte("PK\x03\x04")) || bytes.Equal(sig, []byte("PK\x05\x06")), nil
+}
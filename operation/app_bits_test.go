@@ -0,0 +1,165 @@
+package operation
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cloudfoundry/go-cfclient/v3/client"
+	"github.com/cloudfoundry/go-cfclient/v3/config"
+	"github.com/cloudfoundry/go-cfclient/v3/testutil"
+
+	"github.com/stretchr/testify/require"
+)
+
+func writeAppDir(t *testing.T, files map[string]string) string {
+	dir := t.TempDir()
+	for name, content := range files {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(p), 0755))
+		require.NoError(t, os.WriteFile(p, []byte(content), 0644))
+	}
+	return dir
+}
+
+func TestAppBits(t *testing.T) {
+	dir := writeAppDir(t, map[string]string{
+		".cfignore":           "*.log\nbuild/\n",
+		"manifest.yml":        "applications: []",
+		"app.jar":             "cached jar",
+		"lib/dependency.jar":  "new jar",
+		"lib/debug.log":       "ignored",
+		"build/classes/A.txt": "ignored",
+	})
+	require.NoError(t, os.Chmod(filepath.Join(dir, "app.jar"), 0755))
+
+	bits, err := NewAppBits(dir)
+	require.NoError(t, err)
+	require.Len(t, bits.Files, 2)
+	require.Equal(t, "app.jar", bits.Files[0].Path)
+	require.Equal(t, "lib/dependency.jar", bits.Files[1].Path)
+
+	resources := bits.Resources()
+	require.Equal(t, "0755", resources[0].Mode)
+	require.Equal(t, "0644", resources[1].Mode)
+	require.Equal(t, 10, resources[0].SizeInBytes)
+	require.Equal(t, "c102a1985110c844a76e3c17f671fdb38d7e1adb", resources[0].Checksum.Value)
+
+	var buf bytes.Buffer
+	require.NoError(t, bits.WriteZip(&buf, bits.Files[1:]))
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+	require.Len(t, zr.File, 1)
+	require.Equal(t, "lib/dependency.jar", zr.File[0].Name)
+	require.Equal(t, os.FileMode(0644), zr.File[0].Mode().Perm())
+
+	t.Run("uploads only unmatched files", func(t *testing.T) {
+		serverURL := testutil.SetupFakeAPIServer()
+		defer testutil.Teardown()
+
+		g := testutil.NewObjectJSONGenerator(1)
+		pkg := g.Package("PROCESSING_UPLOAD")
+		cached := fmt.Sprintf(`{"checksum":{"value":"%s"},"size_in_bytes":10,"path":"app.jar","mode":"0755"}`,
+			resources[0].Checksum.Value)
+		testutil.SetupMultiple([]testutil.MockRoute{
+			{
+				Method:   http.MethodPost,
+				Endpoint: "/v3/resource_matches",
+				Output:   []string{`{"resources":[` + cached + `]}`},
+				Status:   http.StatusCreated,
+			},
+			{
+				Method:   "POST-FILE",
+				Endpoint: fmt.Sprintf("/v3/packages/%s/upload", pkg.GUID),
+				Output:   g.Single(pkg.JSON),
+				Status:   http.StatusOK,
+				PostForm: `[` + cached + `]`,
+			},
+		}, t)
+
+		c, _ := config.New(serverURL, config.Token("", "fake-refresh-token"), config.SkipTLSValidation())
+		cf, err := client.New(c)
+		require.NoError(t, err)
+
+		_, err = bits.Upload(context.Background(), cf, pkg.GUID)
+		require.NoError(t, err)
+	})
+}
+
+func TestOpenAppZip(t *testing.T) {
+	readZip := func(t *testing.T, r io.ReadCloser) *zip.Reader {
+		defer r.Close()
+		b, err := io.ReadAll(r)
+		require.NoError(t, err)
+		zr, err := zip.NewReader(bytes.NewReader(b), int64(len(b)))
+		require.NoError(t, err)
+		return zr
+	}
+
+	t.Run("directory", func(t *testing.T) {
+		dir := writeAppDir(t, map[string]string{
+			".cfignore":   "tmp/\n",
+			"bin/run":     "#!/bin/sh",
+			"config.yml":  "port: 8080",
+			"tmp/scratch": "ignored",
+		})
+		require.NoError(t, os.Chmod(filepath.Join(dir, "bin/run"), 0755))
+		require.NoError(t, os.Symlink("config.yml", filepath.Join(dir, "current.yml")))
+
+		r, err := OpenAppZip(dir)
+		require.NoError(t, err)
+		zr := readZip(t, r)
+		require.Len(t, zr.File, 3)
+
+		files := map[string]*zip.File{}
+		for _, f := range zr.File {
+			files[f.Name] = f
+		}
+		require.Equal(t, os.FileMode(0755), files["bin/run"].Mode().Perm())
+		require.Equal(t, os.FileMode(0644), files["config.yml"].Mode().Perm())
+		link := files["current.yml"]
+		require.NotZero(t, link.Mode()&os.ModeSymlink)
+		rc, err := link.Open()
+		require.NoError(t, err)
+		target, _ := io.ReadAll(rc)
+		_ = rc.Close()
+		require.Equal(t, "config.yml", string(target))
+	})
+
+	t.Run("archive", func(t *testing.T) {
+		var buf bytes.Buffer
+		zw := zip.NewWriter(&buf)
+		w, _ := zw.Create("META-INF/MANIFEST.MF")
+		_, _ = w.Write([]byte("Manifest-Version: 1.0"))
+		require.NoError(t, zw.Close())
+		jar := filepath.Join(t.TempDir(), "app.jar")
+		require.NoError(t, os.WriteFile(jar, buf.Bytes(), 0644))
+
+		r, err := OpenAppZip(jar)
+		require.NoError(t, err)
+		defer r.Close()
+		b, err := io.ReadAll(r)
+		require.NoError(t, err)
+		require.Equal(t, buf.Bytes(), b)
+	})
+
+	t.Run("single file", func(t *testing.T) {
+		dir := writeAppDir(t, map[string]string{"index.php": "<?php echo 'hi';"})
+		r, err := OpenAppZip(filepath.Join(dir, "index.php"))
+		require.NoError(t, err)
+		zr := readZip(t, r)
+		require.Len(t, zr.File, 1)
+		require.Equal(t, "index.php", zr.File[0].Name)
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		_, err := OpenAppZip(filepath.Join(t.TempDir(), "missing"))
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,148 @@
+package operation
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// CFIgnoreFileName is the name of the file in an app directory listing files that shouldn't be pushed
+const CFIgnoreFileName = ".cfignore"
+
+// defaultIgnorePatterns are never pushed, the same as the cf CLI
+var defaultIgnorePatterns = []string{
+	".cfignore",
+	"/manifest.yml",
+	".gitignore",
+	".git",
+	".hg",
+	".svn",
+	"_darcs",
+	".DS_Store",
+}
+
+// ignorePattern is a single .gitignore style pattern from a .cfignore file
+type ignorePattern struct {
+	segments []string // the slash separated glob segments
+	negate   bool     // the pattern started with ! and re-includes matching files
+	dirOnly  bool     // the pattern ended with / and only matches directories
+	anchored bool     // the pattern contained a / so is matched against the full path
+}
+
+// CFIgnore matches the files in an app directory that shouldn't be pushed using the .gitignore
+// style patterns from a .cfignore file
+type CFIgnore struct {
+	patterns []ignorePattern
+}
+
+// NewCFIgnore creates a CFIgnore from the default ignore patterns and the .cfignore patterns
+func NewCFIgnore(r io.Reader) (*CFIgnore, error) {
+	ig := &CFIgnore{}
+	for _, p := range defaultIgnorePatterns {
+		ig.add(p)
+	}
+	if r == nil {
+		return ig, nil
+	}
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		ig.add(scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return ig, nil
+}
+
+// LoadCFIgnore creates a CFIgnore from the .cfignore file in the app directory, if there is one
+func LoadCFIgnore(dir string) (*CFIgnore, error) {
+	f, err := os.Open(filepath.Join(dir, CFIgnoreFileName))
+	if errors.Is(err, fs.ErrNotExist) {
+		return NewCFIgnore(nil)
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return NewCFIgnore(f)
+}
+
+func (ig *CFIgnore) add(line string) {
+	line = strings.TrimRight(strings.TrimSuffix(line, "\r"), " ")
+	if line == "" || strings.HasPrefix(line, "#") {
+		return
+	}
+	p := ignorePattern{}
+	if strings.HasPrefix(line, "!") {
+		p.negate = true
+		line = line[1:]
+	}
+	if strings.HasSuffix(line, "/") {
+		p.dirOnly = true
+		line = strings.TrimRight(line, "/")
+	}
+	p.anchored = strings.Contains(line, "/")
+	line = strings.TrimPrefix(line, "/")
+	if line == "" {
+		return
+	}
+	p.segments = strings.Split(line, "/")
+	ig.patterns = append(ig.patterns, p)
+}
+
+// Ignored returns true if the slash separated path relative to the app directory shouldn't be pushed,
+// the last pattern that matches the path wins
+func (ig *CFIgnore) Ignored(relPath string, isDir bool) bool {
+	relPath = strings.Trim(relPath, "/")
+	segments := strings.Split(relPath, "/")
+	ignored := false
+	for _, p := range ig.patterns {
+		if p.negate == !ignored {
+			// the pattern can't change the result
+			continue
+		}
+		if p.matches(segments, isDir) {
+			ignored = !p.negate
+		}
+	}
+	return ignored
+}
+
+func (p ignorePattern) matches(segments []string, isDir bool) bool {
+	if p.dirOnly && !isDir {
+		return false
+	}
+	if !p.anchored {
+		ok, _ := path.Match(p.segments[0], segments[len(segments)-1])
+		return ok
+	}
+	return matchSegments(p.segments, segments)
+}
+
+// matchSegments matches the glob segments against the path segments where a ** segment matches
+// zero or more path segments
+func matchSegments(pattern, segments []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := 0; i <= len(segments); i++ {
+				if matchSegments(pattern[1:], segments[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(segments) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(pattern[0], segments[0]); !ok {
+			return false
+		}
+		pattern, segments = pattern[1:], segments[1:]
+	}
+	return len(segments) == 0
+}
@@ -0,0 +1,52 @@
+package operation
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCFIgnore(t *testing.T) {
+	ignore, err := NewCFIgnore(strings.NewReader(`
+# build output
+target/
+*.log
+!important.log
+/local.properties
+docs/**/*.md
+`))
+	require.NoError(t, err)
+
+	tests := []struct {
+		path    string
+		isDir   bool
+		ignored bool
+	}{
+		{path: ".git", isDir: true, ignored: true},
+		{path: "src/.DS_Store", ignored: true},
+		{path: "manifest.yml", ignored: true},
+		{path: "config/manifest.yml", ignored: false},
+		{path: "target", isDir: true, ignored: true},
+		{path: "src/target", isDir: true, ignored: true},
+		{path: "target", isDir: false, ignored: false},
+		{path: "app.log", ignored: true},
+		{path: "logs/app.log", ignored: true},
+		{path: "important.log", ignored: false},
+		{path: "local.properties", ignored: true},
+		{path: "config/local.properties", ignored: false},
+		{path: "docs/README.md", ignored: true},
+		{path: "docs/api/v3/README.md", ignored: true},
+		{path: "README.md", ignored: false},
+		{path: "src/main/App.java", ignored: false},
+	}
+	for _, tt := range tests {
+		require.Equal(t, tt.ignored, ignore.Ignored(tt.path, tt.isDir), tt.path)
+	}
+
+	// only the defaults are used without a .cfignore file
+	ignore, err = LoadCFIgnore(t.TempDir())
+	require.NoError(t, err)
+	require.True(t, ignore.Ignored(".svn", true))
+	require.False(t, ignore.Ignored("app.log", false))
+}
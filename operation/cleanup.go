@@ -0,0 +1,40 @@
+package operation
+
+import (
+	"context"
+	"time"
+)
+
+// cleanupTimeout bounds how long rolling back or cancelling a failed operation may take
+const cleanupTimeout = 5 * time.Minute
+
+// cleanupContext returns a context to roll back or cancel a failed operation with. It keeps the values of
+// ctx but isn't cancelled with it, so an operation that failed because ctx was cancelled or timed out can
+// still undo its changes
+func cleanupContext(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout < cleanupTimeout {
+		timeout = cleanupTimeout
+	}
+	return context.WithTimeout(detachedContext{parent: ctx}, timeout)
+}
+
+// detachedContext has the values of its parent but never has a deadline or is done
+type detachedContext struct {
+	parent context.Context
+}
+
+func (c detachedContext) Deadline() (time.Time, bool) {
+	return time.Time{}, false
+}
+
+func (c detachedContext) Done() <-chan struct{} {
+	return nil
+}
+
+func (c detachedContext) Err() error {
+	return nil
+}
+
+func (c detachedContext) Value(key any) any {
+	return c.parent.Value(key)
+}
@@ -0,0 +1,30 @@
+package operation
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type cleanupTestKey struct{}
+
+func TestCleanupContext(t *testing.T) {
+	parent, cancelParent := context.WithTimeout(context.WithValue(context.Background(), cleanupTestKey{}, "request-id"), time.Millisecond)
+	defer cancelParent()
+	<-parent.Done()
+
+	ctx, cancel := cleanupContext(parent, 0)
+	defer cancel()
+	require.NoError(t, ctx.Err())
+	require.Equal(t, "request-id", ctx.Value(cleanupTestKey{}))
+	deadline, ok := ctx.Deadline()
+	require.True(t, ok)
+	require.WithinDuration(t, time.Now().Add(cleanupTimeout), deadline, time.Second)
+
+	ctx, cancel = cleanupContext(parent, time.Hour)
+	defer cancel()
+	deadline, _ = ctx.Deadline()
+	require.WithinDuration(t, time.Now().Add(time.Hour), deadline, time.Second)
+}
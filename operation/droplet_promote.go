@@ -0,0 +1,279 @@
+package operation
+
+import (
+	"context"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/cloudfoundry/go-cfclient/v3/client"
+	"github.com/cloudfoundry/go-cfclient/v3/resource"
+)
+
+// The annotations recording where a promoted droplet came from, the CF API doesn't allow the buildpacks
+// and stack of an uploaded droplet to be set
+const (
+	promotedFromAnnotation = "promoted-from-droplet"
+	buildpacksAnnotation   = "buildpacks"
+	stackAnnotation        = "stack"
+)
+
+// AppRef identifies an application by the name of its org, space and app
+type AppRef struct {
+	Org   string
+	Space string
+	App   string
+}
+
+func (r AppRef) String() string {
+	return fmt.Sprintf("%s/%s/%s", r.Org, r.Space, r.App)
+}
+
+// DropletPromoteOptions configures how a droplet is promoted between foundations
+type DropletPromoteOptions struct {
+	Deploy   bool                    // roll the droplet out with a rolling deployment instead of only setting it as current
+	Rolling  *RollingOptions         // configures the deployment when Deploy is true
+	Download *client.DownloadOptions // configures the download from the source foundation
+	TempDir  string                  // directory the droplet is downloaded to, defaults to os.TempDir
+}
+
+// DropletPromoteOperation copies an app's current droplet from one foundation to an app on another, for
+// example from a staging to a production foundation, without staging the app again
+type DropletPromoteOperation struct {
+	source *client.Client
+	target *client.Client
+}
+
+// NewDropletPromoteOperation creates a new DropletPromoteOperation
+func NewDropletPromoteOperation(source, target *client.Client) *DropletPromoteOperation {
+	return &DropletPromoteOperation{
+		source: source,
+		target: target,
+	}
+}
+
+// Promote downloads the current droplet of the source app, verifying its checksum, and uploads it to a new
+// droplet on the target app with the same process types. Once the target foundation has processed the
+// upload its checksum is compared with the downloaded bits, so a corrupted copy is never used. The new
+// droplet is then set as the target app's current droplet, which takes effect the next time the app is
+// restarted, or when Deploy is set it's rolled out with a deployment.
+//
+// The source droplet's buildpacks and stack are recorded as annotations on the new droplet. If the promotion
+// fails before the new droplet is set as current or a deployment of it is created, it's deleted again
+func (o *DropletPromoteOperation) Promote(ctx context.Context, from, to AppRef, opts *DropletPromoteOptions) (*resource.Droplet, error) {
+	if opts == nil {
+		opts = &DropletPromoteOptions{}
+	}
+	sourceApp, err := findAppByRef(ctx, o.source, from)
+	if err != nil {
+		return nil, err
+	}
+	sourceDroplet, err := o.source.Droplets.GetCurrentForApp(ctx, sourceApp.GUID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the current droplet of app %s: %w", from, err)
+	}
+	if sourceDroplet.Lifecycle.Type == resource.LifecycleDocker.String() {
+		return nil, fmt.Errorf("app %s uses a docker image, only buildpack droplets can be promoted", from)
+	}
+	targetApp, err := findAppByRef(ctx, o.target, to)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.CreateTemp(opts.TempDir, "droplet-*.tgz")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create a file to download the droplet to: %w", err)
+	}
+	defer func() {
+		_ = f.Close()
+		_ = os.Remove(f.Name())
+	}()
+	err = o.source.Droplets.DownloadTo(ctx, sourceDroplet.GUID, f, opts.Download)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download droplet %s of app %s: %w", sourceDroplet.GUID, from, err)
+	}
+
+	droplet, err := o.uploadDroplet(ctx, targetApp, sourceDroplet, f)
+	if err != nil {
+		return nil, err
+	}
+	if err = o.setCurrent(ctx, targetApp, droplet, opts); err != nil {
+		return nil, err
+	}
+	return droplet, nil
+}
+
+// uploadDroplet creates a droplet on the target app and uploads the downloaded bits to it, deleting the
+// droplet again if the upload fails or doesn't match the source droplet's checksum
+func (o *DropletPromoteOperation) uploadDroplet(ctx context.Context, app *resource.App, source *resource.Droplet, f *os.File) (*resource.Droplet, error) {
+	r := resource.NewDropletCreate(app.GUID)
+	r.ProcessTypes = source.ProcessTypes
+	created, err := o.target.Droplets.Create(ctx, r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create droplet for app %s: %w", app.Name, err)
+	}
+
+	droplet, err := o.upload(ctx, created.GUID, source, f)
+	if err != nil {
+		return nil, o.deleteDroplet(ctx, created.GUID, err)
+	}
+	return droplet, nil
+}
+
+// deleteDroplet deletes a droplet that couldn't be promoted, even when ctx is done, and returns the cause
+// along with any failure to delete it
+func (o *DropletPromoteOperation) deleteDroplet(ctx context.Context, dropletGUID string, cause error) error {
+	ctx, cancel := cleanupContext(ctx, 0)
+	defer cancel()
+	if _, err := o.target.Droplets.Delete(ctx, dropletGUID); err != nil {
+		return fmt.Errorf("%w\nfailed to delete droplet %s: %s", cause, dropletGUID, err.Error())
+	}
+	return cause
+}
+
+func (o *DropletPromoteOperation) upload(ctx context.Context, dropletGUID string, source *resource.Droplet, f *os.File) (*resource.Droplet, error) {
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return nil, fmt.Errorf("failed to read the downloaded droplet: %w", err)
+	}
+	jobGUID, _, err := o.target.Droplets.Upload(ctx, dropletGUID, f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to upload droplet %s: %w", dropletGUID, err)
+	}
+	if err = o.target.Jobs.PollComplete(ctx, jobGUID, nil); err != nil {
+		return nil, fmt.Errorf("failed waiting for droplet %s to process: %w", dropletGUID, err)
+	}
+	droplet, err := o.target.Droplets.Get(ctx, dropletGUID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get droplet %s: %w", dropletGUID, err)
+	}
+	if droplet.State != resource.DropletState(resource.DropletStateStaged) {
+		return nil, fmt.Errorf("droplet %s is %s after uploading, expected %s", dropletGUID, droplet.State,
+			resource.DropletStateStaged)
+	}
+	// the target may use a different checksum type to the source, for example sha256 for a droplet staged
+	// by an older API with only a sha1 checksum, so checksum the downloaded bits the same way as the target
+	sum, err := fileChecksum(f, droplet.Checksum.Type)
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify droplet %s: %w", dropletGUID, err)
+	}
+	if !strings.EqualFold(droplet.Checksum.Value, sum) {
+		return nil, client.ChecksumMismatchError{
+			Type:     droplet.Checksum.Type,
+			Expected: sum,
+			Actual:   droplet.Checksum.Value,
+		}
+	}
+
+	update := &resource.DropletUpdate{}
+	update.Metadata.SetAnnotation("", promotedFromAnnotation, source.GUID)
+	update.Metadata.SetAnnotation("", stackAnnotation, source.Stack)
+	update.Metadata.SetAnnotation("", buildpacksAnnotation, formatBuildpacks(source.Buildpacks))
+	droplet, err = o.target.Droplets.Update(ctx, dropletGUID, update)
+	if err != nil {
+		return nil, fmt.Errorf("failed to annotate droplet %s: %w", dropletGUID, err)
+	}
+	return droplet, nil
+}
+
+// fileChecksum returns the hex encoded sha256 or sha1 checksum of the file's contents
+func fileChecksum(f *os.File, checksumType string) (string, error) {
+	var h hash.Hash
+	switch strings.ToLower(checksumType) {
+	case "sha256":
+		h = sha256.New()
+	case "sha1":
+		h = sha1.New()
+	default:
+		return "", fmt.Errorf("unsupported checksum type %q", checksumType)
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// setCurrent makes the droplet the app's current droplet, either directly or through a rolling deployment.
+// The droplet is deleted if it couldn't be set as current or deployed, but not once the deployment has started
+func (o *DropletPromoteOperation) setCurrent(ctx context.Context, app *resource.App, droplet *resource.Droplet, opts *DropletPromoteOptions) error {
+	if !opts.Deploy {
+		_, err := o.target.Droplets.SetCurrentAssociationForApp(ctx, app.GUID, droplet.GUID)
+		if err != nil {
+			return o.deleteDroplet(ctx, droplet.GUID,
+				fmt.Errorf("failed to set droplet %s as current for app %s: %w", droplet.GUID, app.Name, err))
+		}
+		return nil
+	}
+
+	r := resource.NewDeploymentCreate(app.GUID)
+	r.Droplet = &resource.Relationship{
+		GUID: droplet.GUID,
+	}
+	var maxInFlight int
+	var pollOptions *client.PollingOptions
+	if opts.Rolling != nil {
+		maxInFlight = opts.Rolling.MaxInFlight
+		pollOptions = opts.Rolling.PollingOptions
+	}
+	if maxInFlight > 0 {
+		r.Options = &resource.DeploymentOptions{
+			MaxInFlight: maxInFlight,
+		}
+	}
+	deployment, err := o.target.Deployments.Create(ctx, r)
+	if err != nil {
+		return o.deleteDroplet(ctx, droplet.GUID,
+			fmt.Errorf("failed to deploy droplet %s to app %s: %w", droplet.GUID, app.Name, err))
+	}
+	if pollOptions == nil {
+		var instances uint
+		processOpts := client.NewProcessOptions()
+		processOpts.Types.EqualTo("web")
+		if web, err := o.target.Processes.FirstForApp(ctx, app.GUID, processOpts); err == nil && web.Instances > 0 {
+			instances = uint(web.Instances)
+		}
+		pollOptions = deploymentPollingOptions(instances, maxInFlight)
+	}
+	if err = o.target.Deployments.PollFinalized(ctx, deployment.GUID, pollOptions); err != nil {
+		return fmt.Errorf("failed waiting for droplet %s to deploy to app %s: %w", droplet.GUID, app.Name, err)
+	}
+	return nil
+}
+
+// formatBuildpacks lists the buildpacks a droplet was staged with, like go_buildpack@1.10.6
+func formatBuildpacks(buildpacks []resource.DetectedBuildpack) string {
+	names := make([]string, len(buildpacks))
+	for i, bp := range buildpacks {
+		names[i] = bp.Name
+		if bp.Version != "" {
+			names[i] += "@" + bp.Version
+		}
+	}
+	return strings.Join(names, ",")
+}
+
+func findAppByRef(ctx context.Context, cf *client.Client, ref AppRef) (*resource.App, error) {
+	org, err := findOrgByName(ctx, cf, ref.Org)
+	if err != nil {
+		return nil, err
+	}
+	space, err := findSpaceByName(ctx, cf, org.GUID, ref.Space)
+	if err != nil {
+		return nil, err
+	}
+	opts := client.NewAppListOptions()
+	opts.Names.EqualTo(ref.App)
+	opts.SpaceGUIDs.EqualTo(space.GUID)
+	app, err := cf.Applications.Single(ctx, opts)
+	if err != nil {
+		return nil, fmt.Errorf("could not find app %s: %w", ref, err)
+	}
+	return app, nil
+}
@@ -0,0 +1,341 @@
+package operation
+
+import (
+	"context"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/cloudfoundry/go-cfclient/v3/client"
+	"github.com/cloudfoundry/go-cfclient/v3/config"
+	"github.com/cloudfoundry/go-cfclient/v3/testutil"
+
+	"github.com/stretchr/testify/require"
+)
+
+// templateDropletChecksum is the checksum of the droplet in the droplet template
+const templateDropletChecksum = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+type promoteFixture struct {
+	g             *testutil.ObjectJSONGenerator
+	serverURL     string
+	blobstoreURL  string
+	sourceApp     *testutil.JSONResource
+	targetApp     *testutil.JSONResource
+	sourceDroplet *testutil.JSONResource
+	targetDroplet *testutil.JSONResource
+	job           *testutil.JSONResource
+	bits          []byte
+	checksum      string
+	source        string // the source droplet JSON
+}
+
+func newPromoteFixture(t *testing.T, serverURL string) *promoteFixture {
+	bits := []byte("droplet tgz bits")
+	sum := sha256.Sum256(bits)
+	blobstore := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(bits)
+	}))
+	t.Cleanup(blobstore.Close)
+
+	g := testutil.NewObjectJSONGenerator(4412)
+	f := &promoteFixture{
+		g:             g,
+		serverURL:     serverURL,
+		blobstoreURL:  blobstore.URL,
+		sourceApp:     g.Application(),
+		targetApp:     g.Application(),
+		sourceDroplet: g.Droplet(),
+		targetDroplet: g.Droplet(),
+		job:           g.Job("COMPLETE"),
+		bits:          bits,
+		checksum:      hex.EncodeToString(sum[:]),
+	}
+	f.source = f.withChecksum(f.sourceDroplet.JSON)
+	return f
+}
+
+// withChecksum replaces the template's checksum of the droplet with the checksum of the blobstore bits
+func (f *promoteFixture) withChecksum(dropletJSON string) string {
+	return strings.Replace(dropletJSON, templateDropletChecksum, f.checksum, 1)
+}
+
+// routes are the routes needed to download the source droplet and upload it to the target app, where
+// uploaded is the target droplet once the upload has been processed
+func (f *promoteFixture) routes(uploaded string) []testutil.MockRoute {
+	g := f.g
+	org := g.SinglePaged(g.Organization().JSON)[0]
+	space := g.SinglePaged(g.Space().JSON)[0]
+	source := f.source
+	return []testutil.MockRoute{
+		{
+			Method:   http.MethodGet,
+			Endpoint: "/v3/organizations",
+			Output:   []string{org, org},
+			Status:   http.StatusOK,
+		},
+		{
+			Method:   http.MethodGet,
+			Endpoint: "/v3/spaces",
+			Output:   []string{space, space},
+			Status:   http.StatusOK,
+		},
+		{
+			Method:   http.MethodGet,
+			Endpoint: "/v3/apps",
+			Output:   []string{g.SinglePaged(f.sourceApp.JSON)[0], g.SinglePaged(f.targetApp.JSON)[0]},
+			Status:   http.StatusOK,
+		},
+		{
+			Method:   http.MethodGet,
+			Endpoint: fmt.Sprintf("/v3/apps/%s/droplets/current", f.sourceApp.GUID),
+			Output:   g.Single(source),
+			Status:   http.StatusOK,
+		},
+		{
+			Method:   http.MethodGet,
+			Endpoint: fmt.Sprintf("/v3/droplets/%s", f.sourceDroplet.GUID),
+			Output:   g.Single(source),
+			Status:   http.StatusOK,
+		},
+		{
+			Method:           http.MethodGet,
+			Endpoint:         fmt.Sprintf("/v3/droplets/%s/download", f.sourceDroplet.GUID),
+			Status:           http.StatusFound,
+			RedirectLocation: f.blobstoreURL,
+		},
+		{
+			Method:   http.MethodPost,
+			Endpoint: "/v3/droplets",
+			Output:   g.Single(strings.Replace(f.targetDroplet.JSON, "STAGED", "AWAITING_UPLOAD", 1)),
+			Status:   http.StatusCreated,
+			PostForm: fmt.Sprintf(`{"relationships":{"app":{"data":{"guid":"%s"}}},"process_types":{"rake":"bundle exec rake","web":"bundle exec rackup config.ru -p $PORT"}}`,
+				f.targetApp.GUID),
+		},
+		{
+			Method:           "POST-FILE",
+			Endpoint:         fmt.Sprintf("/v3/droplets/%s/upload", f.targetDroplet.GUID),
+			Output:           g.Single(f.targetDroplet.JSON),
+			Status:           http.StatusAccepted,
+			PostForm:         "droplet tgz bits",
+			RedirectLocation: fmt.Sprintf("%s/v3/jobs/%s", f.serverURL, f.job.GUID),
+		},
+		{
+			Method:   http.MethodGet,
+			Endpoint: fmt.Sprintf("/v3/jobs/%s", f.job.GUID),
+			Output:   g.Single(f.job.JSON),
+			Status:   http.StatusOK,
+		},
+		{
+			Method:   http.MethodGet,
+			Endpoint: fmt.Sprintf("/v3/droplets/%s", f.targetDroplet.GUID),
+			Output:   g.Single(uploaded),
+			Status:   http.StatusOK,
+		},
+	}
+}
+
+// annotateRoute records where the target droplet came from
+func (f *promoteFixture) annotateRoute() testutil.MockRoute {
+	return testutil.MockRoute{
+		Method:   http.MethodPatch,
+		Endpoint: fmt.Sprintf("/v3/droplets/%s", f.targetDroplet.GUID),
+		Output:   f.g.Single(f.withChecksum(f.targetDroplet.JSON)),
+		Status:   http.StatusOK,
+		PostForm: fmt.Sprintf(`{"metadata":{"labels":null,"annotations":{"buildpacks":"ruby_buildpack@1.1.1.","promoted-from-droplet":"%s","stack":"cflinuxfs3"}}}`,
+			f.sourceDroplet.GUID),
+	}
+}
+
+// deleteRoute deletes the target droplet after a failed promotion
+func (f *promoteFixture) deleteRoute() testutil.MockRoute {
+	return testutil.MockRoute{
+		Method:           http.MethodDelete,
+		Endpoint:         fmt.Sprintf("/v3/droplets/%s", f.targetDroplet.GUID),
+		Status:           http.StatusAccepted,
+		RedirectLocation: fmt.Sprintf("%s/v3/jobs/%s", f.serverURL, f.job.GUID),
+	}
+}
+
+func (f *promoteFixture) promoter(t *testing.T) *DropletPromoteOperation {
+	return f.promoterWithHTTPClient(t, nil)
+}
+
+func (f *promoteFixture) promoterWithHTTPClient(t *testing.T, httpClient *http.Client) *DropletPromoteOperation {
+	opts := []config.Option{config.Token("", "fake-refresh-token"), config.SkipTLSValidation()}
+	if httpClient != nil {
+		opts = append(opts, config.HttpClient(httpClient))
+	}
+	c, _ := config.New(f.serverURL, opts...)
+	cf, err := client.New(c)
+	require.NoError(t, err)
+	// both foundations are served by the same mock API
+	return NewDropletPromoteOperation(cf, cf)
+}
+
+var (
+	promoteFrom = AppRef{Org: "staging-org", Space: "staging", App: "my-app"}
+	promoteTo   = AppRef{Org: "prod-org", Space: "prod", App: "my-app"}
+)
+
+func TestDropletPromote(t *testing.T) {
+	serverURL := testutil.SetupFakeAPIServer()
+	defer testutil.Teardown()
+
+	f := newPromoteFixture(t, serverURL)
+	routes := append(f.routes(f.withChecksum(f.targetDroplet.JSON)),
+		f.annotateRoute(),
+		testutil.MockRoute{
+			Method:   http.MethodPatch,
+			Endpoint: fmt.Sprintf("/v3/apps/%s/relationships/current_droplet", f.targetApp.GUID),
+			Output:   f.g.Single(f.g.DropletAssociation().JSON),
+			Status:   http.StatusOK,
+			PostForm: fmt.Sprintf(`{"data":{"guid":"%s"}}`, f.targetDroplet.GUID),
+		},
+	)
+	testutil.SetupMultiple(routes, t)
+
+	droplet, err := f.promoter(t).Promote(context.Background(), promoteFrom, promoteTo, nil)
+	require.NoError(t, err)
+	require.Equal(t, f.targetDroplet.GUID, droplet.GUID)
+	require.Equal(t, f.checksum, droplet.Checksum.Value)
+}
+
+func TestDropletPromoteWithDeployment(t *testing.T) {
+	serverURL := testutil.SetupFakeAPIServer()
+	defer testutil.Teardown()
+
+	f := newPromoteFixture(t, serverURL)
+	deployment := f.g.DeploymentWithStatus("FINALIZED", "DEPLOYED")
+	routes := append(f.routes(f.withChecksum(f.targetDroplet.JSON)),
+		f.annotateRoute(),
+		testutil.MockRoute{
+			Method:   http.MethodPost,
+			Endpoint: "/v3/deployments",
+			Output:   f.g.Single(deployment.JSON),
+			Status:   http.StatusCreated,
+			PostForm: fmt.Sprintf(`{"droplet":{"guid":"%s"},"options":{"max_in_flight":2},"relationships":{"app":{"data":{"guid":"%s"}}}}`,
+				f.targetDroplet.GUID, f.targetApp.GUID),
+		},
+		testutil.MockRoute{
+			Method:   http.MethodGet,
+			Endpoint: fmt.Sprintf("/v3/deployments/%s", deployment.GUID),
+			Output:   f.g.Single(deployment.JSON),
+			Status:   http.StatusOK,
+		},
+	)
+	testutil.SetupMultiple(routes, t)
+
+	pollOptions := client.NewPollingOptions()
+	pollOptions.CheckInterval = time.Millisecond
+	_, err := f.promoter(t).Promote(context.Background(), promoteFrom, promoteTo, &DropletPromoteOptions{
+		Deploy: true,
+		Rolling: &RollingOptions{
+			MaxInFlight:    2,
+			PollingOptions: pollOptions,
+		},
+	})
+	require.NoError(t, err)
+}
+
+func TestDropletPromoteSHA1Source(t *testing.T) {
+	serverURL := testutil.SetupFakeAPIServer()
+	defer testutil.Teardown()
+
+	// the source droplet was staged by an older API with only a sha1 checksum, the target uses sha256
+	f := newPromoteFixture(t, serverURL)
+	sum := sha1.Sum(f.bits)
+	f.source = strings.Replace(strings.Replace(f.sourceDroplet.JSON, `"sha256"`, `"sha1"`, 1),
+		templateDropletChecksum, hex.EncodeToString(sum[:]), 1)
+	routes := append(f.routes(f.withChecksum(f.targetDroplet.JSON)),
+		f.annotateRoute(),
+		testutil.MockRoute{
+			Method:   http.MethodPatch,
+			Endpoint: fmt.Sprintf("/v3/apps/%s/relationships/current_droplet", f.targetApp.GUID),
+			Output:   f.g.Single(f.g.DropletAssociation().JSON),
+			Status:   http.StatusOK,
+			PostForm: fmt.Sprintf(`{"data":{"guid":"%s"}}`, f.targetDroplet.GUID),
+		},
+	)
+	testutil.SetupMultiple(routes, t)
+
+	droplet, err := f.promoter(t).Promote(context.Background(), promoteFrom, promoteTo, nil)
+	require.NoError(t, err)
+	require.Equal(t, f.checksum, droplet.Checksum.Value)
+}
+
+func TestDropletPromoteChecksumMismatch(t *testing.T) {
+	serverURL := testutil.SetupFakeAPIServer()
+	defer testutil.Teardown()
+
+	f := newPromoteFixture(t, serverURL)
+	// the target foundation received different bits, so its checksum is the template's
+	routes := append(f.routes(f.targetDroplet.JSON), f.deleteRoute())
+	testutil.SetupMultiple(routes, t)
+
+	_, err := f.promoter(t).Promote(context.Background(), promoteFrom, promoteTo, nil)
+	var mismatch client.ChecksumMismatchError
+	require.ErrorAs(t, err, &mismatch)
+	require.Equal(t, f.checksum, mismatch.Expected)
+	require.Equal(t, templateDropletChecksum, mismatch.Actual)
+}
+
+func TestDropletPromoteDeletesDropletWhenNotCurrent(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		deploy bool
+		route  func(f *promoteFixture) testutil.MockRoute
+		err    string
+	}{
+		{
+			name: "set current fails",
+			route: func(f *promoteFixture) testutil.MockRoute {
+				return testutil.MockRoute{
+					Method:   http.MethodPatch,
+					Endpoint: fmt.Sprintf("/v3/apps/%s/relationships/current_droplet", f.targetApp.GUID),
+					Status:   http.StatusUnprocessableEntity,
+				}
+			},
+			err: "failed to set droplet",
+		},
+		{
+			name:   "deployment fails",
+			deploy: true,
+			route: func(f *promoteFixture) testutil.MockRoute {
+				return testutil.MockRoute{
+					Method:   http.MethodPost,
+					Endpoint: "/v3/deployments",
+					Status:   http.StatusUnprocessableEntity,
+				}
+			},
+			err: "failed to deploy droplet",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			serverURL := testutil.SetupFakeAPIServer()
+			defer testutil.Teardown()
+
+			f := newPromoteFixture(t, serverURL)
+			routes := append(f.routes(f.withChecksum(f.targetDroplet.JSON)), f.annotateRoute(), tc.route(f), f.deleteRoute())
+			testutil.SetupMultiple(routes, t)
+
+			var deletes int32
+			promoter := f.promoterWithHTTPClient(t, &http.Client{Transport: requestHook(func(req *http.Request) {
+				if req.Method == http.MethodDelete {
+					atomic.AddInt32(&deletes, 1)
+				}
+			})})
+			_, err := promoter.Promote(context.Background(), promoteFrom, promoteTo, &DropletPromoteOptions{Deploy: tc.deploy})
+			require.ErrorContains(t, err, tc.err)
+			require.NotContains(t, err.Error(), "failed to delete droplet")
+			require.Equal(t, int32(1), deletes)
+		})
+	}
+}
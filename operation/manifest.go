@@ -30,6 +30,9 @@ const (
 type Manifest struct {
 	Version      string         `yaml:"version,omitempty"`
 	Applications []*AppManifest `yaml:"applications"`
+
+	// Extensions holds any top level fields not modeled above so they survive a round trip
+	Extensions map[string]any `yaml:",inline"`
 }
 
 type AppManifest struct {
@@ -48,6 +51,10 @@ type AppManifest struct {
 	Stack              string                `yaml:"stack,omitempty"`
 	Metadata           *resource.Metadata    `yaml:"metadata,omitempty"`
 	AppManifestProcess `yaml:",inline"`
+
+	// Extensions holds any application fields not modeled above, like lifecycle or features, so they
+	// survive a generate, modify and apply round trip
+	Extensions map[string]any `yaml:",inline"`
 }
 
 type AppManifestProcesses []AppManifestProcess
@@ -68,11 +75,17 @@ type AppManifestProcess struct {
 	ReadinessHealthCheckHttpEndpoint string             `yaml:"readiness-health-check-http-endpoint,omitempty"`
 	ReadinessHealthInvocationTimeout uint               `yaml:"readiness-health-invocation-timeout,omitempty"`
 	ReadinessHealthCheckInterval     uint               `yaml:"readiness-health-check-interval,omitempty"`
+
+	// Extensions holds any process fields not modeled above so they survive a round trip
+	Extensions map[string]any `yaml:",inline"`
 }
 
 type AppManifestDocker struct {
 	Image    string `yaml:"image,omitempty"`
 	Username string `yaml:"username,omitempty"`
+
+	// Extensions holds any docker fields not modeled above so they survive a round trip
+	Extensions map[string]any `yaml:",inline"`
 }
 
 type AppManifestServices []AppManifestService
@@ -131,6 +144,9 @@ type AppManifestRoutes []AppManifestRoute
 type AppManifestRoute struct {
 	Route    string           `yaml:"route"`
 	Protocol AppRouteProtocol `yaml:"protocol,omitempty"`
+
+	// Extensions holds any route fields not modeled above, like options, so they survive a round trip
+	Extensions map[string]any `yaml:",inline"`
 }
 
 type AppManifestSideCars []AppManifestSideCar
@@ -140,6 +156,9 @@ type AppManifestSideCar struct {
 	ProcessTypes []string `yaml:"process_types,omitempty"`
 	Command      string   `yaml:"command,omitempty"`
 	Memory       string   `yaml:"memory,omitempty"`
+
+	// Extensions holds any sidecar fields not modeled above so they survive a round trip
+	Extensions map[string]any `yaml:",inline"`
 }
 
 func NewManifest(applications ...*AppManifest) *Manifest {
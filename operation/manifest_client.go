@@ -0,0 +1,35 @@
+package operation
+
+import (
+	"context"
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/cloudfoundry/go-cfclient/v3/client"
+)
+
+// GenerateTyped generates the specified app manifest and parses it into a Manifest. Any fields the
+// Manifest types don't model are kept in their Extensions so the manifest can be modified and applied
+// again with ApplyManifestTyped without losing data
+func GenerateTyped(ctx context.Context, cf *client.Client, appGUID string) (*Manifest, error) {
+	manifestYAML, err := cf.Manifests.Generate(ctx, appGUID)
+	if err != nil {
+		return nil, err
+	}
+	var m Manifest
+	if err = yaml.Unmarshal([]byte(manifestYAML), &m); err != nil {
+		return nil, fmt.Errorf("error parsing manifest for app %s: %w", appGUID, err)
+	}
+	return &m, nil
+}
+
+// ApplyManifestTyped marshals the manifest, including any Extensions, and applies it to the space
+// asynchronously returning the jobGUID. See ManifestClient.ApplyManifest
+func ApplyManifestTyped(ctx context.Context, cf *client.Client, spaceGUID string, manifest *Manifest) (string, error) {
+	manifestBytes, err := yaml.Marshal(manifest)
+	if err != nil {
+		return "", fmt.Errorf("error marshalling manifest: %w", err)
+	}
+	return cf.Manifests.ApplyManifest(ctx, spaceGUID, string(manifestBytes))
+}
@@ -0,0 +1,103 @@
+package operation
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/cloudfoundry/go-cfclient/v3/client"
+	"github.com/cloudfoundry/go-cfclient/v3/config"
+	"github.com/cloudfoundry/go-cfclient/v3/testutil"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+const generatedManifestYaml = `applications:
+- name: api
+  lifecycle: cnb
+  features:
+    ssh: true
+  memory: 256M
+  processes:
+  - type: web
+    instances: 2
+    user: vcap
+  routes:
+  - route: api.example.org
+    options:
+      loadbalancing: least-connection
+  docker:
+    image: nginx
+  sidecars:
+  - name: proxy
+    command: ./proxy
+    startup-timeout: 30
+version: 1
+extra: true
+`
+
+func TestManifestExtensionsRoundTrip(t *testing.T) {
+	var m Manifest
+	require.NoError(t, yaml.Unmarshal([]byte(generatedManifestYaml), &m))
+
+	app := m.Applications[0]
+	require.Equal(t, "api", app.Name)
+	require.Equal(t, "256M", app.Memory)
+	require.Equal(t, "cnb", app.Extensions["lifecycle"])
+	require.Equal(t, map[string]any{"ssh": true}, app.Extensions["features"])
+	require.Nil(t, app.AppManifestProcess.Extensions)
+	require.Equal(t, "vcap", (*app.Processes)[0].Extensions["user"])
+	require.Equal(t, map[string]any{"loadbalancing": "least-connection"}, (*app.Routes)[0].Extensions["options"])
+	require.Equal(t, 30, (*app.Sidecars)[0].Extensions["startup-timeout"])
+	require.Equal(t, true, m.Extensions["extra"])
+
+	app.Memory = "512M"
+	b, err := yaml.Marshal(&m)
+	require.NoError(t, err)
+
+	var expected, actual map[string]any
+	require.NoError(t, yaml.Unmarshal([]byte(generatedManifestYaml), &expected))
+	require.NoError(t, yaml.Unmarshal(b, &actual))
+	expected["applications"].([]any)[0].(map[string]any)["memory"] = "512M"
+	expected["version"] = "1"
+	require.Equal(t, expected, actual)
+}
+
+func TestGenerateAndApplyManifestTyped(t *testing.T) {
+	serverURL := testutil.SetupFakeAPIServer()
+	defer testutil.Teardown()
+
+	g := testutil.NewObjectJSONGenerator(1)
+	app := g.Application()
+	space := g.Space()
+	testutil.SetupMultiple([]testutil.MockRoute{
+		{
+			Method:   http.MethodGet,
+			Endpoint: fmt.Sprintf("/v3/apps/%s/manifest", app.GUID),
+			Output:   []string{generatedManifestYaml},
+			Status:   http.StatusOK,
+		},
+		{
+			Method:           "POST-FILE",
+			Endpoint:         fmt.Sprintf("/v3/spaces/%s/actions/apply_manifest", space.GUID),
+			Status:           http.StatusAccepted,
+			RedirectLocation: fmt.Sprintf("%s/v3/jobs/%s", serverURL, "b7b9ab0a-1a3d-4e2b-bd6c-6b4b5d1b9b1f"),
+			PostForm:         "lifecycle: cnb",
+		},
+	}, t)
+
+	c, _ := config.New(serverURL, config.Token("", "fake-refresh-token"), config.SkipTLSValidation())
+	cf, err := client.New(c)
+	require.NoError(t, err)
+
+	m, err := GenerateTyped(context.Background(), cf, app.GUID)
+	require.NoError(t, err)
+	require.Equal(t, "cnb", m.Applications[0].Extensions["lifecycle"])
+
+	m.Applications[0].Memory = "512M"
+	jobGUID, err := ApplyManifestTyped(context.Background(), cf, space.GUID, m)
+	require.NoError(t, err)
+	require.Equal(t, "b7b9ab0a-1a3d-4e2b-bd6c-6b4b5d1b9b1f", jobGUID)
+}
@@ -0,0 +1,186 @@
+package operation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/cloudfoundry/go-cfclient/v3/client"
+	"github.com/cloudfoundry/go-cfclient/v3/resource"
+)
+
+const (
+	colorReset  = "\033[0m"
+	colorRed    = "\033[31m"
+	colorGreen  = "\033[32m"
+	colorYellow = "\033[33m"
+)
+
+// ManifestPlan is a preview of the changes applying a manifest would make to a space
+type ManifestPlan struct {
+	SpaceGUID string
+	Manifest  *Manifest
+	Diff      *resource.ManifestDiff
+}
+
+// HasChanges returns true if applying the manifest would change the space
+func (p *ManifestPlan) HasChanges() bool {
+	return p.Diff != nil && len(p.Diff.Diff) > 0
+}
+
+// String renders the plan without color
+func (p *ManifestPlan) String() string {
+	var sb strings.Builder
+	_ = p.Render(&sb, false)
+	return sb.String()
+}
+
+// Render writes a human-readable plan grouping the changes by application, similar to terraform plan.
+// Additions are prefixed with +, removals with - and replacements with ~, and colored green, red and
+// yellow respectively when color is true
+func (p *ManifestPlan) Render(w io.Writer, color bool) error {
+	if !p.HasChanges() {
+		_, err := fmt.Fprintln(w, "No changes. The space already matches the manifest.")
+		return err
+	}
+
+	var adds, changes, removes int
+	var lastApp string
+	for _, d := range p.Diff.Diff {
+		app, field := p.splitPath(d.Path)
+		if app != lastApp {
+			if _, err := fmt.Fprintf(w, "%s\n", app); err != nil {
+				return err
+			}
+			lastApp = app
+		}
+
+		var line, c string
+		switch d.Op {
+		case "add":
+			adds++
+			c, line = colorGreen, fmt.Sprintf("  + %s: %s", field, formatDiffValue(d.Value))
+		case "remove":
+			removes++
+			c, line = colorRed, fmt.Sprintf("  - %s: %s", field, formatDiffValue(d.Was))
+		default:
+			changes++
+			c, line = colorYellow, fmt.Sprintf("  ~ %s: %s => %s", field, formatDiffValue(d.Was), formatDiffValue(d.Value))
+		}
+		if color {
+			line = c + line + colorReset
+		}
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintf(w, "\nPlan: %d to add, %d to change, %d to remove.\n", adds, changes, removes)
+	return err
+}
+
+// splitPath splits a diff JSON pointer like /applications/0/processes/1/memory into the application's
+// name and the field path processes[1].memory
+func (p *ManifestPlan) splitPath(pointer string) (string, string) {
+	parts := strings.Split(strings.TrimPrefix(pointer, "/"), "/")
+	app := "space"
+	if len(parts) >= 2 && parts[0] == "applications" {
+		app = "application " + parts[1]
+		if i, err := strconv.Atoi(parts[1]); err == nil && p.Manifest != nil && i < len(p.Manifest.Applications) {
+			app = "application " + p.Manifest.Applications[i].Name
+		}
+		parts = parts[2:]
+	}
+
+	var field strings.Builder
+	for _, part := range parts {
+		part = strings.NewReplacer("~1", "/", "~0", "~").Replace(part)
+		if _, err := strconv.Atoi(part); err == nil {
+			field.WriteString("[" + part + "]")
+			continue
+		}
+		if field.Len() > 0 {
+			field.WriteString(".")
+		}
+		field.WriteString(part)
+	}
+	return app, field.String()
+}
+
+func formatDiffValue(v any) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(b)
+}
+
+// ManifestApplyOperation validates a manifest, previews the changes it would make to a space and then
+// applies it once the plan is confirmed
+type ManifestApplyOperation struct {
+	orgName   string
+	spaceName string
+	client    *client.Client
+}
+
+// NewManifestApplyOperation creates a new ManifestApplyOperation
+func NewManifestApplyOperation(client *client.Client, orgName, spaceName string) *ManifestApplyOperation {
+	return &ManifestApplyOperation{
+		orgName:   orgName,
+		spaceName: spaceName,
+		client:    client,
+	}
+}
+
+// Plan validates the manifest locally and then asks the API for the changes applying it would make
+// to the space. Nothing is changed until the plan is applied
+func (o *ManifestApplyOperation) Plan(ctx context.Context, manifest *Manifest) (*ManifestPlan, error) {
+	if err := manifest.Validate(); err != nil {
+		return nil, err
+	}
+	manifestBytes, err := yaml.Marshal(manifest)
+	if err != nil {
+		return nil, fmt.Errorf("error marshalling manifest: %w", err)
+	}
+
+	org, err := findOrgByName(ctx, o.client, o.orgName)
+	if err != nil {
+		return nil, err
+	}
+	space, err := findSpaceByName(ctx, o.client, org.GUID, o.spaceName)
+	if err != nil {
+		return nil, err
+	}
+	diff, err := o.client.Manifests.ManifestDiff(ctx, space.GUID, string(manifestBytes))
+	if err != nil {
+		return nil, fmt.Errorf("error diffing manifest with space %s: %w", space.Name, err)
+	}
+	return &ManifestPlan{
+		SpaceGUID: space.GUID,
+		Manifest:  manifest,
+		Diff:      diff,
+	}, nil
+}
+
+// Apply applies the planned manifest to the space and waits for it to finish
+func (o *ManifestApplyOperation) Apply(ctx context.Context, plan *ManifestPlan) error {
+	manifestBytes, err := yaml.Marshal(plan.Manifest)
+	if err != nil {
+		return fmt.Errorf("error marshalling manifest: %w", err)
+	}
+	jobGUID, err := o.client.Manifests.ApplyManifest(ctx, plan.SpaceGUID, string(manifestBytes))
+	if err != nil {
+		return fmt.Errorf("error applying manifest to space: %w", err)
+	}
+	if err = o.client.Jobs.PollComplete(ctx, jobGUID, nil); err != nil {
+		return fmt.Errorf("error waiting for manifest to finish applying: %w", err)
+	}
+	return nil
+}
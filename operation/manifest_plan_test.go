@@ -0,0 +1,109 @@
+package operation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/cloudfoundry/go-cfclient/v3/client"
+	"github.com/cloudfoundry/go-cfclient/v3/config"
+	"github.com/cloudfoundry/go-cfclient/v3/resource"
+	"github.com/cloudfoundry/go-cfclient/v3/testutil"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestManifestPlanRender(t *testing.T) {
+	g := testutil.NewObjectJSONGenerator(1)
+	var diff resource.ManifestDiff
+	require.NoError(t, json.Unmarshal([]byte(g.ManifestDiff().JSON), &diff))
+
+	plan := &ManifestPlan{
+		Manifest: NewManifest(NewAppManifest("api"), NewAppManifest("web"), NewAppManifest("worker")),
+		Diff:     &diff,
+	}
+	require.True(t, plan.HasChanges())
+	require.Equal(t, `application api
+  - routes[1]: {"route":"route.example.com"}
+application web
+  + buildpacks[2]: java_buildpack
+application worker
+  ~ processes[1].memory: 256M => 512M
+
+Plan: 1 to add, 1 to change, 1 to remove.
+`, plan.String())
+
+	var sb strings.Builder
+	require.NoError(t, plan.Render(&sb, true))
+	require.Contains(t, sb.String(), colorGreen+"  + buildpacks[2]: java_buildpack"+colorReset)
+
+	plan.Diff = &resource.ManifestDiff{}
+	require.False(t, plan.HasChanges())
+	require.Equal(t, "No changes. The space already matches the manifest.\n", plan.String())
+}
+
+func TestManifestApplyOperation(t *testing.T) {
+	serverURL := testutil.SetupFakeAPIServer()
+	defer testutil.Teardown()
+
+	g := testutil.NewObjectJSONGenerator(1)
+	org := g.Organization()
+	space := g.Space()
+	job := g.Job("COMPLETE")
+	testutil.SetupMultiple([]testutil.MockRoute{
+		{
+			Method:   http.MethodGet,
+			Endpoint: "/v3/organizations",
+			Output:   g.SinglePaged(org.JSON),
+			Status:   http.StatusOK,
+		},
+		{
+			Method:   http.MethodGet,
+			Endpoint: "/v3/spaces",
+			Output:   g.SinglePaged(space.JSON),
+			Status:   http.StatusOK,
+		},
+		{
+			Method:   "POST-FILE",
+			Endpoint: fmt.Sprintf("/v3/spaces/%s/manifest_diff", space.GUID),
+			Output:   g.Single(g.ManifestDiff().JSON),
+			Status:   http.StatusCreated,
+			PostForm: "name: api",
+		},
+		{
+			Method:           "POST-FILE",
+			Endpoint:         fmt.Sprintf("/v3/spaces/%s/actions/apply_manifest", space.GUID),
+			Status:           http.StatusAccepted,
+			RedirectLocation: fmt.Sprintf("%s/v3/jobs/%s", serverURL, job.GUID),
+			PostForm:         "name: api",
+		},
+		{
+			Method:   http.MethodGet,
+			Endpoint: fmt.Sprintf("/v3/jobs/%s", job.GUID),
+			Output:   g.Single(job.JSON),
+			Status:   http.StatusOK,
+		},
+	}, t)
+
+	c, _ := config.New(serverURL, config.Token("", "fake-refresh-token"), config.SkipTLSValidation())
+	cf, err := client.New(c)
+	require.NoError(t, err)
+
+	op := NewManifestApplyOperation(cf, org.Name, space.Name)
+
+	// invalid manifests never reach the API
+	invalid := NewManifest(NewAppManifest("api"))
+	invalid.Applications[0].Memory = "lots"
+	_, err = op.Plan(context.Background(), invalid)
+	var validationErr *ManifestValidationError
+	require.ErrorAs(t, err, &validationErr)
+
+	plan, err := op.Plan(context.Background(), NewManifest(NewAppManifest("api")))
+	require.NoError(t, err)
+	require.Equal(t, space.GUID, plan.SpaceGUID)
+	require.Len(t, plan.Diff.Diff, 3)
+	require.NoError(t, op.Apply(context.Background(), plan))
+}
@@ -1,6 +1,8 @@
 package operation
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -140,3 +142,120 @@ func TestManifestUnMarshalling(t *testing.T) {
 	require.Equal(t, 1, len(m.Applications))
 	require.Equal(t, 2, len(*m.Applications[0].Services))
 }
+
+func TestInterpolateManifest(t *testing.T) {
+	const manifestYaml = `applications:
+- name: ((app))-api
+  instances: ((instances))
+  env:
+    GREETING: hello ((app)) from ((region))
+  routes:
+  - route: ((app)).((domain))
+`
+	m, err := InterpolateManifest([]byte(manifestYaml), map[string]any{
+		"app":       "orders",
+		"instances": 3,
+		"region":    "us-east",
+		"domain":    "apps.example.org",
+	})
+	require.NoError(t, err)
+	require.Len(t, m.Applications, 1)
+	app := m.Applications[0]
+	require.Equal(t, "orders-api", app.Name)
+	require.Equal(t, uint(3), *app.Instances)
+	require.Equal(t, "hello orders from us-east", app.Env["GREETING"])
+	require.Equal(t, "orders.apps.example.org", (*app.Routes)[0].Route)
+
+	_, err = InterpolateManifest([]byte(manifestYaml), map[string]any{"app": "orders"})
+	require.EqualError(t, err, "expected to find variables: domain, instances, region")
+}
+
+func TestLoadManifest(t *testing.T) {
+	dir := t.TempDir()
+	manifestPath := filepath.Join(dir, "manifest.yml")
+	require.NoError(t, os.WriteFile(manifestPath, []byte(`applications:
+- name: web
+  path: ((web_path))
+  memory: ((memory))
+- name: worker
+  memory: ((memory))
+- name: image
+  docker:
+    image: ((image))
+`), 0644))
+	varsPath := filepath.Join(dir, "vars.yml")
+	require.NoError(t, os.WriteFile(varsPath, []byte("web_path: web\nmemory: 512M\nimage: nginx:1\n"), 0644))
+	overridesPath := filepath.Join(dir, "overrides.yml")
+	require.NoError(t, os.WriteFile(overridesPath, []byte("memory: 1G\n"), 0644))
+
+	m, err := LoadManifest(manifestPath, []string{varsPath, overridesPath}, map[string]any{"image": "nginx:2"})
+	require.NoError(t, err)
+	require.Len(t, m.Applications, 3)
+	require.Equal(t, filepath.Join(dir, "web"), m.Applications[0].Path)
+	require.Equal(t, "1G", m.Applications[0].Memory)
+	require.Equal(t, dir, m.Applications[1].Path)
+	require.Equal(t, "", m.Applications[2].Path)
+	require.Equal(t, "nginx:2", m.Applications[2].Docker.Image)
+
+	_, err = LoadManifest(manifestPath, []string{filepath.Join(dir, "missing.yml")}, nil)
+	require.ErrorContains(t, err, "error reading vars file")
+}
+
+func TestManifestValidate(t *testing.T) {
+	var m *Manifest
+	require.NoError(t, yamlv3.Unmarshal([]byte(fullSpringMusicYamlV2), &m))
+	require.NoError(t, m.Validate())
+
+	const invalidYaml = `applications:
+- name: web
+  memory: 1GiB
+  disk_quota: 0G
+  log-rate-limit-per-second: fast
+  health-check-type: tcp
+  health-check-http-endpoint: health
+  buildpacks:
+  - java_buildpack
+  docker:
+    image: nginx
+  no-route: true
+  routes:
+  - route: https://web.apps.example.org/path
+  - route: not a route
+  - route: tcp.example.org:99999
+    protocol: udp
+  processes:
+  - type: web
+    memory: 512M
+  - type: web
+    memory: 512
+- name: web
+`
+	require.NoError(t, yamlv3.Unmarshal([]byte(invalidYaml), &m))
+	err := m.Validate()
+	var validationErr *ManifestValidationError
+	require.ErrorAs(t, err, &validationErr)
+
+	var fields []string
+	for _, fe := range validationErr.Errors {
+		fields = append(fields, fe.Field)
+	}
+	require.Equal(t, []string{
+		"applications[0].buildpacks",
+		"applications[0].memory",
+		"applications[0].disk_quota",
+		"applications[0].log-rate-limit-per-second",
+		"applications[0].health-check-type",
+		"applications[0].health-check-http-endpoint",
+		"applications[0].processes[1].type",
+		"applications[0].processes[1].memory",
+		"applications[0].no-route",
+		"applications[0].routes[1].route",
+		"applications[0].routes[2].protocol",
+		"applications[0].routes[2].route",
+		"applications[1].name",
+	}, fields)
+	require.Equal(t, "web", validationErr.Errors[0].App)
+	require.Contains(t, err.Error(), `applications[0].memory: "1GiB" must be an integer followed by a unit of M, MB, G, GB, T or TB`)
+
+	require.EqualError(t, (&Manifest{}).Validate(), "invalid manifest: applications: must contain at least one application")
+}
@@ -0,0 +1,208 @@
+package operation
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	// byteQuantityRegex matches memory and disk quotas like 256M, 1G or 1024MB
+	byteQuantityRegex = regexp.MustCompile(`^(?i)[1-9]\d*(M|MB|G|GB|T|TB)$`)
+
+	// logRateLimitRegex matches log rate limits like 100MB, 1K or 512B, -1 is unlimited
+	logRateLimitRegex = regexp.MustCompile(`^(?i)(-1|\d+(B|K|KB|M|MB|G|GB|T|TB))$`)
+
+	// routeHostRegex matches a route's domain name
+	routeHostRegex = regexp.MustCompile(`^(\*\.)?([a-zA-Z0-9]([-a-zA-Z0-9]*[a-zA-Z0-9])?\.)+[a-zA-Z0-9]([-a-zA-Z0-9]*[a-zA-Z0-9])?$`)
+)
+
+// ManifestFieldError is a single invalid field found in a manifest
+type ManifestFieldError struct {
+	App     string // the name of the application with the invalid field
+	Field   string // the path to the invalid field, for example applications[0].processes[1].memory
+	Value   string // the invalid value, if any
+	Message string // why the value is invalid
+}
+
+func (e ManifestFieldError) Error() string {
+	if e.Value == "" {
+		return fmt.Sprintf("%s: %s", e.Field, e.Message)
+	}
+	return fmt.Sprintf("%s: %q %s", e.Field, e.Value, e.Message)
+}
+
+// ManifestValidationError contains every invalid field found in a manifest
+type ManifestValidationError struct {
+	Errors []ManifestFieldError
+}
+
+func (e *ManifestValidationError) Error() string {
+	msgs := make([]string, len(e.Errors))
+	for i, fe := range e.Errors {
+		msgs[i] = fe.Error()
+	}
+	return fmt.Sprintf("invalid manifest: %s", strings.Join(msgs, "; "))
+}
+
+// Validate checks the manifest locally for invalid fields that the API would reject. A
+// *ManifestValidationError listing every invalid field is returned if the manifest isn't valid
+func (m *Manifest) Validate() error {
+	v := &manifestValidator{}
+	if len(m.Applications) == 0 {
+		v.add("", "applications", "", "must contain at least one application")
+	}
+	names := map[string]bool{}
+	for i, app := range m.Applications {
+		field := fmt.Sprintf("applications[%d]", i)
+		if app == nil {
+			v.add("", field, "", "must not be empty")
+			continue
+		}
+		if names[app.Name] {
+			v.add(app.Name, field+".name", app.Name, "is a duplicate application name")
+		}
+		names[app.Name] = true
+		v.validateApp(field, app)
+	}
+	if len(v.errors) > 0 {
+		return &ManifestValidationError{Errors: v.errors}
+	}
+	return nil
+}
+
+type manifestValidator struct {
+	errors []ManifestFieldError
+}
+
+func (v *manifestValidator) add(app, field, value, message string) {
+	v.errors = append(v.errors, ManifestFieldError{
+		App:     app,
+		Field:   field,
+		Value:   value,
+		Message: message,
+	})
+}
+
+func (v *manifestValidator) validateApp(field string, app *AppManifest) {
+	if app.Name == "" {
+		v.add(app.Name, field+".name", "", "is required")
+	}
+
+	if app.Docker != nil {
+		if app.Docker.Image == "" {
+			v.add(app.Name, field+".docker.image", "", "is required when docker is specified")
+		}
+		if len(app.Buildpacks) > 0 {
+			v.add(app.Name, field+".buildpacks", "", "cannot be used with docker")
+		}
+		if app.Path != "" {
+			v.add(app.Name, field+".path", app.Path, "cannot be used with docker")
+		}
+	}
+
+	v.validateProcess(app.Name, field, app.AppManifestProcess)
+	if app.Processes != nil {
+		types := map[AppProcessType]bool{}
+		for i, p := range *app.Processes {
+			processField := fmt.Sprintf("%s.processes[%d]", field, i)
+			if p.Type == "" {
+				v.add(app.Name, processField+".type", "", "is required")
+			} else if types[p.Type] {
+				v.add(app.Name, processField+".type", string(p.Type), "is a duplicate process type")
+			}
+			types[p.Type] = true
+			v.validateProcess(app.Name, processField, p)
+		}
+	}
+
+	if app.Sidecars != nil {
+		for i, sc := range *app.Sidecars {
+			sidecarField := fmt.Sprintf("%s.sidecars[%d]", field, i)
+			if sc.Name == "" {
+				v.add(app.Name, sidecarField+".name", "", "is required")
+			}
+			if sc.Command == "" {
+				v.add(app.Name, sidecarField+".command", "", "is required")
+			}
+			v.validateByteQuantity(app.Name, sidecarField+".memory", sc.Memory)
+		}
+	}
+
+	if app.Routes != nil && len(*app.Routes) > 0 {
+		if app.NoRoute {
+			v.add(app.Name, field+".no-route", "", "cannot be used with routes")
+		}
+		if app.RandomRoute {
+			v.add(app.Name, field+".random-route", "", "cannot be used with routes")
+		}
+		for i, r := range *app.Routes {
+			v.validateRoute(app.Name, fmt.Sprintf("%s.routes[%d]", field, i), r)
+		}
+	}
+	if app.NoRoute && app.RandomRoute {
+		v.add(app.Name, field+".no-route", "", "cannot be used with random-route")
+	}
+}
+
+func (v *manifestValidator) validateProcess(app, field string, p AppManifestProcess) {
+	v.validateByteQuantity(app, field+".memory", p.Memory)
+	v.validateByteQuantity(app, field+".disk_quota", p.DiskQuota)
+	if p.LogRateLimitPerSecond != "" && !logRateLimitRegex.MatchString(p.LogRateLimitPerSecond) {
+		v.add(app, field+".log-rate-limit-per-second", p.LogRateLimitPerSecond,
+			"must be -1 or an integer followed by a unit of B, K, KB, M, MB, G, GB, T or TB")
+	}
+
+	switch p.HealthCheckType {
+	case "", Http, Port, Process:
+	default:
+		v.add(app, field+".health-check-type", string(p.HealthCheckType), "must be one of http, port or process")
+	}
+	if p.HealthCheckHTTPEndpoint != "" && !strings.HasPrefix(p.HealthCheckHTTPEndpoint, "/") {
+		v.add(app, field+".health-check-http-endpoint", p.HealthCheckHTTPEndpoint, "must be a path starting with /")
+	}
+
+	switch AppHealthCheckType(p.ReadinessHealthCheckType) {
+	case "", Http, Port, Process:
+	default:
+		v.add(app, field+".readiness-health-check-type", p.ReadinessHealthCheckType, "must be one of http, port or process")
+	}
+	if p.ReadinessHealthCheckHttpEndpoint != "" && !strings.HasPrefix(p.ReadinessHealthCheckHttpEndpoint, "/") {
+		v.add(app, field+".readiness-health-check-http-endpoint", p.ReadinessHealthCheckHttpEndpoint, "must be a path starting with /")
+	}
+}
+
+func (v *manifestValidator) validateByteQuantity(app, field, value string) {
+	if value != "" && !byteQuantityRegex.MatchString(value) {
+		v.add(app, field, value, "must be an integer followed by a unit of M, MB, G, GB, T or TB")
+	}
+}
+
+func (v *manifestValidator) validateRoute(app, field string, r AppManifestRoute) {
+	switch r.Protocol {
+	case "", HTTP1, HTTP2, TCP:
+	default:
+		v.add(app, field+".protocol", string(r.Protocol), "must be one of http1, http2 or tcp")
+	}
+
+	route := r.Route
+	if route == "" {
+		v.add(app, field+".route", "", "is required")
+		return
+	}
+	if !strings.Contains(route, "://") {
+		route = "unused://" + route
+	}
+	u, err := url.Parse(route)
+	if err != nil || u.User != nil || u.RawQuery != "" || u.Fragment != "" || !routeHostRegex.MatchString(u.Hostname()) {
+		v.add(app, field+".route", r.Route, "is not a valid route, expected host.domain[:port][/path]")
+		return
+	}
+	if port := u.Port(); port != "" {
+		if n, err := strconv.Atoi(port); err != nil || n < 1 || n > 65535 {
+			v.add(app, field+".route", r.Route, "has an invalid port")
+		}
+	}
+}
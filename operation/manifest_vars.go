@@ -0,0 +1,157 @@
+package operation
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// manifestVarRegex matches ((var)) placeholders in manifest values
+var manifestVarRegex = regexp.MustCompile(`\(\(([-/.\w!\[\]]+)\)\)`)
+
+// LoadVarsFile reads a YAML file of manifest variables
+func LoadVarsFile(path string) (map[string]any, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading vars file %s: %w", path, err)
+	}
+	vars := map[string]any{}
+	if err = yaml.Unmarshal(b, &vars); err != nil {
+		return nil, fmt.Errorf("error parsing vars file %s: %w", path, err)
+	}
+	return vars, nil
+}
+
+// LoadManifest reads the manifest file replacing any ((var)) placeholders with the variables from the
+// vars files and the vars map, where later vars files take precedence and the vars map takes precedence
+// over all the vars files.
+//
+// Any relative application paths are resolved against the manifest's directory and applications without
+// a path default to the manifest's directory, the same as cf push
+func LoadManifest(path string, varsFiles []string, vars map[string]any) (*Manifest, error) {
+	allVars := map[string]any{}
+	for _, f := range varsFiles {
+		fileVars, err := LoadVarsFile(f)
+		if err != nil {
+			return nil, err
+		}
+		for k, v := range fileVars {
+			allVars[k] = v
+		}
+	}
+	for k, v := range vars {
+		allVars[k] = v
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading manifest %s: %w", path, err)
+	}
+	m, err := InterpolateManifest(b, allVars)
+	if err != nil {
+		return nil, fmt.Errorf("error interpolating manifest %s: %w", path, err)
+	}
+
+	dir := filepath.Dir(path)
+	for _, app := range m.Applications {
+		if app.Docker != nil {
+			continue
+		}
+		if app.Path == "" {
+			app.Path = dir
+		} else if !filepath.IsAbs(app.Path) {
+			app.Path = filepath.Join(dir, app.Path)
+		}
+	}
+	return m, nil
+}
+
+// InterpolateManifest parses the manifest YAML replacing any ((var)) placeholders with the variables.
+// A placeholder that's an entire value is replaced by the variable keeping its type, otherwise the
+// variable is substituted into the string value. An error listing every missing variable is returned
+// if any placeholders don't have a variable
+func InterpolateManifest(manifestYAML []byte, vars map[string]any) (*Manifest, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(manifestYAML, &doc); err != nil {
+		return nil, fmt.Errorf("error parsing manifest: %w", err)
+	}
+
+	missing := map[string]bool{}
+	if err := interpolateNode(&doc, vars, missing); err != nil {
+		return nil, err
+	}
+	if len(missing) > 0 {
+		names := make([]string, 0, len(missing))
+		for name := range missing {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return nil, fmt.Errorf("expected to find variables: %s", strings.Join(names, ", "))
+	}
+
+	var m Manifest
+	if err := doc.Decode(&m); err != nil {
+		return nil, fmt.Errorf("error parsing manifest: %w", err)
+	}
+	return &m, nil
+}
+
+func interpolateNode(node *yaml.Node, vars map[string]any, missing map[string]bool) error {
+	switch node.Kind {
+	case yaml.DocumentNode, yaml.SequenceNode:
+		for _, n := range node.Content {
+			if err := interpolateNode(n, vars, missing); err != nil {
+				return err
+			}
+		}
+	case yaml.MappingNode:
+		// only interpolate the values, not the keys
+		for i := 1; i < len(node.Content); i += 2 {
+			if err := interpolateNode(node.Content[i], vars, missing); err != nil {
+				return err
+			}
+		}
+	case yaml.ScalarNode:
+		return interpolateScalar(node, vars, missing)
+	}
+	return nil
+}
+
+func interpolateScalar(node *yaml.Node, vars map[string]any, missing map[string]bool) error {
+	matches := manifestVarRegex.FindAllStringSubmatchIndex(node.Value, -1)
+	if len(matches) == 0 {
+		return nil
+	}
+
+	// the whole value is a single placeholder so replace the node keeping the variable's type
+	if len(matches) == 1 && matches[0][0] == 0 && matches[0][1] == len(node.Value) {
+		name := node.Value[matches[0][2]:matches[0][3]]
+		v, ok := vars[name]
+		if !ok {
+			missing[name] = true
+			return nil
+		}
+		var replacement yaml.Node
+		if err := replacement.Encode(v); err != nil {
+			return fmt.Errorf("error interpolating variable %s: %w", name, err)
+		}
+		*node = replacement
+		return nil
+	}
+
+	node.Value = manifestVarRegex.ReplaceAllStringFunc(node.Value, func(placeholder string) string {
+		name := placeholder[2 : len(placeholder)-2]
+		v, ok := vars[name]
+		if !ok {
+			missing[name] = true
+			return placeholder
+		}
+		return fmt.Sprint(v)
+	})
+	return nil
+}
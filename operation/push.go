@@ -19,14 +19,58 @@ const (
 	StrategyNone StrategyMode = iota
 	StrategyBlueGreen
 	StrategyRolling
+	StrategyCanary
 )
 
+// packageBits uploads an app's files to a package
+type packageBits interface {
+	Upload(ctx context.Context, cf *client.Client, packageGUID string) (*resource.Package, error)
+}
+
+// zipBits uploads an already zipped app
+type zipBits struct {
+	zipFile io.Reader
+}
+
+func (z zipBits) Upload(ctx context.Context, cf *client.Client, packageGUID string) (*resource.Package, error) {
+	return cf.Packages.Upload(ctx, packageGUID, z.zipFile)
+}
+
+// pathBits packages and uploads the app at the path when it's needed, an app directory only uploads
+// the files that aren't already cached
+type pathBits struct {
+	path string
+}
+
+func (b pathBits) Upload(ctx context.Context, cf *client.Client, packageGUID string) (*resource.Package, error) {
+	appPath := b.path
+	if appPath == "" {
+		appPath = "."
+	}
+	if fi, err := os.Stat(appPath); err == nil && fi.IsDir() {
+		bits, err := NewAppBits(appPath)
+		if err != nil {
+			return nil, err
+		}
+		return bits.Upload(ctx, cf, packageGUID)
+	}
+	zipFile, err := OpenAppZip(appPath)
+	if err != nil {
+		return nil, fmt.Errorf("error packaging app %s: %w", appPath, err)
+	}
+	defer zipFile.Close()
+	return cf.Packages.Upload(ctx, packageGUID, zipFile)
+}
+
 // AppPushOperation can be used to push buildpack apps
 type AppPushOperation struct {
-	orgName   string
-	spaceName string
-	client    *client.Client
-	strategy  StrategyMode
+	orgName     string
+	spaceName   string
+	client      *client.Client
+	strategy    StrategyMode
+	rolling     *RollingOptions
+	canary      *CanaryOptions
+	stagingLogs io.Writer
 }
 
 // NewAppPushOperation creates a new AppPushOperation
@@ -41,15 +85,51 @@ func NewAppPushOperation(client *client.Client, orgName, spaceName string) *AppP
 }
 func (p *AppPushOperation) WithStrategy(s StrategyMode) {
 	switch s {
-	case StrategyBlueGreen, StrategyRolling:
+	case StrategyBlueGreen, StrategyRolling, StrategyCanary:
 		p.strategy = s
 	default:
 		p.strategy = StrategyNone
 	}
 }
 
-// Push creates or updates an application using the specified manifest and zipped source files
+// RollingOptions configures a rolling push, see resource.DeploymentOptions
+type RollingOptions struct {
+	MaxInFlight    int                    // the maximum number of new instances started at the same time
+	PollingOptions *client.PollingOptions // overrides waiting a minute for each batch of instances started at the same time
+}
+
+// WithRollingOptions configures the deployment used when the strategy is StrategyRolling
+func (p *AppPushOperation) WithRollingOptions(opts *RollingOptions) {
+	p.rolling = opts
+}
+
+// WithStagingLogs streams the staging output of the app to the specified writer while the app is built
+func (p *AppPushOperation) WithStagingLogs(w io.Writer) {
+	p.stagingLogs = w
+}
+
+// Push creates or updates an application using the specified manifest and zipped source files.
+//
+// If zipFile is nil the app is packaged from the manifest's path, or the current directory if the
+// manifest doesn't have a path, the same as cf push
 func (p *AppPushOperation) Push(ctx context.Context, appManifest *AppManifest, zipFile io.Reader) (*resource.App, error) {
+	if zipFile == nil {
+		return p.push(ctx, appManifest, pathBits{path: appManifest.Path})
+	}
+	return p.push(ctx, appManifest, zipBits{zipFile: zipFile})
+}
+
+// PushDir creates or updates an application using the specified manifest and the files in the app directory,
+// excluding any ignored by its .cfignore file. Only the files that CF hasn't already cached are uploaded
+func (p *AppPushOperation) PushDir(ctx context.Context, appManifest *AppManifest, dir string) (*resource.App, error) {
+	bits, err := NewAppBits(dir)
+	if err != nil {
+		return nil, err
+	}
+	return p.push(ctx, appManifest, bits)
+}
+
+func (p *AppPushOperation) push(ctx context.Context, appManifest *AppManifest, bits packageBits) (*resource.App, error) {
 	org, err := p.findOrg(ctx)
 	if err != nil {
 		return nil, err
@@ -58,93 +138,33 @@ func (p *AppPushOperation) Push(ctx context.Context, appManifest *AppManifest, z
 	if err != nil {
 		return nil, err
 	}
-	return p.pushWithStrategyApp(ctx, space, appManifest, zipFile)
+	return p.pushWithStrategyApp(ctx, space, appManifest, bits)
 }
-func (p *AppPushOperation) pushWithStrategyApp(ctx context.Context, space *resource.Space, manifest *AppManifest, zipFile io.Reader) (*resource.App, error) {
+func (p *AppPushOperation) pushWithStrategyApp(ctx context.Context, space *resource.Space, manifest *AppManifest, bits packageBits) (*resource.App, error) {
 	switch p.strategy {
 	case StrategyBlueGreen:
-		return p.pushBlueGreenApp(ctx, space, manifest, zipFile)
+		return p.pushBlueGreenApp(ctx, space, manifest, bits)
 	case StrategyRolling:
-		return p.pushRollingApp(ctx, space, manifest, zipFile)
+		return p.pushRollingApp(ctx, space, manifest, bits)
+	case StrategyCanary:
+		return p.pushCanaryApp(ctx, space, manifest, bits)
 	default:
-		return p.pushApp(ctx, space, manifest, zipFile)
-	}
-}
-
-func (p *AppPushOperation) pushBlueGreenApp(ctx context.Context, space *resource.Space, manifest *AppManifest, zipFile io.Reader) (*resource.App, error) {
-	originalApp, err := p.findApp(ctx, manifest.Name, space)
-	if err != nil && err != client.ErrExactlyOneResultNotReturned {
-		return nil, err
-	}
-	if err == client.ErrExactlyOneResultNotReturned || originalApp.State != "STARTED" {
-		return p.pushApp(ctx, space, manifest, zipFile)
-	}
-
-	tempAppName := originalApp.Name + "-venerable"
-
-	// Check if temporary app name already exists if yes gracefully delete the app and continue
-	tempApp, err := p.findApp(ctx, tempAppName, space)
-	if err == nil {
-		err = p.gracefulDeletion(ctx, tempApp)
-		if err != nil {
-			return nil, err
-		}
-	}
-
-	// Update the existing app's name
-	_, err = p.client.Applications.Update(ctx, originalApp.GUID, &resource.AppUpdate{
-		Name: tempAppName,
-	})
-	if err != nil {
-		return nil, fmt.Errorf("failed to update app name failed with: %s", err.Error())
-	}
-
-	// Apply the manifest
-	newApp, err := p.pushApp(ctx, space, manifest, zipFile)
-	if err != nil {
-		// If push fails change back original app name
-		_, err = p.client.Applications.Update(ctx, originalApp.GUID, &resource.AppUpdate{
-			Name: originalApp.Name,
-		})
-		if err != nil {
-			return nil, fmt.Errorf("failed to update app name back to original name: failed with %s", err.Error())
-		}
-		return nil, fmt.Errorf("blue green deployment failed with: %s", err.Error())
+		return p.pushApp(ctx, space, manifest, bits)
 	}
-	if newApp.State == "STARTED" {
-		err = p.gracefulDeletion(ctx, originalApp)
-		return newApp, err
-	}
-	return newApp, fmt.Errorf("failed to verify application start: %s", err.Error())
 }
 
-func (p *AppPushOperation) pushRollingApp(ctx context.Context, space *resource.Space, manifest *AppManifest, zipFile io.Reader) (*resource.App, error) {
+func (p *AppPushOperation) pushRollingApp(ctx context.Context, space *resource.Space, manifest *AppManifest, bits packageBits) (*resource.App, error) {
 	originalApp, err := p.findApp(ctx, manifest.Name, space)
 	if err != nil && err != client.ErrExactlyOneResultNotReturned {
 		return nil, err
 	}
 	if err == client.ErrExactlyOneResultNotReturned || originalApp.State != "STARTED" {
-		return p.pushApp(ctx, space, manifest, zipFile)
+		return p.pushApp(ctx, space, manifest, bits)
 	}
 	// Get the fallback revision in case of rollback
 	fallbackRevision, _ := p.client.Revisions.SingleForAppDeployed(ctx, originalApp.GUID, nil)
 
-	err = p.applySpaceManifest(ctx, space, manifest)
-	if err != nil {
-		return nil, err
-	}
-
-	var pkg *resource.Package
-	if manifest.Docker != nil {
-		pkg, err = p.uploadDockerPackage(ctx, originalApp, manifest.Docker)
-	} else {
-		pkg, err = p.uploadBitsPackage(ctx, originalApp, zipFile)
-	}
-	if err != nil {
-		return nil, err
-	}
-
-	droplet, err := p.buildDroplet(ctx, pkg, manifest)
+	droplet, err := p.stageDroplet(ctx, space, originalApp, manifest, bits)
 	if err != nil {
 		return nil, err
 	}
@@ -155,7 +175,8 @@ func (p *AppPushOperation) pushRollingApp(ctx context.Context, space *resource.S
 	}
 	// In case application crashed due to new deployment, deployment will be stuck with value "ACTIVE" and reason "DEPLOYING"
 	// This will be considered as deployment failed after timeout
-	depPollErr := p.waitForDeployment(ctx, deployment.GUID, *manifest.Instances)
+	pollOptions := p.rollingPollingOptions(manifest)
+	depPollErr := p.waitForDeployment(ctx, deployment.GUID, pollOptions)
 
 	// Check the app state if app not started or deployment failed rollback the deployment
 	originalApp, err = p.findApp(ctx, manifest.Name, space)
@@ -167,7 +188,7 @@ func (p *AppPushOperation) pushRollingApp(ctx context.Context, space *resource.S
 		if rollBackErr != nil {
 			return nil, fmt.Errorf("failed to confirm rollback deployment with: %s", rollBackErr.Error())
 		}
-		depRollPollErr := p.waitForDeployment(ctx, rollBackDeployment.GUID, *manifest.Instances)
+		depRollPollErr := p.waitForDeployment(ctx, rollBackDeployment.GUID, pollOptions)
 		if depRollPollErr != nil {
 			return nil, fmt.Errorf("failed to deploy with: %s \nfailed to confirm roll back to last deployment with: %s", depPollErr.Error(), depRollPollErr.Error())
 		}
@@ -177,24 +198,66 @@ func (p *AppPushOperation) pushRollingApp(ctx context.Context, space *resource.S
 	return originalApp, nil
 }
 
-// Poll for deployment status and wait for the deployment to be in the final state
-// Timeout is calculated based on the number of instances
-func (p *AppPushOperation) waitForDeployment(ctx context.Context, deploymentGUID string, instances uint) error {
+// stageDroplet applies the manifest to the running app and stages a new droplet for it without changing
+// the app's current droplet, so the droplet can then be deployed
+func (p *AppPushOperation) stageDroplet(ctx context.Context, space *resource.Space, app *resource.App, manifest *AppManifest, bits packageBits) (*resource.Droplet, error) {
+	err := p.applySpaceManifest(ctx, space, manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	var pkg *resource.Package
+	if manifest.Docker != nil {
+		pkg, err = p.uploadDockerPackage(ctx, app, manifest.Docker)
+	} else {
+		pkg, err = p.uploadBitsPackage(ctx, app, bits)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return p.buildDroplet(ctx, app, pkg, manifest)
+}
+
+// Poll for deployment status and wait for the deployment to be in the final state, a *client.DeploymentFailedError
+// is returned if the deployment is cancelled or degenerate
+func (p *AppPushOperation) waitForDeployment(ctx context.Context, deploymentGUID string, pollOptions *client.PollingOptions) error {
+	return p.client.Deployments.PollFinalized(ctx, deploymentGUID, pollOptions)
+}
+
+// rollingPollingOptions returns the configured rolling polling options or a timeout based on the number of
+// instances and how many are started at the same time
+func (p *AppPushOperation) rollingPollingOptions(manifest *AppManifest) *client.PollingOptions {
+	if p.rolling != nil && p.rolling.PollingOptions != nil {
+		return p.rolling.PollingOptions
+	}
+	var maxInFlight int
+	if p.rolling != nil {
+		maxInFlight = p.rolling.MaxInFlight
+	}
+	return deploymentPollingOptions(manifestInstances(manifest), maxInFlight)
+}
+
+// deploymentPollingOptions allows a deployment a minute for each batch of instances started at the same time
+func deploymentPollingOptions(instances uint, maxInFlight int) *client.PollingOptions {
 	// If instances is not set default to 1
 	if instances == 0 {
 		instances = 1
 	}
+	if maxInFlight < 1 {
+		maxInFlight = 1
+	}
+	batches := (instances + uint(maxInFlight) - 1) / uint(maxInFlight)
 	pollOptions := client.NewPollingOptions()
-	pollOptions.Timeout = time.Duration(instances) * time.Minute
+	pollOptions.Timeout = time.Duration(batches) * time.Minute
+	return pollOptions
+}
 
-	depPollErr := client.PollForStateOrTimeout(func() (string, error) {
-		deployment, err := p.client.Deployments.Get(ctx, deploymentGUID)
-		if err != nil {
-			return "", err
-		}
-		return deployment.Status.Value, nil
-	}, "FINALIZED", pollOptions)
-	return depPollErr
+// manifestInstances returns the number of web instances in the manifest or 0 if it isn't set
+func manifestInstances(manifest *AppManifest) uint {
+	if manifest.Instances == nil {
+		return 0
+	}
+	return *manifest.Instances
 }
 
 func (p *AppPushOperation) createNewDeployment(ctx context.Context, originalApp *resource.App, droplet *resource.Droplet) (*resource.Deployment, error) {
@@ -209,6 +272,7 @@ func (p *AppPushOperation) createNewDeployment(ctx context.Context, originalApp
 		Droplet: &resource.Relationship{
 			GUID: droplet.GUID,
 		},
+		Options: p.rollingDeploymentOptions(),
 	})
 }
 
@@ -224,9 +288,19 @@ func (p *AppPushOperation) rollBackDeployment(ctx context.Context, originalApp *
 		Revision: &resource.DeploymentRevision{
 			GUID: fallbackRevision.GUID,
 		},
+		Options: p.rollingDeploymentOptions(),
 	})
 }
 
+func (p *AppPushOperation) rollingDeploymentOptions() *resource.DeploymentOptions {
+	if p.rolling == nil || p.rolling.MaxInFlight < 1 {
+		return nil
+	}
+	return &resource.DeploymentOptions{
+		MaxInFlight: p.rolling.MaxInFlight,
+	}
+}
+
 // Stop the application and delete it
 // https://github.com/cloudfoundry/cloud_controller_ng/issues/1017
 func (p *AppPushOperation) gracefulDeletion(ctx context.Context, app *resource.App) error {
@@ -241,7 +315,6 @@ func (p *AppPushOperation) gracefulDeletion(ctx context.Context, app *resource.A
 	err = p.client.Jobs.PollComplete(ctx, jobId, &client.PollingOptions{
 		Timeout:       20 * time.Minute,
 		CheckInterval: time.Second * 5,
-		FailedState:   string(resource.JobStateFailed),
 	})
 	if err != nil {
 		return err
@@ -255,7 +328,7 @@ func (p *AppPushOperation) gracefulDeletion(ctx context.Context, app *resource.A
 // an application to be deployed or tasks to be run. The current droplet must be assigned to an application before
 // it may be started. When tasks are created, they either use a specific droplet guid, or use the current droplet
 // assigned to an application.
-func (p *AppPushOperation) pushApp(ctx context.Context, space *resource.Space, manifest *AppManifest, zipFile io.Reader) (*resource.App, error) {
+func (p *AppPushOperation) pushApp(ctx context.Context, space *resource.Space, manifest *AppManifest, bits packageBits) (*resource.App, error) {
 	err := p.applySpaceManifest(ctx, space, manifest)
 	if err != nil {
 		return nil, err
@@ -270,13 +343,13 @@ func (p *AppPushOperation) pushApp(ctx context.Context, space *resource.Space, m
 	if app.Lifecycle.Type == resource.LifecycleDocker.String() {
 		pkg, err = p.uploadDockerPackage(ctx, app, manifest.Docker)
 	} else {
-		pkg, err = p.uploadBitsPackage(ctx, app, zipFile)
+		pkg, err = p.uploadBitsPackage(ctx, app, bits)
 	}
 	if err != nil {
 		return nil, err
 	}
 
-	droplet, err := p.buildDroplet(ctx, pkg, manifest)
+	droplet, err := p.buildDroplet(ctx, app, pkg, manifest)
 	if err != nil {
 		return nil, err
 	}
@@ -330,13 +403,13 @@ func (p *AppPushOperation) uploadDockerPackage(ctx context.Context, app *resourc
 	return pkg, nil
 }
 
-func (p *AppPushOperation) uploadBitsPackage(ctx context.Context, app *resource.App, zipFile io.Reader) (*resource.Package, error) {
+func (p *AppPushOperation) uploadBitsPackage(ctx context.Context, app *resource.App, bits packageBits) (*resource.Package, error) {
 	newPkg := resource.NewPackageCreate(app.GUID)
 	pkg, err := p.client.Packages.Create(ctx, newPkg)
 	if err != nil {
 		return nil, fmt.Errorf("error creating package bits for app %s: %w", app.Name, err)
 	}
-	_, err = p.client.Packages.Upload(ctx, pkg.GUID, zipFile)
+	_, err = bits.Upload(ctx, p.client, pkg.GUID)
 	if err != nil {
 		return nil, fmt.Errorf("error uploading package bits for app %s: %w", app.Name, err)
 	}
@@ -347,7 +420,12 @@ func (p *AppPushOperation) uploadBitsPackage(ctx context.Context, app *resource.
 	return pkg, nil
 }
 
-func (p *AppPushOperation) buildDroplet(ctx context.Context, pkg *resource.Package, manifest *AppManifest) (*resource.Droplet, error) {
+func (p *AppPushOperation) buildDroplet(ctx context.Context, app *resource.App, pkg *resource.Package, manifest *AppManifest) (*resource.Droplet, error) {
+	if p.stagingLogs != nil {
+		stop := p.tailStagingLogs(ctx, app.GUID)
+		defer stop()
+	}
+
 	newBuild := resource.NewBuildCreate(pkg.GUID)
 	if pkg.Type == resource.LifecycleDocker.String() {
 		newBuild.Lifecycle = &resource.Lifecycle{Type: pkg.Type}
@@ -378,23 +456,62 @@ func (p *AppPushOperation) buildDroplet(ctx context.Context, pkg *resource.Packa
 	return droplet, nil
 }
 
+// tailStagingLogs writes the app's staging logs to the staging log writer until the returned stop func is called
+func (p *AppPushOperation) tailStagingLogs(ctx context.Context, appGUID string) func() {
+	ctx, cancel := context.WithCancel(ctx)
+	envelopes, errs := p.client.LogStream.Tail(ctx, appGUID, nil)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for envelopes != nil || errs != nil {
+			select {
+			case e, ok := <-envelopes:
+				if !ok {
+					envelopes = nil
+					continue
+				}
+				if e.Log != nil && e.Tags["source_type"] == "STG" {
+					_, _ = fmt.Fprintln(p.stagingLogs, string(e.Log.Payload))
+				}
+			case _, ok := <-errs:
+				// staging output is best effort, so stream errors don't fail the push
+				if !ok {
+					errs = nil
+				}
+			}
+		}
+	}()
+	return func() {
+		cancel()
+		<-done
+	}
+}
+
 func (p *AppPushOperation) findOrg(ctx context.Context) (*resource.Organization, error) {
+	return findOrgByName(ctx, p.client, p.orgName)
+}
+
+func (p *AppPushOperation) findSpace(ctx context.Context, orgGUID string) (*resource.Space, error) {
+	return findSpaceByName(ctx, p.client, orgGUID, p.spaceName)
+}
+
+func findOrgByName(ctx context.Context, cf *client.Client, orgName string) (*resource.Organization, error) {
 	opts := client.NewOrganizationListOptions()
-	opts.Names.EqualTo(p.orgName)
-	org, err := p.client.Organizations.Single(ctx, opts)
+	opts.Names.EqualTo(orgName)
+	org, err := cf.Organizations.Single(ctx, opts)
 	if err != nil {
-		return nil, fmt.Errorf("could not find org %s: %w", p.orgName, err)
+		return nil, fmt.Errorf("could not find org %s: %w", orgName, err)
 	}
 	return org, nil
 }
 
-func (p *AppPushOperation) findSpace(ctx context.Context, orgGUID string) (*resource.Space, error) {
+func findSpaceByName(ctx context.Context, cf *client.Client, orgGUID, spaceName string) (*resource.Space, error) {
 	opts := client.NewSpaceListOptions()
-	opts.Names.EqualTo(p.spaceName)
+	opts.Names.EqualTo(spaceName)
 	opts.OrganizationGUIDs.EqualTo(orgGUID)
-	space, err := p.client.Spaces.Single(ctx, opts)
+	space, err := cf.Spaces.Single(ctx, opts)
 	if err != nil {
-		return nil, fmt.Errorf("could not find space %s: %w", p.spaceName, err)
+		return nil, fmt.Errorf("could not find space %s: %w", spaceName, err)
 	}
 	return space, nil
 }
@@ -0,0 +1,345 @@
+package operation
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/cloudfoundry/go-cfclient/v3/client"
+	"github.com/cloudfoundry/go-cfclient/v3/resource"
+)
+
+const (
+	// blueGreenTempSuffix is appended to the app name while the new app is pushed alongside the running app
+	blueGreenTempSuffix = "-green"
+
+	// venerableSuffix is appended to the name of the previous app once the new app is serving its routes
+	venerableSuffix = "-venerable"
+
+	processStateRunning = "RUNNING"
+	processStateCrashed = "CRASHED"
+)
+
+// rollbackSteps undoes the completed steps of an operation in reverse order
+type rollbackSteps []func(ctx context.Context) error
+
+func (r *rollbackSteps) add(undo func(ctx context.Context) error) {
+	*r = append(*r, undo)
+}
+
+// rollback undoes every step and returns the cause along with any steps that couldn't be undone. The steps
+// are undone even when ctx is done, which is often why the operation failed
+func (r rollbackSteps) rollback(ctx context.Context, cause error) error {
+	ctx, cancel := cleanupContext(ctx, 0)
+	defer cancel()
+	var failed []string
+	for i := len(r) - 1; i >= 0; i-- {
+		if err := r[i](ctx); err != nil {
+			failed = append(failed, err.Error())
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("blue green push failed with: %w\nfailed to roll back with: %s", cause, strings.Join(failed, "; "))
+	}
+	return fmt.Errorf("blue green push failed with: %w\nrolled back to the running app", cause)
+}
+
+// pushBlueGreenApp pushes the new version of a running app alongside it on a temporary route. Once all the
+// new instances are running the new app is mapped to the running app's routes, the running app is unmapped
+// and stopped, and then renamed with the -venerable suffix so the new app can take its name. Finally the
+// new app is mapped to the routes in the manifest and the temporary route is deleted. Any failure rolls back
+// every completed step so the original app keeps serving its routes.
+//
+// A manifest with no-route pushes the new app without any routes, so it doesn't take over the running app's routes
+func (p *AppPushOperation) pushBlueGreenApp(ctx context.Context, space *resource.Space, manifest *AppManifest, bits packageBits) (*resource.App, error) {
+	originalApp, err := p.findApp(ctx, manifest.Name, space)
+	if err != nil && err != client.ErrExactlyOneResultNotReturned {
+		return nil, err
+	}
+	if err == client.ErrExactlyOneResultNotReturned || originalApp.State != "STARTED" {
+		return p.pushApp(ctx, space, manifest, bits)
+	}
+
+	var routes []*resource.Route
+	if !manifest.NoRoute {
+		routes, err = p.client.Routes.ListForAppAll(ctx, originalApp.GUID, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list routes for app %s: %w", originalApp.Name, err)
+		}
+	}
+
+	// remove any apps left behind by a previous push
+	venerableName := originalApp.Name + venerableSuffix
+	tempName := originalApp.Name + blueGreenTempSuffix
+	for _, name := range []string{venerableName, tempName} {
+		if err = p.deleteAppIfExists(ctx, name, space); err != nil {
+			return nil, err
+		}
+	}
+
+	var undo rollbackSteps
+
+	// push the new app on a random temporary route
+	tempManifest := *manifest
+	tempManifest.Name = tempName
+	tempManifest.Routes = nil
+	tempManifest.DefaultRoute = false
+	tempManifest.RandomRoute = !manifest.NoRoute
+	var tempRoutes []*resource.Route
+	tempRoutesKnown := false
+	undo.add(func(ctx context.Context) error {
+		if !tempRoutesKnown {
+			// the new app isn't mapped to any other routes yet
+			return p.deleteAppAndTempRoutes(ctx, tempName, space, routes)
+		}
+		if err := p.deleteRoutes(ctx, tempRoutes); err != nil {
+			return err
+		}
+		return p.deleteAppIfExists(ctx, tempName, space)
+	})
+	newApp, err := p.pushApp(ctx, space, &tempManifest, bits)
+	if err != nil {
+		return nil, undo.rollback(ctx, err)
+	}
+	tempRoutes, err = p.client.Routes.ListForAppAll(ctx, newApp.GUID, nil)
+	if err != nil {
+		return nil, undo.rollback(ctx, fmt.Errorf("failed to list routes for app %s: %w", tempName, err))
+	}
+	tempRoutesKnown = true
+	if err = p.waitForInstancesRunning(ctx, newApp.GUID, manifestInstances(manifest)); err != nil {
+		return nil, undo.rollback(ctx, err)
+	}
+
+	// map the new app to the routes before unmapping the original app so there's no downtime
+	for _, route := range routes {
+		route := route
+		dest, err := p.mapRouteLike(ctx, route, originalApp.GUID, newApp.GUID)
+		if err != nil {
+			return nil, undo.rollback(ctx, err)
+		}
+		undo.add(func(ctx context.Context) error {
+			return p.unmapRouteDestinations(ctx, route.GUID, dest)
+		})
+	}
+	for _, route := range routes {
+		route := route
+		dest := appDestinations(route.Destinations, originalApp.GUID)
+		if err = p.unmapRouteDestinations(ctx, route.GUID, dest); err != nil {
+			return nil, undo.rollback(ctx, err)
+		}
+		undo.add(func(ctx context.Context) error {
+			_, err := p.client.Routes.InsertDestinations(ctx, route.GUID, copyDestinations(dest, originalApp.GUID))
+			return err
+		})
+	}
+
+	if _, err = p.client.Applications.Stop(ctx, originalApp.GUID); err != nil {
+		return nil, undo.rollback(ctx, fmt.Errorf("failed to stop app %s: %w", originalApp.Name, err))
+	}
+	undo.add(func(ctx context.Context) error {
+		_, err := p.client.Applications.Start(ctx, originalApp.GUID)
+		return err
+	})
+
+	// swap the names so the new app takes the original name
+	if err = p.renameApp(ctx, originalApp.GUID, venerableName); err != nil {
+		return nil, undo.rollback(ctx, err)
+	}
+	undo.add(func(ctx context.Context) error {
+		return p.renameApp(ctx, originalApp.GUID, originalApp.Name)
+	})
+	if err = p.renameApp(ctx, newApp.GUID, originalApp.Name); err != nil {
+		return nil, undo.rollback(ctx, err)
+	}
+	undo.add(func(ctx context.Context) error {
+		return p.renameApp(ctx, newApp.GUID, tempName)
+	})
+	newApp.Name = originalApp.Name
+
+	// the running app didn't serve any routes the manifest adds, so they're mapped once the new app is live
+	if err = p.mapManifestRoutes(ctx, space, manifest); err != nil {
+		return nil, undo.rollback(ctx, err)
+	}
+
+	// the new app is live, failing to clean up its temporary route doesn't warrant a rollback
+	if err = p.deleteRoutes(ctx, tempRoutes); err != nil {
+		return newApp, fmt.Errorf("blue green push succeeded but failed to delete the temporary route: %w", err)
+	}
+	return newApp, nil
+}
+
+// mapManifestRoutes maps the app to the routes in its manifest, or its default route, creating any that don't exist
+func (p *AppPushOperation) mapManifestRoutes(ctx context.Context, space *resource.Space, manifest *AppManifest) error {
+	if manifest.NoRoute || (manifest.Routes == nil && !manifest.DefaultRoute) {
+		return nil
+	}
+	routesManifest := &AppManifest{
+		Name:         manifest.Name,
+		Routes:       manifest.Routes,
+		DefaultRoute: manifest.DefaultRoute,
+	}
+	if err := p.applySpaceManifest(ctx, space, routesManifest); err != nil {
+		return fmt.Errorf("failed to map the manifest routes to app %s: %w", manifest.Name, err)
+	}
+	return nil
+}
+
+// waitForInstancesRunning polls the app's web process stats until every instance is running, failing if
+// any instance crashes
+func (p *AppPushOperation) waitForInstancesRunning(ctx context.Context, appGUID string, instances uint) error {
+	_, err := client.PollForState(ctx, func(ctx context.Context) (string, error) {
+		stats, err := p.client.Processes.GetStatsForApp(ctx, appGUID, string(Web))
+		if err != nil {
+			return "", err
+		}
+		if len(stats.Stats) == 0 {
+			return "", nil
+		}
+		state := processStateRunning
+		for _, s := range stats.Stats {
+			if s.State == processStateCrashed {
+				return processStateCrashed, nil
+			}
+			if s.State != processStateRunning {
+				state = s.State
+			}
+		}
+		return state, nil
+	}, []string{processStateRunning}, []string{processStateCrashed}, deploymentPollingOptions(instances, 0))
+	if err != nil {
+		return fmt.Errorf("failed waiting for the new app's instances to be running: %w", err)
+	}
+	return nil
+}
+
+// mapRouteLike maps the new app to the route using the same process, port and protocol as the original
+// app's destinations, returning the new destinations
+func (p *AppPushOperation) mapRouteLike(ctx context.Context, route *resource.Route, originalAppGUID, newAppGUID string) ([]resource.RouteDestination, error) {
+	original := appDestinations(route.Destinations, originalAppGUID)
+	if len(original) == 0 {
+		return nil, nil
+	}
+	destinations, err := p.client.Routes.InsertDestinations(ctx, route.GUID, copyDestinations(original, newAppGUID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to map route %s to the new app: %w", route.URL, err)
+	}
+	var inserted []resource.RouteDestination
+	for _, d := range destinations.Destinations {
+		if d.App.GUID != nil && *d.App.GUID == newAppGUID {
+			inserted = append(inserted, *d)
+		}
+	}
+	return inserted, nil
+}
+
+// unmapRouteDestinations removes the destinations from the route
+func (p *AppPushOperation) unmapRouteDestinations(ctx context.Context, routeGUID string, destinations []resource.RouteDestination) error {
+	for _, d := range destinations {
+		if d.GUID == nil {
+			continue
+		}
+		if err := p.client.Routes.RemoveDestination(ctx, routeGUID, *d.GUID); err != nil {
+			return fmt.Errorf("failed to unmap route %s destination %s: %w", routeGUID, *d.GUID, err)
+		}
+	}
+	return nil
+}
+
+// deleteAppAndTempRoutes deletes the app, if it was created, along with its routes other than the routes to keep
+func (p *AppPushOperation) deleteAppAndTempRoutes(ctx context.Context, appName string, space *resource.Space, keepRoutes []*resource.Route) error {
+	app, err := p.findApp(ctx, appName, space)
+	if err == client.ErrExactlyOneResultNotReturned {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	tempRoutes, err := p.otherRoutes(ctx, app.GUID, keepRoutes)
+	if err != nil {
+		return err
+	}
+	if err = p.deleteRoutes(ctx, tempRoutes); err != nil {
+		return err
+	}
+	return p.gracefulDeletion(ctx, app)
+}
+
+// otherRoutes returns the app's routes except for those to keep
+func (p *AppPushOperation) otherRoutes(ctx context.Context, appGUID string, keepRoutes []*resource.Route) ([]*resource.Route, error) {
+	routes, err := p.client.Routes.ListForAppAll(ctx, appGUID, nil)
+	if err != nil {
+		return nil, err
+	}
+	keep := map[string]bool{}
+	for _, r := range keepRoutes {
+		keep[r.GUID] = true
+	}
+	var other []*resource.Route
+	for _, r := range routes {
+		if !keep[r.GUID] {
+			other = append(other, r)
+		}
+	}
+	return other, nil
+}
+
+// deleteRoutes deletes the routes
+func (p *AppPushOperation) deleteRoutes(ctx context.Context, routes []*resource.Route) error {
+	for _, r := range routes {
+		jobGUID, err := p.client.Routes.Delete(ctx, r.GUID)
+		if err != nil {
+			return fmt.Errorf("failed to delete route %s: %w", r.URL, err)
+		}
+		if err = p.client.Jobs.PollComplete(ctx, jobGUID, nil); err != nil {
+			return fmt.Errorf("failed waiting for route %s to be deleted: %w", r.URL, err)
+		}
+	}
+	return nil
+}
+
+func (p *AppPushOperation) deleteAppIfExists(ctx context.Context, appName string, space *resource.Space) error {
+	app, err := p.findApp(ctx, appName, space)
+	if err == client.ErrExactlyOneResultNotReturned {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return p.gracefulDeletion(ctx, app)
+}
+
+func (p *AppPushOperation) renameApp(ctx context.Context, appGUID, name string) error {
+	_, err := p.client.Applications.Update(ctx, appGUID, &resource.AppUpdate{
+		Name: name,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to rename app to %s: %w", name, err)
+	}
+	return nil
+}
+
+// appDestinations returns the destinations for the app
+func appDestinations(destinations []resource.RouteDestination, appGUID string) []resource.RouteDestination {
+	var appDest []resource.RouteDestination
+	for _, d := range destinations {
+		if d.App.GUID != nil && *d.App.GUID == appGUID {
+			appDest = append(appDest, d)
+		}
+	}
+	return appDest
+}
+
+// copyDestinations copies the destinations' process, port and protocol to new destinations for the app
+func copyDestinations(destinations []resource.RouteDestination, appGUID string) []*resource.RouteDestinationInsertOrReplace {
+	copies := make([]*resource.RouteDestinationInsertOrReplace, len(destinations))
+	for i, d := range destinations {
+		c := resource.NewRouteDestinationInsertOrReplace(appGUID)
+		if d.App.Process != nil {
+			c.WithProcessType(d.App.Process.Type)
+		}
+		c.Port = d.Port
+		c.Protocol = d.Protocol
+		copies[i] = c
+	}
+	return copies
+}
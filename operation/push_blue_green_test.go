@@ -0,0 +1,411 @@
+package operation
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/cloudfoundry/go-cfclient/v3/client"
+	"github.com/cloudfoundry/go-cfclient/v3/config"
+	"github.com/cloudfoundry/go-cfclient/v3/testutil"
+
+	"github.com/stretchr/testify/require"
+)
+
+type blueGreenFixture struct {
+	g          *testutil.ObjectJSONGenerator
+	serverURL  string
+	oldApp     *testutil.JSONResource
+	newApp     *testutil.JSONResource
+	prodRoute  string
+	prodGUID   string
+	tempRoute  *testutil.JSONResource
+	job        *testutil.JSONResource
+	stats      string
+	oldDestURL string
+	newDestURL string
+
+	// substrings of each manifest applied, in order
+	applyBodies []string
+}
+
+func newBlueGreenFixture(serverURL string) *blueGreenFixture {
+	g := testutil.NewObjectJSONGenerator(8723)
+	f := &blueGreenFixture{
+		g:         g,
+		serverURL: serverURL,
+		oldApp:    g.Application(),
+		newApp:    g.Application(),
+		tempRoute: g.Route(),
+		job:       g.Job("COMPLETE"),
+		stats:     g.ProcessStats().JSON,
+	}
+	prod := g.Route()
+	f.prodGUID = prod.GUID
+	f.prodRoute = strings.Replace(prod.JSON, "0a6636b5-7fc4-44d8-8752-0db3e40b35a5", f.oldApp.GUID, 1)
+	f.oldDestURL = fmt.Sprintf("/v3/routes/%s/destinations/385bf117-17f5-4689-8c5c-08c6cc821fed", prod.GUID)
+	f.newDestURL = fmt.Sprintf("/v3/routes/%s/destinations/6b0c5a2c-3c1e-4b4e-a1a8-5d0d26f1f0c1", prod.GUID)
+	f.applyBodies = []string{fmt.Sprintf("name: %s-green", f.oldApp.Name)}
+	return f
+}
+
+// pushRoutes are the routes needed to find the running app and push the new app alongside it
+func (f *blueGreenFixture) pushRoutes(appsOutput []string, jobOutput []string) []testutil.MockRoute {
+	g := f.g
+	org := g.Organization()
+	space := g.Space()
+	pkg := g.Package("READY")
+	build := g.Build("STAGED")
+	droplet := g.Droplet()
+	return []testutil.MockRoute{
+		{
+			Method:   http.MethodGet,
+			Endpoint: "/v3/organizations",
+			Output:   g.SinglePaged(org.JSON),
+			Status:   http.StatusOK,
+		},
+		{
+			Method:   http.MethodGet,
+			Endpoint: "/v3/spaces",
+			Output:   g.SinglePaged(space.JSON),
+			Status:   http.StatusOK,
+		},
+		{
+			Method:   http.MethodGet,
+			Endpoint: "/v3/apps",
+			Output:   appsOutput,
+			Status:   http.StatusOK,
+		},
+		{
+			Method:   http.MethodGet,
+			Endpoint: fmt.Sprintf("/v3/apps/%s/routes", f.oldApp.GUID),
+			Output:   g.SinglePaged(f.prodRoute),
+			Status:   http.StatusOK,
+		},
+		{
+			Method:           "POST-FILE",
+			Endpoint:         fmt.Sprintf("/v3/spaces/%s/actions/apply_manifest", space.GUID),
+			Output:           make([]string, len(f.applyBodies)),
+			Status:           http.StatusAccepted,
+			PostForms:        f.applyBodies,
+			RedirectLocation: fmt.Sprintf("%s/v3/jobs/%s", f.serverURL, f.job.GUID),
+		},
+		{
+			Method:   http.MethodGet,
+			Endpoint: fmt.Sprintf("/v3/jobs/%s", f.job.GUID),
+			Output:   jobOutput,
+			Status:   http.StatusOK,
+		},
+		{
+			Method:   http.MethodPost,
+			Endpoint: "/v3/packages",
+			Output:   g.Single(pkg.JSON),
+			Status:   http.StatusCreated,
+		},
+		{
+			Method:   http.MethodPost,
+			Endpoint: fmt.Sprintf("/v3/packages/%s/upload", pkg.GUID),
+			Output:   g.Single(pkg.JSON),
+			Status:   http.StatusOK,
+		},
+		{
+			Method:   http.MethodGet,
+			Endpoint: fmt.Sprintf("/v3/packages/%s", pkg.GUID),
+			Output:   g.Single(pkg.JSON),
+			Status:   http.StatusOK,
+		},
+		{
+			Method:   http.MethodPost,
+			Endpoint: "/v3/builds",
+			Output:   g.Single(build.JSON),
+			Status:   http.StatusCreated,
+		},
+		{
+			Method:   http.MethodGet,
+			Endpoint: fmt.Sprintf("/v3/builds/%s", build.GUID),
+			Output:   g.Single(build.JSON),
+			Status:   http.StatusOK,
+		},
+		{
+			Method:   http.MethodGet,
+			Endpoint: fmt.Sprintf("/v3/packages/%s/droplets", pkg.GUID),
+			Output:   g.SinglePaged(droplet.JSON),
+			Status:   http.StatusOK,
+		},
+		{
+			Method:   http.MethodPatch,
+			Endpoint: fmt.Sprintf("/v3/apps/%s/relationships/current_droplet", f.newApp.GUID),
+			Output:   g.Single(g.DropletAssociation().JSON),
+			Status:   http.StatusOK,
+		},
+		{
+			Method:   http.MethodPost,
+			Endpoint: fmt.Sprintf("/v3/apps/%s/actions/start", f.newApp.GUID),
+			Output:   g.Single(f.newApp.JSON),
+			Status:   http.StatusOK,
+		},
+		{
+			Method:   http.MethodGet,
+			Endpoint: fmt.Sprintf("/v3/apps/%s/routes", f.newApp.GUID),
+			Output:   g.SinglePaged(f.tempRoute.JSON),
+			Status:   http.StatusOK,
+		},
+		{
+			Method:           http.MethodDelete,
+			Endpoint:         fmt.Sprintf("/v3/routes/%s", f.tempRoute.GUID),
+			Status:           http.StatusAccepted,
+			RedirectLocation: fmt.Sprintf("%s/v3/jobs/%s", f.serverURL, f.job.GUID),
+		},
+	}
+}
+
+// swapRoutes are the routes needed to move the running app's route to the new app and retire the running
+// app, the statuses are those of stopping the running app, renaming it and renaming the new app
+func (f *blueGreenFixture) swapRoutes(stopStatus int, renameOldStatuses []int, renameNewStatus int) []testutil.MockRoute {
+	g := f.g
+	newDest := fmt.Sprintf(`{"destinations":[{"guid":"6b0c5a2c-3c1e-4b4e-a1a8-5d0d26f1f0c1","app":{"guid":"%s","process":{"type":"web"}},"weight":null,"port":8080,"protocol":"tcp"}]}`,
+		f.newApp.GUID)
+	return []testutil.MockRoute{
+		{
+			Method:   http.MethodGet,
+			Endpoint: fmt.Sprintf("/v3/apps/%s/processes/web/stats", f.newApp.GUID),
+			Output:   []string{f.stats},
+			Status:   http.StatusOK,
+		},
+		{
+			// maps the new app and, when rolling back, the running app again
+			Method:   http.MethodPost,
+			Endpoint: fmt.Sprintf("/v3/routes/%s/destinations", f.prodGUID),
+			Output:   []string{newDest, f.prodRoute},
+			Status:   http.StatusOK,
+		},
+		{
+			Method:   http.MethodDelete,
+			Endpoint: f.oldDestURL,
+			Status:   http.StatusNoContent,
+		},
+		{
+			Method:   http.MethodPost,
+			Endpoint: fmt.Sprintf("/v3/apps/%s/actions/stop", f.oldApp.GUID),
+			Output:   g.Single(f.oldApp.JSON),
+			Status:   stopStatus,
+		},
+		{
+			Method:   http.MethodPatch,
+			Endpoint: fmt.Sprintf("/v3/apps/%s", f.oldApp.GUID),
+			Output:   []string{f.oldApp.JSON, f.oldApp.JSON},
+			Statuses: renameOldStatuses,
+		},
+		{
+			Method:   http.MethodPatch,
+			Endpoint: fmt.Sprintf("/v3/apps/%s", f.newApp.GUID),
+			Output:   g.Single(f.newApp.JSON),
+			Status:   renameNewStatus,
+		},
+	}
+}
+
+// rollbackRoutes are the routes needed to restart the running app and delete the new app
+func (f *blueGreenFixture) rollbackRoutes() []testutil.MockRoute {
+	g := f.g
+	return []testutil.MockRoute{
+		{
+			Method:   http.MethodPost,
+			Endpoint: fmt.Sprintf("/v3/apps/%s/actions/start", f.oldApp.GUID),
+			Output:   g.Single(f.oldApp.JSON),
+			Status:   http.StatusOK,
+		},
+		{
+			Method:   http.MethodDelete,
+			Endpoint: f.newDestURL,
+			Status:   http.StatusNoContent,
+		},
+		{
+			Method:   http.MethodPost,
+			Endpoint: fmt.Sprintf("/v3/apps/%s/actions/stop", f.newApp.GUID),
+			Output:   g.Single(f.newApp.JSON),
+			Status:   http.StatusOK,
+		},
+		{
+			Method:           http.MethodDelete,
+			Endpoint:         fmt.Sprintf("/v3/apps/%s", f.newApp.GUID),
+			Status:           http.StatusAccepted,
+			RedirectLocation: fmt.Sprintf("%s/v3/jobs/%s", f.serverURL, f.job.GUID),
+		},
+	}
+}
+
+func (f *blueGreenFixture) pusher(t *testing.T) *AppPushOperation {
+	return f.pusherWithHTTPClient(t, nil)
+}
+
+func (f *blueGreenFixture) pusherWithHTTPClient(t *testing.T, httpClient *http.Client) *AppPushOperation {
+	opts := []config.Option{config.Token("", "fake-refresh-token"), config.SkipTLSValidation()}
+	if httpClient != nil {
+		opts = append(opts, config.HttpClient(httpClient))
+	}
+	c, _ := config.New(f.serverURL, opts...)
+	cf, err := client.New(c)
+	require.NoError(t, err)
+
+	pusher := NewAppPushOperation(cf, "my-org", "my-space")
+	pusher.WithStrategy(StrategyBlueGreen)
+	return pusher
+}
+
+func (f *blueGreenFixture) manifest() *AppManifest {
+	return NewAppManifest(f.oldApp.Name)
+}
+
+func TestAppPushBlueGreen(t *testing.T) {
+	serverURL := testutil.SetupFakeAPIServer()
+	defer testutil.Teardown()
+
+	f := newBlueGreenFixture(serverURL)
+	// the manifest adds a route the running app doesn't have
+	f.applyBodies = append(f.applyBodies, "route: new-route.example.org")
+	g := f.g
+	noApps := g.Paged([]string{})
+	routes := append(f.pushRoutes(
+		[]string{g.SinglePaged(f.oldApp.JSON)[0], noApps[0], noApps[0], g.SinglePaged(f.newApp.JSON)[0]},
+		[]string{f.job.JSON, f.job.JSON, f.job.JSON}),
+		f.swapRoutes(http.StatusOK, []int{http.StatusOK}, http.StatusOK)...)
+	testutil.SetupMultiple(routes, t)
+
+	var applies int32
+	pusher := f.pusherWithHTTPClient(t, &http.Client{Transport: requestHook(func(req *http.Request) {
+		if strings.HasSuffix(req.URL.Path, "/actions/apply_manifest") {
+			atomic.AddInt32(&applies, 1)
+		}
+	})})
+	manifest := f.manifest()
+	manifest.Routes = &AppManifestRoutes{{Route: "new-route.example.org"}}
+	app, err := pusher.Push(context.Background(), manifest, strings.NewReader("blah zip zip"))
+	require.NoError(t, err)
+	require.Equal(t, f.newApp.GUID, app.GUID)
+	require.Equal(t, f.oldApp.Name, app.Name)
+	require.Equal(t, int32(len(f.applyBodies)), applies)
+}
+
+func TestAppPushBlueGreenRollback(t *testing.T) {
+	serverURL := testutil.SetupFakeAPIServer()
+	defer testutil.Teardown()
+
+	f := newBlueGreenFixture(serverURL)
+	g := f.g
+	noApps := g.Paged([]string{})
+	newApp := g.SinglePaged(f.newApp.JSON)[0]
+	routes := append(f.pushRoutes(
+		[]string{g.SinglePaged(f.oldApp.JSON)[0], noApps[0], noApps[0], newApp, newApp},
+		[]string{f.job.JSON, f.job.JSON, f.job.JSON}),
+		testutil.MockRoute{
+			Method:   http.MethodGet,
+			Endpoint: fmt.Sprintf("/v3/apps/%s/processes/web/stats", f.newApp.GUID),
+			Output:   []string{strings.Replace(f.stats, "RUNNING", "CRASHED", 1)},
+			Status:   http.StatusOK,
+		},
+		testutil.MockRoute{
+			Method:   http.MethodGet,
+			Endpoint: fmt.Sprintf("/v3/apps/%s/routes", f.newApp.GUID),
+			Output:   g.SinglePaged(f.tempRoute.JSON),
+			Status:   http.StatusOK,
+		},
+		testutil.MockRoute{
+			Method:   http.MethodPost,
+			Endpoint: fmt.Sprintf("/v3/apps/%s/actions/stop", f.newApp.GUID),
+			Output:   g.Single(f.newApp.JSON),
+			Status:   http.StatusOK,
+		},
+		testutil.MockRoute{
+			Method:           http.MethodDelete,
+			Endpoint:         fmt.Sprintf("/v3/apps/%s", f.newApp.GUID),
+			Status:           http.StatusAccepted,
+			RedirectLocation: fmt.Sprintf("%s/v3/jobs/%s", f.serverURL, f.job.GUID),
+		},
+	)
+	testutil.SetupMultiple(routes, t)
+
+	_, err := f.pusher(t).Push(context.Background(), f.manifest(), strings.NewReader("blah zip zip"))
+	require.ErrorIs(t, err, client.AsyncProcessFailedError)
+	require.ErrorContains(t, err, "rolled back to the running app")
+}
+
+// requestHook is called with each request before it's sent
+type requestHook func(req *http.Request)
+
+func (h requestHook) RoundTrip(req *http.Request) (*http.Response, error) {
+	h(req)
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestAppPushBlueGreenRollbackAfterUnmap(t *testing.T) {
+	serverURL := testutil.SetupFakeAPIServer()
+	defer testutil.Teardown()
+
+	f := newBlueGreenFixture(serverURL)
+	g := f.g
+	noApps := g.Paged([]string{})
+	newApp := g.SinglePaged(f.newApp.JSON)[0]
+	routes := append(f.pushRoutes(
+		[]string{g.SinglePaged(f.oldApp.JSON)[0], noApps[0], noApps[0], newApp, newApp},
+		[]string{f.job.JSON, f.job.JSON, f.job.JSON}),
+		f.swapRoutes(http.StatusOK, nil, http.StatusOK)...)
+	routes = append(routes, f.rollbackRoutes()...)
+	testutil.SetupMultiple(routes, t)
+
+	// the caller gives up while the running app is being stopped, once it's been unmapped
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stopPath := fmt.Sprintf("/v3/apps/%s/actions/stop", f.oldApp.GUID)
+	pusher := f.pusherWithHTTPClient(t, &http.Client{Transport: requestHook(func(req *http.Request) {
+		if req.Method == http.MethodPost && req.URL.Path == stopPath {
+			cancel()
+		}
+	})})
+	_, err := pusher.Push(ctx, f.manifest(), strings.NewReader("blah zip zip"))
+	require.ErrorIs(t, err, context.Canceled)
+	require.ErrorContains(t, err, "rolled back to the running app")
+}
+
+func TestAppPushBlueGreenRollbackAfterStop(t *testing.T) {
+	serverURL := testutil.SetupFakeAPIServer()
+	defer testutil.Teardown()
+
+	f := newBlueGreenFixture(serverURL)
+	g := f.g
+	noApps := g.Paged([]string{})
+	newApp := g.SinglePaged(f.newApp.JSON)[0]
+	routes := append(f.pushRoutes(
+		[]string{g.SinglePaged(f.oldApp.JSON)[0], noApps[0], noApps[0], newApp, newApp},
+		[]string{f.job.JSON, f.job.JSON, f.job.JSON}),
+		f.swapRoutes(http.StatusOK, []int{http.StatusUnprocessableEntity}, http.StatusOK)...)
+	routes = append(routes, f.rollbackRoutes()...)
+	testutil.SetupMultiple(routes, t)
+
+	_, err := f.pusher(t).Push(context.Background(), f.manifest(), strings.NewReader("blah zip zip"))
+	require.ErrorContains(t, err, "venerable")
+	require.ErrorContains(t, err, "rolled back to the running app")
+}
+
+func TestAppPushBlueGreenRollbackAfterRename(t *testing.T) {
+	serverURL := testutil.SetupFakeAPIServer()
+	defer testutil.Teardown()
+
+	f := newBlueGreenFixture(serverURL)
+	g := f.g
+	noApps := g.Paged([]string{})
+	newApp := g.SinglePaged(f.newApp.JSON)[0]
+	routes := append(f.pushRoutes(
+		[]string{g.SinglePaged(f.oldApp.JSON)[0], noApps[0], noApps[0], newApp, newApp},
+		[]string{f.job.JSON, f.job.JSON, f.job.JSON}),
+		f.swapRoutes(http.StatusOK, []int{http.StatusOK, http.StatusOK}, http.StatusUnprocessableEntity)...)
+	routes = append(routes, f.rollbackRoutes()...)
+	testutil.SetupMultiple(routes, t)
+
+	_, err := f.pusher(t).Push(context.Background(), f.manifest(), strings.NewReader("blah zip zip"))
+	require.ErrorContains(t, err, fmt.Sprintf("failed to rename app to %s", f.oldApp.Name))
+	require.ErrorContains(t, err, "rolled back to the running app")
+}
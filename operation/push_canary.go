@@ -0,0 +1,170 @@
+package operation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/cloudfoundry/go-cfclient/v3/client"
+	"github.com/cloudfoundry/go-cfclient/v3/resource"
+)
+
+// canaryPausedState is the pseudo state polled for when a canary deployment is paused at a new step
+const canaryPausedState = "PAUSED"
+
+// CanaryStepVerifier checks the health of the new instances while a canary deployment is paused at a step,
+// where step starts at 1. Returning an error cancels the deployment, rolling the app back to its previous droplet
+type CanaryStepVerifier func(ctx context.Context, deployment *resource.Deployment, step int) error
+
+// CanaryOptions configures a canary push, see resource.DeploymentOptions
+type CanaryOptions struct {
+	Steps       []resource.DeploymentCanaryStep // the canary steps, by default CC pauses once with a single new instance
+	MaxInFlight int                             // the maximum number of new instances started at the same time
+	Verify      CanaryStepVerifier              // verifies each paused step before continuing, nil always continues
+}
+
+// WithCanaryOptions configures the steps and verification used when the strategy is StrategyCanary
+func (p *AppPushOperation) WithCanaryOptions(opts *CanaryOptions) {
+	p.canary = opts
+}
+
+// pushCanaryApp stages a new droplet for a running app and deploys it with the canary strategy. The
+// deployment pauses at each step so the new instances can be verified before continuing, if the
+// verification fails the deployment is cancelled
+func (p *AppPushOperation) pushCanaryApp(ctx context.Context, space *resource.Space, manifest *AppManifest, bits packageBits) (*resource.App, error) {
+	originalApp, err := p.findApp(ctx, manifest.Name, space)
+	if err != nil && err != client.ErrExactlyOneResultNotReturned {
+		return nil, err
+	}
+	if err == client.ErrExactlyOneResultNotReturned || originalApp.State != "STARTED" {
+		return p.pushApp(ctx, space, manifest, bits)
+	}
+
+	droplet, err := p.stageDroplet(ctx, space, originalApp, manifest, bits)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := p.canary
+	if opts == nil {
+		opts = &CanaryOptions{}
+	}
+	r := resource.NewDeploymentCreate(originalApp.GUID)
+	r.Droplet = &resource.Relationship{
+		GUID: droplet.GUID,
+	}
+	r.Strategy = resource.DeploymentStrategyCanary
+	if opts.MaxInFlight > 0 || len(opts.Steps) > 0 {
+		r.Options = &resource.DeploymentOptions{
+			MaxInFlight: opts.MaxInFlight,
+		}
+		if len(opts.Steps) > 0 {
+			r.Options.Canary = &resource.DeploymentCanaryOptions{
+				Steps: opts.Steps,
+			}
+		}
+	}
+	deployment, err := p.client.Deployments.Create(ctx, r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create canary deployment for app %s: %w", manifest.Name, err)
+	}
+
+	pollOptions := deploymentPollingOptions(manifestInstances(manifest), opts.MaxInFlight)
+	verifiedStep := 0
+	for {
+		deployment, err = p.waitForCanaryStep(ctx, deployment.GUID, verifiedStep, pollOptions)
+		if err != nil {
+			return nil, p.cancelDeployment(ctx, deployment.GUID, pollOptions, err)
+		}
+		if deployment.Status.Value == resource.DeploymentStatusValueFinalized {
+			break
+		}
+
+		step := canaryStep(deployment, verifiedStep)
+		if opts.Verify != nil {
+			if err = opts.Verify(ctx, deployment, step); err != nil {
+				return nil, p.cancelDeployment(ctx, deployment.GUID, pollOptions,
+					fmt.Errorf("canary step %d verification failed: %w", step, err))
+			}
+		}
+		if _, err = p.client.Deployments.Continue(ctx, deployment.GUID); err != nil {
+			return nil, p.cancelDeployment(ctx, deployment.GUID, pollOptions,
+				fmt.Errorf("failed to continue canary deployment past step %d: %w", step, err))
+		}
+		verifiedStep = step
+	}
+
+	if deployment.Status.Reason != resource.DeploymentStatusReasonDeployed {
+		return nil, &client.DeploymentFailedError{
+			GUID:   deployment.GUID,
+			Reason: deployment.Status.Reason,
+			Detail: deployment.Status.ErrorDetail(),
+		}
+	}
+	return p.client.Applications.Get(ctx, originalApp.GUID)
+}
+
+// waitForCanaryStep polls the deployment until it's paused at a step after the verified step or finalized
+func (p *AppPushOperation) waitForCanaryStep(ctx context.Context, deploymentGUID string, verifiedStep int, pollOptions *client.PollingOptions) (*resource.Deployment, error) {
+	deployment := &resource.Deployment{
+		Resource: resource.Resource{GUID: deploymentGUID},
+	}
+	// without the current step a pause can't be told apart from the pause at the verified step, read before
+	// the continue took effect, so after a continue the deployment has to be seen resuming first
+	resumed := verifiedStep == 0
+	_, err := client.PollForState(ctx, func(ctx context.Context) (string, error) {
+		d, err := p.client.Deployments.Get(ctx, deploymentGUID)
+		if err != nil {
+			return "", err
+		}
+		deployment = d
+		if d.Status.Reason != resource.DeploymentStatusReasonPaused {
+			resumed = true
+		}
+		if d.Status.Value != resource.DeploymentStatusValueFinalized && d.Status.Reason == resource.DeploymentStatusReasonPaused {
+			step, reported := reportedCanaryStep(d)
+			if reported && step > verifiedStep || !reported && resumed {
+				return canaryPausedState, nil
+			}
+		}
+		return d.Status.Value, nil
+	}, []string{resource.DeploymentStatusValueFinalized, canaryPausedState}, nil, pollOptions)
+	return deployment, err
+}
+
+// cancelDeployment cancels the deployment, waits for the app to roll back and returns the cause wrapped
+// with the outcome of the cancellation. The deployment is cancelled even when ctx is done so it isn't left
+// paused part way through rolling out the new droplet
+func (p *AppPushOperation) cancelDeployment(ctx context.Context, deploymentGUID string, pollOptions *client.PollingOptions, cause error) error {
+	ctx, cancel := cleanupContext(ctx, pollOptions.Timeout)
+	defer cancel()
+	if err := p.client.Deployments.Cancel(ctx, deploymentGUID); err != nil {
+		return fmt.Errorf("%w\nfailed to cancel deployment with: %s", cause, err.Error())
+	}
+	err := p.waitForDeployment(ctx, deploymentGUID, pollOptions)
+	var failedErr *client.DeploymentFailedError
+	if errors.As(err, &failedErr) && failedErr.Reason == resource.DeploymentStatusReasonCanceled {
+		err = nil
+	}
+	if err != nil {
+		return fmt.Errorf("%w\nfailed to confirm deployment was cancelled with: %s", cause, err.Error())
+	}
+	return fmt.Errorf("%w\ndeployment cancelled", cause)
+}
+
+// canaryStep returns the step the deployment is paused at, falling back to the step after the last
+// verified step when the API doesn't report the current step
+func canaryStep(deployment *resource.Deployment, verifiedStep int) int {
+	if step, ok := reportedCanaryStep(deployment); ok {
+		return step
+	}
+	return verifiedStep + 1
+}
+
+// reportedCanaryStep returns the current canary step if the API reports it
+func reportedCanaryStep(deployment *resource.Deployment) (int, bool) {
+	if deployment.Status.Canary != nil && deployment.Status.Canary.Steps.Current > 0 {
+		return deployment.Status.Canary.Steps.Current, true
+	}
+	return 0, false
+}
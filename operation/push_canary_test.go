@@ -0,0 +1,302 @@
+package operation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/cloudfoundry/go-cfclient/v3/client"
+	"github.com/cloudfoundry/go-cfclient/v3/config"
+	"github.com/cloudfoundry/go-cfclient/v3/resource"
+	"github.com/cloudfoundry/go-cfclient/v3/testutil"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAppPushCanary(t *testing.T) {
+	serverURL := testutil.SetupFakeAPIServer()
+	defer testutil.Teardown()
+
+	g := testutil.NewObjectJSONGenerator(8723)
+	app := g.Application()
+	droplet := g.Droplet()
+	deployment := g.DeploymentWithStatus("ACTIVE", "DEPLOYING")
+	paused := sameDeployment(deployment, g.DeploymentWithStatus("ACTIVE", "PAUSED"))
+	deployed := sameDeployment(deployment, g.DeploymentWithStatus("FINALIZED", "DEPLOYED"))
+	routes := append(canaryStagingRoutes(g, serverURL, app, droplet),
+		testutil.MockRoute{
+			Method:   http.MethodPost,
+			Endpoint: "/v3/deployments",
+			Output:   g.Single(deployment.JSON),
+			Status:   http.StatusCreated,
+			PostForm: fmt.Sprintf(`{"relationships":{"app":{"data":{"guid":"%s"}}},"droplet":{"guid":"%s"},"strategy":"canary","options":{"max_in_flight":2,"canary":{"steps":[{"instance_weight":25},{"instance_weight":50}]}}}`,
+				app.GUID, droplet.GUID),
+		},
+		testutil.MockRoute{
+			Method:   http.MethodGet,
+			Endpoint: fmt.Sprintf("/v3/deployments/%s", deployment.GUID),
+			// the API doesn't report the current step, so the pause read before the continue took effect
+			// mustn't be mistaken for the second step
+			Output: []string{deployment.JSON, paused, paused, deployment.JSON, paused, deployed},
+			Status: http.StatusOK,
+		},
+		testutil.MockRoute{
+			Method:   http.MethodPost,
+			Endpoint: fmt.Sprintf("/v3/deployments/%s/actions/continue", deployment.GUID),
+			Output:   []string{deployment.JSON, deployment.JSON},
+			Status:   http.StatusOK,
+		},
+		testutil.MockRoute{
+			Method:   http.MethodGet,
+			Endpoint: fmt.Sprintf("/v3/apps/%s", app.GUID),
+			Output:   g.Single(app.JSON),
+			Status:   http.StatusOK,
+		},
+	)
+	testutil.SetupMultiple(routes, t)
+
+	var verified []int
+	pusher := newCanaryPusher(t, serverURL, &CanaryOptions{
+		Steps:       []resource.DeploymentCanaryStep{{InstanceWeight: 25}, {InstanceWeight: 50}},
+		MaxInFlight: 2,
+		Verify: func(ctx context.Context, d *resource.Deployment, step int) error {
+			require.Equal(t, deployment.GUID, d.GUID)
+			verified = append(verified, step)
+			return nil
+		},
+	})
+	pushed, err := pusher.Push(context.Background(), canaryManifest(app.Name), strings.NewReader("blah zip zip"))
+	require.NoError(t, err)
+	require.Equal(t, app.GUID, pushed.GUID)
+	require.Equal(t, []int{1, 2}, verified)
+}
+
+func TestAppPushCanaryVerifyFailed(t *testing.T) {
+	serverURL := testutil.SetupFakeAPIServer()
+	defer testutil.Teardown()
+
+	g := testutil.NewObjectJSONGenerator(8723)
+	app := g.Application()
+	droplet := g.Droplet()
+	deployment := g.DeploymentWithStatus("ACTIVE", "PAUSED")
+	canceled := sameDeployment(deployment, g.DeploymentWithStatus("FINALIZED", "CANCELED"))
+	routes := append(canaryStagingRoutes(g, serverURL, app, droplet),
+		testutil.MockRoute{
+			Method:   http.MethodPost,
+			Endpoint: "/v3/deployments",
+			Output:   g.Single(deployment.JSON),
+			Status:   http.StatusCreated,
+		},
+		testutil.MockRoute{
+			Method:   http.MethodGet,
+			Endpoint: fmt.Sprintf("/v3/deployments/%s", deployment.GUID),
+			Output:   []string{deployment.JSON, canceled},
+			Status:   http.StatusOK,
+		},
+		testutil.MockRoute{
+			Method:   http.MethodPost,
+			Endpoint: fmt.Sprintf("/v3/deployments/%s/actions/cancel", deployment.GUID),
+			Status:   http.StatusOK,
+		},
+	)
+	testutil.SetupMultiple(routes, t)
+
+	unhealthy := errors.New("error rate too high")
+	pusher := newCanaryPusher(t, serverURL, &CanaryOptions{
+		Verify: func(ctx context.Context, d *resource.Deployment, step int) error {
+			return unhealthy
+		},
+	})
+	_, err := pusher.Push(context.Background(), canaryManifest(app.Name), strings.NewReader("blah zip zip"))
+	require.ErrorIs(t, err, unhealthy)
+	require.ErrorContains(t, err, "canary step 1 verification failed")
+	require.ErrorContains(t, err, "deployment cancelled")
+}
+
+func TestAppPushCanaryContinueFailed(t *testing.T) {
+	serverURL := testutil.SetupFakeAPIServer()
+	defer testutil.Teardown()
+
+	g := testutil.NewObjectJSONGenerator(8723)
+	app := g.Application()
+	droplet := g.Droplet()
+	deployment := g.DeploymentWithStatus("ACTIVE", "PAUSED")
+	canceled := sameDeployment(deployment, g.DeploymentWithStatus("FINALIZED", "CANCELED"))
+	routes := append(canaryStagingRoutes(g, serverURL, app, droplet),
+		testutil.MockRoute{
+			Method:   http.MethodPost,
+			Endpoint: "/v3/deployments",
+			Output:   g.Single(deployment.JSON),
+			Status:   http.StatusCreated,
+		},
+		testutil.MockRoute{
+			Method:   http.MethodGet,
+			Endpoint: fmt.Sprintf("/v3/deployments/%s", deployment.GUID),
+			Output:   []string{deployment.JSON, canceled},
+			Status:   http.StatusOK,
+		},
+		testutil.MockRoute{
+			Method:   http.MethodPost,
+			Endpoint: fmt.Sprintf("/v3/deployments/%s/actions/continue", deployment.GUID),
+			Status:   http.StatusInternalServerError,
+		},
+		testutil.MockRoute{
+			Method:   http.MethodPost,
+			Endpoint: fmt.Sprintf("/v3/deployments/%s/actions/cancel", deployment.GUID),
+			Status:   http.StatusOK,
+		},
+	)
+	testutil.SetupMultiple(routes, t)
+
+	pusher := newCanaryPusher(t, serverURL, nil)
+	_, err := pusher.Push(context.Background(), canaryManifest(app.Name), strings.NewReader("blah zip zip"))
+	require.ErrorContains(t, err, "failed to continue canary deployment past step 1")
+	require.ErrorContains(t, err, "deployment cancelled")
+}
+
+func TestAppPushCanaryContextCancelled(t *testing.T) {
+	serverURL := testutil.SetupFakeAPIServer()
+	defer testutil.Teardown()
+
+	g := testutil.NewObjectJSONGenerator(8723)
+	app := g.Application()
+	droplet := g.Droplet()
+	deployment := g.DeploymentWithStatus("ACTIVE", "PAUSED")
+	canceled := sameDeployment(deployment, g.DeploymentWithStatus("FINALIZED", "CANCELED"))
+	routes := append(canaryStagingRoutes(g, serverURL, app, droplet),
+		testutil.MockRoute{
+			Method:   http.MethodPost,
+			Endpoint: "/v3/deployments",
+			Output:   g.Single(deployment.JSON),
+			Status:   http.StatusCreated,
+		},
+		testutil.MockRoute{
+			Method:   http.MethodGet,
+			Endpoint: fmt.Sprintf("/v3/deployments/%s", deployment.GUID),
+			Output:   []string{deployment.JSON, canceled},
+			Status:   http.StatusOK,
+		},
+		testutil.MockRoute{
+			Method:   http.MethodPost,
+			Endpoint: fmt.Sprintf("/v3/deployments/%s/actions/cancel", deployment.GUID),
+			Status:   http.StatusOK,
+		},
+	)
+	testutil.SetupMultiple(routes, t)
+
+	// the caller gives up while the paused step is being verified
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	pusher := newCanaryPusher(t, serverURL, &CanaryOptions{
+		Verify: func(ctx context.Context, d *resource.Deployment, step int) error {
+			cancel()
+			return ctx.Err()
+		},
+	})
+	_, err := pusher.Push(ctx, canaryManifest(app.Name), strings.NewReader("blah zip zip"))
+	require.ErrorIs(t, err, context.Canceled)
+	require.ErrorContains(t, err, "deployment cancelled")
+}
+
+func newCanaryPusher(t *testing.T, serverURL string, opts *CanaryOptions) *AppPushOperation {
+	c, _ := config.New(serverURL, config.Token("", "fake-refresh-token"), config.SkipTLSValidation())
+	cf, err := client.New(c)
+	require.NoError(t, err)
+
+	pusher := NewAppPushOperation(cf, "my-org", "my-space")
+	pusher.WithStrategy(StrategyCanary)
+	pusher.WithCanaryOptions(opts)
+	return pusher
+}
+
+func canaryManifest(appName string) *AppManifest {
+	var instances uint = 4
+	manifest := NewAppManifest(appName)
+	manifest.Instances = &instances
+	return manifest
+}
+
+// sameDeployment gives the other deployment JSON the deployment's GUID
+func sameDeployment(deployment, other *testutil.JSONResource) string {
+	return strings.ReplaceAll(other.JSON, other.GUID, deployment.GUID)
+}
+
+// canaryStagingRoutes are the routes needed to stage a new droplet for a running app
+func canaryStagingRoutes(g *testutil.ObjectJSONGenerator, serverURL string, app, droplet *testutil.JSONResource) []testutil.MockRoute {
+	org := g.Organization()
+	space := g.Space()
+	job := g.Job("COMPLETE")
+	pkg := g.Package("READY")
+	build := g.Build("STAGED")
+	return []testutil.MockRoute{
+		{
+			Method:   http.MethodGet,
+			Endpoint: "/v3/organizations",
+			Output:   g.SinglePaged(org.JSON),
+			Status:   http.StatusOK,
+		},
+		{
+			Method:   http.MethodGet,
+			Endpoint: "/v3/spaces",
+			Output:   g.SinglePaged(space.JSON),
+			Status:   http.StatusOK,
+		},
+		{
+			Method:   http.MethodGet,
+			Endpoint: "/v3/apps",
+			Output:   g.SinglePaged(app.JSON),
+			Status:   http.StatusOK,
+		},
+		{
+			Method:           http.MethodPost,
+			Endpoint:         fmt.Sprintf("/v3/spaces/%s/actions/apply_manifest", space.GUID),
+			Status:           http.StatusAccepted,
+			RedirectLocation: fmt.Sprintf("%s/v3/jobs/%s", serverURL, job.GUID),
+		},
+		{
+			Method:   http.MethodGet,
+			Endpoint: fmt.Sprintf("/v3/jobs/%s", job.GUID),
+			Output:   g.Single(job.JSON),
+			Status:   http.StatusOK,
+		},
+		{
+			Method:   http.MethodPost,
+			Endpoint: "/v3/packages",
+			Output:   g.Single(pkg.JSON),
+			Status:   http.StatusCreated,
+		},
+		{
+			Method:   http.MethodPost,
+			Endpoint: fmt.Sprintf("/v3/packages/%s/upload", pkg.GUID),
+			Output:   g.Single(pkg.JSON),
+			Status:   http.StatusOK,
+		},
+		{
+			Method:   http.MethodGet,
+			Endpoint: fmt.Sprintf("/v3/packages/%s", pkg.GUID),
+			Output:   g.Single(pkg.JSON),
+			Status:   http.StatusOK,
+		},
+		{
+			Method:   http.MethodPost,
+			Endpoint: "/v3/builds",
+			Output:   g.Single(build.JSON),
+			Status:   http.StatusCreated,
+		},
+		{
+			Method:   http.MethodGet,
+			Endpoint: fmt.Sprintf("/v3/builds/%s", build.GUID),
+			Output:   g.Single(build.JSON),
+			Status:   http.StatusOK,
+		},
+		{
+			Method:   http.MethodGet,
+			Endpoint: fmt.Sprintf("/v3/packages/%s/droplets", pkg.GUID),
+			Output:   g.SinglePaged(droplet.JSON),
+			Status:   http.StatusOK,
+		},
+	}
+}
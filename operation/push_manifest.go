@@ -0,0 +1,130 @@
+package operation
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"sync"
+
+	"github.com/cloudfoundry/go-cfclient/v3/resource"
+)
+
+// DefaultPushConcurrency is the default number of applications in a manifest pushed at the same time
+const DefaultPushConcurrency = 4
+
+// PushManifestOptions configures how a manifest file is interpolated and pushed
+type PushManifestOptions struct {
+	VarsFiles   []string       // files of variables to interpolate, later files take precedence
+	Vars        map[string]any // variables to interpolate, these take precedence over the vars files
+	Concurrency int            // the maximum number of applications to push at the same time
+}
+
+// NewPushManifestOptions creates new options to push a manifest
+func NewPushManifestOptions() *PushManifestOptions {
+	return &PushManifestOptions{
+		Concurrency: DefaultPushConcurrency,
+	}
+}
+
+// AppPushResult is the outcome of pushing a single application from a manifest
+type AppPushResult struct {
+	Name string        // the name of the application in the manifest
+	App  *resource.App // the pushed application or nil if the push failed
+	Err  error         // any error pushing the application
+}
+
+// PushManifest reads the manifest file, interpolating any ((var)) placeholders, and pushes every application
+// in it to the space using the push strategy. Applications are pushed concurrently up to the configured
+// concurrency.
+//
+// Any staging logs are written a line at a time with each line prefixed by the application's name.
+//
+// An error is only returned if the manifest can't be loaded or the org or space can't be found. Otherwise
+// there's a result for each application, in manifest order, containing any error pushing that application
+func (p *AppPushOperation) PushManifest(ctx context.Context, manifestPath string, opts *PushManifestOptions) ([]*AppPushResult, error) {
+	if opts == nil {
+		opts = NewPushManifestOptions()
+	}
+	manifest, err := LoadManifest(manifestPath, opts.VarsFiles, opts.Vars)
+	if err != nil {
+		return nil, err
+	}
+	org, err := p.findOrg(ctx)
+	if err != nil {
+		return nil, err
+	}
+	space, err := p.findSpace(ctx, org.GUID)
+	if err != nil {
+		return nil, err
+	}
+
+	concurrency := opts.Concurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	// each application gets its own copy of the operation so the staging logs of applications pushed at the
+	// same time can be told apart and don't interleave
+	pushers := make([]*AppPushOperation, len(manifest.Applications))
+	var stagingLogsMu sync.Mutex
+	for i, appManifest := range manifest.Applications {
+		pusher := *p
+		if p.stagingLogs != nil {
+			pusher.stagingLogs = &prefixWriter{
+				mu:     &stagingLogsMu,
+				w:      p.stagingLogs,
+				prefix: []byte("[" + appManifest.Name + "] "),
+			}
+		}
+		pushers[i] = &pusher
+	}
+
+	sem := make(chan struct{}, concurrency)
+	results := make([]*AppPushResult, len(manifest.Applications))
+	var wg sync.WaitGroup
+	for i, appManifest := range manifest.Applications {
+		results[i] = &AppPushResult{Name: appManifest.Name}
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			results[i].Err = ctx.Err()
+			continue
+		}
+		wg.Add(1)
+		go func(pusher *AppPushOperation, result *AppPushResult, appManifest *AppManifest) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			result.App, result.Err = pusher.pushWithStrategyApp(ctx, space, appManifest, pathBits{path: appManifest.Path})
+		}(pushers[i], results[i], appManifest)
+	}
+	wg.Wait()
+	return results, nil
+}
+
+// prefixWriter prefixes each line written to w, holding a lock shared by all the writers to w
+type prefixWriter struct {
+	mu      *sync.Mutex
+	w       io.Writer
+	prefix  []byte
+	midLine bool // the last write didn't end with a newline
+}
+
+func (pw *prefixWriter) Write(b []byte) (int, error) {
+	pw.mu.Lock()
+	defer pw.mu.Unlock()
+
+	var out []byte
+	for _, line := range bytes.SplitAfter(b, []byte("\n")) {
+		if len(line) == 0 {
+			continue
+		}
+		if !pw.midLine {
+			out = append(out, pw.prefix...)
+		}
+		out = append(out, line...)
+		pw.midLine = line[len(line)-1] != '\n'
+	}
+	if _, err := pw.w.Write(out); err != nil {
+		return 0, err
+	}
+	return len(b), nil
+}
@@ -1,11 +1,16 @@
 package operation
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/cloudfoundry/go-cfclient/v3/client"
 	"github.com/cloudfoundry/go-cfclient/v3/config"
@@ -142,3 +147,328 @@ func TestAppPush(t *testing.T) {
 	_, err = pusher.Push(context.Background(), manifest, fakeAppZipReader)
 	require.NoError(t, err)
 }
+
+func TestPushManifest(t *testing.T) {
+	serverURL := testutil.SetupFakeAPIServer()
+	defer testutil.Teardown()
+
+	g := testutil.NewObjectJSONGenerator(8723)
+	org := g.Organization()
+	space := g.Space()
+	job := g.Job("COMPLETE")
+	app := g.Application()
+	pkg := g.Package("READY")
+	build := g.Build("STAGED")
+	droplet := g.Droplet()
+	dropletAssoc := g.DropletAssociation()
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "index.html"), []byte("hello"), 0644))
+	manifestPath := filepath.Join(dir, "manifest.yml")
+	require.NoError(t, os.WriteFile(manifestPath, []byte(`applications:
+- name: ((prefix))-web
+  instances: 1
+- name: ((prefix))-worker
+  instances: 1
+`), 0644))
+
+	twice := func(s string) []string {
+		return []string{s, s}
+	}
+	testutil.SetupMultiple([]testutil.MockRoute{
+		{
+			Method:   http.MethodGet,
+			Endpoint: "/v3/organizations",
+			Output:   g.SinglePaged(org.JSON),
+			Status:   http.StatusOK,
+		},
+		{
+			Method:   http.MethodGet,
+			Endpoint: "/v3/spaces",
+			Output:   g.SinglePaged(space.JSON),
+			Status:   http.StatusOK,
+		},
+		{
+			Method:           http.MethodPost,
+			Endpoint:         fmt.Sprintf("/v3/spaces/%s/actions/apply_manifest", space.GUID),
+			Output:           twice(""),
+			Status:           http.StatusAccepted,
+			RedirectLocation: fmt.Sprintf("%s/v3/jobs/%s", serverURL, job.GUID),
+		},
+		{
+			Method:   http.MethodGet,
+			Endpoint: fmt.Sprintf("/v3/jobs/%s", job.GUID),
+			Output:   twice(job.JSON),
+			Status:   http.StatusOK,
+		},
+		{
+			Method:   http.MethodGet,
+			Endpoint: "/v3/apps",
+			Output:   append(g.SinglePaged(app.JSON), g.SinglePaged(app.JSON)...),
+			Status:   http.StatusOK,
+		},
+		{
+			Method:   http.MethodPost,
+			Endpoint: "/v3/packages",
+			Output:   twice(pkg.JSON),
+			Status:   http.StatusCreated,
+		},
+		{
+			Method:   http.MethodPost,
+			Endpoint: "/v3/resource_matches",
+			Output:   twice(`{"resources":[]}`),
+			Status:   http.StatusCreated,
+		},
+		{
+			Method:   http.MethodPost,
+			Endpoint: fmt.Sprintf("/v3/packages/%s/upload", pkg.GUID),
+			Output:   twice(pkg.JSON),
+			Status:   http.StatusOK,
+		},
+		{
+			Method:   http.MethodGet,
+			Endpoint: fmt.Sprintf("/v3/packages/%s", pkg.GUID),
+			Output:   twice(pkg.JSON),
+			Status:   http.StatusOK,
+		},
+		{
+			Method:   http.MethodPost,
+			Endpoint: "/v3/builds",
+			Output:   twice(build.JSON),
+			Status:   http.StatusCreated,
+		},
+		{
+			Method:   http.MethodGet,
+			Endpoint: fmt.Sprintf("/v3/builds/%s", build.GUID),
+			Output:   twice(build.JSON),
+			Status:   http.StatusOK,
+		},
+		{
+			Method:   http.MethodGet,
+			Endpoint: fmt.Sprintf("/v3/packages/%s/droplets", pkg.GUID),
+			Output:   append(g.SinglePaged(droplet.JSON), g.SinglePaged(droplet.JSON)...),
+			Status:   http.StatusOK,
+		},
+		{
+			Method:   http.MethodPatch,
+			Endpoint: fmt.Sprintf("/v3/apps/%s/relationships/current_droplet", app.GUID),
+			Output:   twice(dropletAssoc.JSON),
+			Status:   http.StatusOK,
+		},
+		{
+			Method:   http.MethodPost,
+			Endpoint: fmt.Sprintf("/v3/apps/%s/actions/start", app.GUID),
+			Output:   []string{app.JSON, ""},
+			Statuses: []int{http.StatusOK, http.StatusInternalServerError},
+		},
+	}, t)
+
+	c, _ := config.New(serverURL, config.Token("", "fake-refresh-token"), config.SkipTLSValidation())
+	cf, err := client.New(c)
+	require.NoError(t, err)
+
+	pusher := NewAppPushOperation(cf, org.Name, space.Name)
+	opts := NewPushManifestOptions()
+	opts.Vars = map[string]any{"prefix": "shop"}
+	opts.Concurrency = 1
+	results, err := pusher.PushManifest(context.Background(), manifestPath, opts)
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	require.Equal(t, "shop-web", results[0].Name)
+	require.NoError(t, results[0].Err)
+	require.Equal(t, app.GUID, results[0].App.GUID)
+	require.Equal(t, "shop-worker", results[1].Name)
+	require.Error(t, results[1].Err)
+	require.Nil(t, results[1].App)
+
+	_, err = pusher.PushManifest(context.Background(), manifestPath, nil)
+	require.EqualError(t, err, fmt.Sprintf("error interpolating manifest %s: expected to find variables: prefix", manifestPath))
+}
+
+func TestPushManifestStagingLogs(t *testing.T) {
+	serverURL := testutil.SetupFakeAPIServer()
+	defer testutil.Teardown()
+
+	g := testutil.NewObjectJSONGenerator(8723)
+	org := g.Organization()
+	space := g.Space()
+	job := g.Job("COMPLETE")
+	app := g.Application()
+	pkg := g.Package("READY")
+	build := g.Build("STAGED")
+	droplet := g.Droplet()
+	dropletAssoc := g.DropletAssociation()
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "index.html"), []byte("hello"), 0644))
+	manifestPath := filepath.Join(dir, "manifest.yml")
+	require.NoError(t, os.WriteFile(manifestPath, []byte(`applications:
+- name: shop-web
+  instances: 1
+- name: shop-worker
+  instances: 1
+`), 0644))
+
+	// each app's staging log stream has a single line, "hello world"
+	var envelope bytes.Buffer
+	_ = json.Compact(&envelope, []byte(strings.Replace(g.LogEnvelope(app.GUID, "1581447006352020890").JSON, "APP/PROC/WEB", "STG", 1)))
+	stream := `data: {"batch":[` + envelope.String() + "]}\n\n"
+
+	twice := func(s string) []string {
+		return []string{s, s}
+	}
+	testutil.SetupMultiple([]testutil.MockRoute{
+		{
+			Method:   http.MethodGet,
+			Endpoint: "/v3/organizations",
+			Output:   g.SinglePaged(org.JSON),
+			Status:   http.StatusOK,
+		},
+		{
+			Method:   http.MethodGet,
+			Endpoint: "/v3/spaces",
+			Output:   g.SinglePaged(space.JSON),
+			Status:   http.StatusOK,
+		},
+		{
+			Method:           http.MethodPost,
+			Endpoint:         fmt.Sprintf("/v3/spaces/%s/actions/apply_manifest", space.GUID),
+			Output:           twice(""),
+			Status:           http.StatusAccepted,
+			RedirectLocation: fmt.Sprintf("%s/v3/jobs/%s", serverURL, job.GUID),
+		},
+		{
+			Method:   http.MethodGet,
+			Endpoint: fmt.Sprintf("/v3/jobs/%s", job.GUID),
+			Output:   twice(job.JSON),
+			Status:   http.StatusOK,
+		},
+		{
+			Method:   http.MethodGet,
+			Endpoint: "/v3/apps",
+			Output:   append(g.SinglePaged(app.JSON), g.SinglePaged(app.JSON)...),
+			Status:   http.StatusOK,
+		},
+		{
+			Method:   http.MethodPost,
+			Endpoint: "/v3/packages",
+			Output:   twice(pkg.JSON),
+			Status:   http.StatusCreated,
+		},
+		{
+			Method:   http.MethodPost,
+			Endpoint: "/v3/resource_matches",
+			Output:   twice(`{"resources":[]}`),
+			Status:   http.StatusCreated,
+		},
+		{
+			Method:   http.MethodPost,
+			Endpoint: fmt.Sprintf("/v3/packages/%s/upload", pkg.GUID),
+			Output:   twice(pkg.JSON),
+			Status:   http.StatusOK,
+		},
+		{
+			Method:   http.MethodGet,
+			Endpoint: fmt.Sprintf("/v3/packages/%s", pkg.GUID),
+			Output:   twice(pkg.JSON),
+			Status:   http.StatusOK,
+		},
+		{
+			Method:   http.MethodGet,
+			Endpoint: "/v2/read",
+			Output:   twice(stream),
+			Status:   http.StatusOK,
+		},
+		{
+			Method:   http.MethodPost,
+			Endpoint: "/v3/builds",
+			Output:   twice(build.JSON),
+			Status:   http.StatusCreated,
+		},
+		{
+			Method:   http.MethodGet,
+			Endpoint: fmt.Sprintf("/v3/builds/%s", build.GUID),
+			Output:   twice(build.JSON),
+			Status:   http.StatusOK,
+		},
+		{
+			Method:   http.MethodGet,
+			Endpoint: fmt.Sprintf("/v3/packages/%s/droplets", pkg.GUID),
+			Output:   append(g.SinglePaged(droplet.JSON), g.SinglePaged(droplet.JSON)...),
+			Status:   http.StatusOK,
+		},
+		{
+			Method:   http.MethodPatch,
+			Endpoint: fmt.Sprintf("/v3/apps/%s/relationships/current_droplet", app.GUID),
+			Output:   twice(dropletAssoc.JSON),
+			Status:   http.StatusOK,
+		},
+		{
+			Method:   http.MethodPost,
+			Endpoint: fmt.Sprintf("/v3/apps/%s/actions/start", app.GUID),
+			Output:   twice(app.JSON),
+			Status:   http.StatusOK,
+		},
+	}, t)
+
+	// hold both builds until both apps have written their staging logs, so the writes happen at the same time
+	logs := &signalWriter{wrote: make(chan struct{}, 2)}
+	bothWritten := make(chan struct{})
+	go func() {
+		for i := 0; i < 2; i++ {
+			<-logs.wrote
+		}
+		close(bothWritten)
+	}()
+	buildPath := fmt.Sprintf("/v3/builds/%s", build.GUID)
+	c, _ := config.New(serverURL, config.Token("", "fake-refresh-token"), config.SkipTLSValidation(),
+		config.HttpClient(&http.Client{Transport: requestHook(func(req *http.Request) {
+			if req.Method == http.MethodGet && req.URL.Path == buildPath {
+				select {
+				case <-bothWritten:
+				case <-time.After(5 * time.Second):
+				}
+			}
+		})}))
+	cf, err := client.New(c)
+	require.NoError(t, err)
+
+	pusher := NewAppPushOperation(cf, org.Name, space.Name)
+	pusher.WithStagingLogs(logs)
+	opts := NewPushManifestOptions()
+	opts.Concurrency = 2
+	results, err := pusher.PushManifest(context.Background(), manifestPath, opts)
+	require.NoError(t, err)
+	for _, result := range results {
+		require.NoError(t, result.Err)
+	}
+	require.ElementsMatch(t, []string{
+		"[shop-web] hello world",
+		"[shop-worker] hello world",
+	}, strings.Split(strings.TrimSuffix(logs.buf.String(), "\n"), "\n"))
+}
+
+// signalWriter writes to a buffer that isn't safe for concurrent use, so the race detector catches
+// concurrent writes, and signals after each write
+type signalWriter struct {
+	buf   bytes.Buffer
+	wrote chan struct{}
+}
+
+func (w *signalWriter) Write(b []byte) (int, error) {
+	n, err := w.buf.Write(b)
+	w.wrote <- struct{}{}
+	return n, err
+}
+
+func TestDeploymentPollingOptions(t *testing.T) {
+	require.Equal(t, time.Minute, deploymentPollingOptions(0, 0).Timeout)
+	require.Equal(t, 4*time.Minute, deploymentPollingOptions(4, 0).Timeout)
+	require.Equal(t, 2*time.Minute, deploymentPollingOptions(4, 2).Timeout)
+	require.Equal(t, 2*time.Minute, deploymentPollingOptions(5, 3).Timeout)
+
+	p := &AppPushOperation{}
+	p.WithRollingOptions(&RollingOptions{MaxInFlight: 3})
+	require.Equal(t, 2*time.Minute, p.rollingPollingOptions(canaryManifest("app")).Timeout)
+	require.Equal(t, 3, p.rollingDeploymentOptions().MaxInFlight)
+}
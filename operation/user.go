@@ -0,0 +1,150 @@
+package operation
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/cloudfoundry/go-cfclient/v3/client"
+	"github.com/cloudfoundry/go-cfclient/v3/resource"
+)
+
+// UserOnboardOperation creates a UAA user along with the matching CF user and roles
+type UserOnboardOperation struct {
+	client     *client.Client
+	groups     []string
+	orgRoles   []orgRole
+	spaceRoles []spaceRole
+}
+
+type orgRole struct {
+	orgGUID  string
+	roleType resource.OrganizationRoleType
+}
+
+type spaceRole struct {
+	spaceGUID string
+	roleType  resource.SpaceRoleType
+}
+
+// NewUserOnboardOperation creates a new UserOnboardOperation
+func NewUserOnboardOperation(client *client.Client) *UserOnboardOperation {
+	return &UserOnboardOperation{
+		client: client,
+	}
+}
+
+// WithGroup adds the new user to the named UAA group, granting the user the group's scope
+func (o *UserOnboardOperation) WithGroup(name string) *UserOnboardOperation {
+	o.groups = append(o.groups, name)
+	return o
+}
+
+// WithOrganizationRole grants the new user the role in the specified org
+func (o *UserOnboardOperation) WithOrganizationRole(orgGUID string, roleType resource.OrganizationRoleType) *UserOnboardOperation {
+	o.orgRoles = append(o.orgRoles, orgRole{orgGUID: orgGUID, roleType: roleType})
+	return o
+}
+
+// WithSpaceRole grants the new user the role in the specified space
+//
+// The CF API requires the user to have a role in the space's org, so also use WithOrganizationRole
+// unless the user is already an org member.
+func (o *UserOnboardOperation) WithSpaceRole(spaceGUID string, roleType resource.SpaceRoleType) *UserOnboardOperation {
+	o.spaceRoles = append(o.spaceRoles, spaceRole{spaceGUID: spaceGUID, roleType: roleType})
+	return o
+}
+
+// Onboard creates the UAA user, adds it to any groups, creates the CF user with the same GUID and
+// then grants the org and space roles
+//
+// If any step fails everything created so far is deleted again in reverse order, even when ctx was
+// cancelled or timed out. The returned error includes any failure to roll back.
+func (o *UserOnboardOperation) Onboard(ctx context.Context, r *resource.UAAUserCreate) (*resource.User, error) {
+	uaaUser, err := o.client.UAA.CreateUser(ctx, r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create UAA user %s: %w", r.UserName, err)
+	}
+	var rollbacks []func(context.Context) error
+	rollbacks = append(rollbacks, func(ctx context.Context) error {
+		return o.client.UAA.DeleteUser(ctx, uaaUser.ID)
+	})
+
+	user, err := o.onboard(ctx, uaaUser, &rollbacks)
+	if err != nil {
+		return nil, o.rollback(ctx, err, rollbacks)
+	}
+	return user, nil
+}
+
+func (o *UserOnboardOperation) onboard(ctx context.Context, uaaUser *resource.UAAUser, rollbacks *[]func(context.Context) error) (*resource.User, error) {
+	for _, name := range o.groups {
+		group, err := o.client.UAA.GetGroupByName(ctx, name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find UAA group %s: %w", name, err)
+		}
+		_, err = o.client.UAA.AddGroupMember(ctx, group.ID, resource.NewUAAGroupUserMember(uaaUser.ID, uaaUser.Origin))
+		if err != nil {
+			return nil, fmt.Errorf("failed to add user %s to UAA group %s: %w", uaaUser.UserName, name, err)
+		}
+		groupID := group.ID
+		*rollbacks = append(*rollbacks, func(ctx context.Context) error {
+			return o.client.UAA.RemoveGroupMember(ctx, groupID, uaaUser.ID)
+		})
+	}
+
+	user, err := o.client.Users.Create(ctx, &resource.UserCreate{GUID: uaaUser.ID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create user %s: %w", uaaUser.UserName, err)
+	}
+	*rollbacks = append(*rollbacks, func(ctx context.Context) error {
+		return o.deleteAndPoll(ctx, o.client.Users.Delete, user.GUID)
+	})
+
+	for _, or := range o.orgRoles {
+		role, err := o.client.Roles.CreateOrganizationRole(ctx, or.orgGUID, user.GUID, or.roleType)
+		if err != nil {
+			return nil, fmt.Errorf("failed to grant %s in org %s: %w", or.roleType, or.orgGUID, err)
+		}
+		*rollbacks = append(*rollbacks, o.deleteRole(role.GUID))
+	}
+	for _, sr := range o.spaceRoles {
+		role, err := o.client.Roles.CreateSpaceRole(ctx, sr.spaceGUID, user.GUID, sr.roleType)
+		if err != nil {
+			return nil, fmt.Errorf("failed to grant %s in space %s: %w", sr.roleType, sr.spaceGUID, err)
+		}
+		*rollbacks = append(*rollbacks, o.deleteRole(role.GUID))
+	}
+	return user, nil
+}
+
+// rollback runs the rollback steps newest first, continuing past failures so as much as possible is
+// cleaned up
+func (o *UserOnboardOperation) rollback(ctx context.Context, cause error, rollbacks []func(context.Context) error) error {
+	ctx, cancel := cleanupContext(ctx, 0)
+	defer cancel()
+	var failures []string
+	for i := len(rollbacks) - 1; i >= 0; i-- {
+		if err := rollbacks[i](ctx); err != nil {
+			failures = append(failures, err.Error())
+		}
+	}
+	if len(failures) > 0 {
+		return fmt.Errorf("%w\nfailed to roll back user onboarding: %s", cause, strings.Join(failures, "; "))
+	}
+	return fmt.Errorf("%w\nrolled back user onboarding", cause)
+}
+
+func (o *UserOnboardOperation) deleteRole(guid string) func(context.Context) error {
+	return func(ctx context.Context) error {
+		return o.deleteAndPoll(ctx, o.client.Roles.Delete, guid)
+	}
+}
+
+func (o *UserOnboardOperation) deleteAndPoll(ctx context.Context, del func(context.Context, string) (string, error), guid string) error {
+	jobGUID, err := del(ctx, guid)
+	if err != nil {
+		return err
+	}
+	return o.client.Jobs.PollComplete(ctx, jobGUID, client.NewPollingOptions())
+}
@@ -0,0 +1,128 @@
+package operation
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/cloudfoundry/go-cfclient/v3/client"
+	"github.com/cloudfoundry/go-cfclient/v3/config"
+	"github.com/cloudfoundry/go-cfclient/v3/resource"
+	"github.com/cloudfoundry/go-cfclient/v3/testutil"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserOnboard(t *testing.T) {
+	g := testutil.NewObjectJSONGenerator(4291)
+	uaaUser := g.UAAUser()
+	group := g.UAAGroup(uaaUser.GUID)
+	user := g.User()
+	orgRole := g.Role()
+	spaceRole := g.Role()
+	job := g.Job("COMPLETE")
+	orgGUID := testutil.RandomGUID()
+	spaceGUID := testutil.RandomGUID()
+
+	setup := func(roleStatuses []int, extra ...testutil.MockRoute) *client.Client {
+		serverURL := testutil.SetupFakeAPIServer()
+		routes := []testutil.MockRoute{
+			{
+				Method:   http.MethodPost,
+				Endpoint: "/Users",
+				Output:   []string{uaaUser.JSON},
+				Status:   http.StatusCreated,
+			},
+			{
+				Method:   http.MethodGet,
+				Endpoint: "/Groups",
+				Output:   []string{g.UAAList(1, 1, group.JSON)},
+				Status:   http.StatusOK,
+			},
+			{
+				Method:   http.MethodPost,
+				Endpoint: fmt.Sprintf("/Groups/%s/members", group.GUID),
+				Output:   []string{`{"origin":"uaa","type":"USER","value":"` + uaaUser.GUID + `"}`},
+				Status:   http.StatusCreated,
+			},
+			{
+				Method:   http.MethodPost,
+				Endpoint: "/v3/users",
+				Output:   g.Single(user.JSON),
+				Status:   http.StatusCreated,
+			},
+			{
+				Method:   http.MethodPost,
+				Endpoint: "/v3/roles",
+				Output: []string{
+					orgRole.JSON,
+					spaceRole.JSON,
+				},
+				Statuses: roleStatuses,
+			},
+		}
+		testutil.SetupMultiple(append(routes, extra...), t)
+		c, _ := config.New(serverURL, config.Token("", "fake-refresh-token"), config.SkipTLSValidation())
+		cf, err := client.New(c)
+		require.NoError(t, err)
+		return cf
+	}
+
+	t.Run("onboard", func(t *testing.T) {
+		cf := setup([]int{http.StatusCreated, http.StatusCreated})
+		defer testutil.Teardown()
+
+		u, err := NewUserOnboardOperation(cf).
+			WithGroup(group.Name).
+			WithOrganizationRole(orgGUID, resource.OrganizationRoleUser).
+			WithSpaceRole(spaceGUID, resource.SpaceRoleDeveloper).
+			Onboard(context.Background(), resource.NewUAAUserCreate("jdoe", "secret", "jdoe@example.org"))
+		require.NoError(t, err)
+		require.Equal(t, user.GUID, u.GUID)
+	})
+
+	t.Run("rollback", func(t *testing.T) {
+		serverURL := "https://api.example.org"
+		cf := setup([]int{http.StatusCreated, http.StatusUnprocessableEntity},
+			testutil.MockRoute{
+				Method:           http.MethodDelete,
+				Endpoint:         fmt.Sprintf("/v3/roles/%s", orgRole.GUID),
+				Status:           http.StatusAccepted,
+				RedirectLocation: fmt.Sprintf("%s/v3/jobs/%s", serverURL, job.GUID),
+			},
+			testutil.MockRoute{
+				Method:           http.MethodDelete,
+				Endpoint:         fmt.Sprintf("/v3/users/%s", user.GUID),
+				Status:           http.StatusAccepted,
+				RedirectLocation: fmt.Sprintf("%s/v3/jobs/%s", serverURL, job.GUID),
+			},
+			testutil.MockRoute{
+				Method:   http.MethodGet,
+				Endpoint: fmt.Sprintf("/v3/jobs/%s", job.GUID),
+				Output:   []string{job.JSON, job.JSON},
+				Status:   http.StatusOK,
+			},
+			testutil.MockRoute{
+				Method:   http.MethodDelete,
+				Endpoint: fmt.Sprintf("/Groups/%s/members/%s", group.GUID, uaaUser.GUID),
+				Status:   http.StatusOK,
+			},
+			testutil.MockRoute{
+				Method:   http.MethodDelete,
+				Endpoint: fmt.Sprintf("/Users/%s", uaaUser.GUID),
+				Status:   http.StatusOK,
+			},
+		)
+		defer testutil.Teardown()
+
+		_, err := NewUserOnboardOperation(cf).
+			WithGroup(group.Name).
+			WithOrganizationRole(orgGUID, resource.OrganizationRoleUser).
+			WithSpaceRole(spaceGUID, resource.SpaceRoleDeveloper).
+			Onboard(context.Background(), resource.NewUAAUserCreate("jdoe", "secret", "jdoe@example.org"))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to grant")
+		require.Contains(t, err.Error(), "rolled back user onboarding")
+	})
+}
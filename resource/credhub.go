@@ -0,0 +1,136 @@
+package resource
+
+import (
+	"time"
+)
+
+// CredHubRefKey is the key of the placeholder a service broker returns in place of binding credentials
+// stored in CredHub, e.g. {"credhub-ref": "/c/p-broker/instance/binding/credentials"}
+const CredHubRefKey = "credhub-ref"
+
+type CredHubCredentialType string
+
+const (
+	CredHubCredentialTypeValue       CredHubCredentialType = "value"
+	CredHubCredentialTypeJSON        CredHubCredentialType = "json"
+	CredHubCredentialTypePassword    CredHubCredentialType = "password"
+	CredHubCredentialTypeUser        CredHubCredentialType = "user"
+	CredHubCredentialTypeCertificate CredHubCredentialType = "certificate"
+	CredHubCredentialTypeRSA         CredHubCredentialType = "rsa"
+	CredHubCredentialTypeSSH         CredHubCredentialType = "ssh"
+)
+
+type CredHubMode string
+
+const (
+	CredHubModeOverwrite   CredHubMode = "overwrite"
+	CredHubModeNoOverwrite CredHubMode = "no-overwrite"
+	CredHubModeConverge    CredHubMode = "converge"
+)
+
+type CredHubPermissionOperation string
+
+const (
+	CredHubPermissionRead     CredHubPermissionOperation = "read"
+	CredHubPermissionWrite    CredHubPermissionOperation = "write"
+	CredHubPermissionDelete   CredHubPermissionOperation = "delete"
+	CredHubPermissionReadACL  CredHubPermissionOperation = "read_acl"
+	CredHubPermissionWriteACL CredHubPermissionOperation = "write_acl"
+)
+
+// CredHubCredential is a single version of a credential stored in CredHub
+type CredHubCredential struct {
+	ID               string                `json:"id"`
+	Name             string                `json:"name"`
+	Type             CredHubCredentialType `json:"type"`
+	Value            any                   `json:"value"` // a string for value and password types, otherwise an object
+	Metadata         map[string]any        `json:"metadata,omitempty"`
+	VersionCreatedAt time.Time             `json:"version_created_at"`
+}
+
+type CredHubCredentialList struct {
+	Data []*CredHubCredential `json:"data"`
+}
+
+type CredHubCredentialSet struct {
+	Name     string                `json:"name"`
+	Type     CredHubCredentialType `json:"type"`
+	Value    any                   `json:"value"`
+	Metadata map[string]any        `json:"metadata,omitempty"`
+}
+
+type CredHubCredentialGenerate struct {
+	Name       string                `json:"name"`
+	Type       CredHubCredentialType `json:"type"`
+	Parameters any                   `json:"parameters,omitempty"` // e.g. CredHubPasswordParameters
+	Mode       CredHubMode           `json:"mode,omitempty"`
+	Metadata   map[string]any        `json:"metadata,omitempty"`
+}
+
+type CredHubPasswordParameters struct {
+	Length         int  `json:"length,omitempty"`
+	ExcludeUpper   bool `json:"exclude_upper,omitempty"`
+	ExcludeLower   bool `json:"exclude_lower,omitempty"`
+	ExcludeNumber  bool `json:"exclude_number,omitempty"`
+	IncludeSpecial bool `json:"include_special,omitempty"`
+}
+
+// CredHubFoundCredential is the name of the latest version of a credential found by path
+type CredHubFoundCredential struct {
+	Name             string    `json:"name"`
+	VersionCreatedAt time.Time `json:"version_created_at"`
+}
+
+type CredHubFindResults struct {
+	Credentials []*CredHubFoundCredential `json:"credentials"`
+}
+
+// CredHubPermission grants an actor, e.g. mtls-app:<app guid>, operations on credentials matching the path
+type CredHubPermission struct {
+	UUID       string                       `json:"uuid"`
+	Actor      string                       `json:"actor"`
+	Path       string                       `json:"path"`
+	Operations []CredHubPermissionOperation `json:"operations"`
+}
+
+type CredHubPermissionCreate struct {
+	Actor      string                       `json:"actor"`
+	Path       string                       `json:"path"`
+	Operations []CredHubPermissionOperation `json:"operations"`
+}
+
+func NewCredHubCredentialSet(name string, credType CredHubCredentialType, value any) *CredHubCredentialSet {
+	return &CredHubCredentialSet{
+		Name:  name,
+		Type:  credType,
+		Value: value,
+	}
+}
+
+func NewCredHubPasswordGenerate(name string, length int) *CredHubCredentialGenerate {
+	return &CredHubCredentialGenerate{
+		Name: name,
+		Type: CredHubCredentialTypePassword,
+		Parameters: &CredHubPasswordParameters{
+			Length: length,
+		},
+	}
+}
+
+func NewCredHubPermissionCreate(actor, path string, operations ...CredHubPermissionOperation) *CredHubPermissionCreate {
+	return &CredHubPermissionCreate{
+		Actor:      actor,
+		Path:       path,
+		Operations: operations,
+	}
+}
+
+// CredHubRef returns the referenced credential name if the value is a credhub-ref placeholder
+func CredHubRef(v any) (string, bool) {
+	m, ok := v.(map[string]any)
+	if !ok || len(m) != 1 {
+		return "", false
+	}
+	ref, ok := m[CredHubRefKey].(string)
+	return ref, ok
+}
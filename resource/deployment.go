@@ -1,8 +1,31 @@
 package resource
 
+import "time"
+
+const (
+	DeploymentStrategyRolling = "rolling"
+	DeploymentStrategyCanary  = "canary"
+)
+
+const (
+	DeploymentStatusValueActive    = "ACTIVE"
+	DeploymentStatusValueFinalized = "FINALIZED"
+)
+
+const (
+	DeploymentStatusReasonDeploying  = "DEPLOYING"
+	DeploymentStatusReasonPaused     = "PAUSED"
+	DeploymentStatusReasonCanceling  = "CANCELING"
+	DeploymentStatusReasonDeployed   = "DEPLOYED"
+	DeploymentStatusReasonCanceled   = "CANCELED"
+	DeploymentStatusReasonSuperseded = "SUPERSEDED"
+	DeploymentStatusReasonDegenerate = "DEGENERATE"
+)
+
 type Deployment struct {
 	Status          DeploymentStatus   `json:"status"`
 	Strategy        string             `json:"strategy"`
+	Options         *DeploymentOptions `json:"options,omitempty"`
 	Droplet         Relationship       `json:"droplet"`
 	PreviousDroplet Relationship       `json:"previous_droplet"`
 	NewProcesses    []ProcessReference `json:"new_processes"`
@@ -17,9 +40,33 @@ type DeploymentCreate struct {
 	Droplet       *Relationship       `json:"droplet,omitempty"`
 	Revision      *DeploymentRevision `json:"revision,omitempty"`
 	Strategy      string              `json:"strategy,omitempty"`
+	Options       *DeploymentOptions  `json:"options,omitempty"`
 	Metadata      *Metadata           `json:"metadata,omitempty"`
 }
 
+type DeploymentOptions struct {
+	// the maximum number of new instances to start at the same time
+	MaxInFlight int `json:"max_in_flight,omitempty"`
+
+	// overrides of the web process, the web process' current values are used when not set
+	WebInstances                 *int `json:"web_instances,omitempty"`
+	MemoryInMB                   *int `json:"memory_in_mb,omitempty"`
+	DiskInMB                     *int `json:"disk_in_mb,omitempty"`
+	LogRateLimitInBytesPerSecond *int `json:"log_rate_limit_in_bytes_per_second,omitempty"`
+
+	// the canary steps, only valid with the canary strategy
+	Canary *DeploymentCanaryOptions `json:"canary,omitempty"`
+}
+
+type DeploymentCanaryOptions struct {
+	Steps []DeploymentCanaryStep `json:"steps,omitempty"`
+}
+
+type DeploymentCanaryStep struct {
+	// the percentage of instances running the new droplet at this step
+	InstanceWeight int `json:"instance_weight"`
+}
+
 type DeploymentUpdate struct {
 	Metadata *Metadata `json:"metadata"`
 }
@@ -40,9 +87,43 @@ type ProcessReference struct {
 }
 
 type DeploymentStatus struct {
-	Value   string            `json:"value"`
-	Reason  string            `json:"reason"`
-	Details map[string]string `json:"details"`
+	Value   string                  `json:"value"`
+	Reason  string                  `json:"reason"`
+	Details map[string]string       `json:"details"`
+	Canary  *DeploymentCanaryStatus `json:"canary,omitempty"`
+}
+
+type DeploymentCanaryStatus struct {
+	Steps DeploymentCanarySteps `json:"steps"`
+}
+
+type DeploymentCanarySteps struct {
+	Current int `json:"current"`
+	Total   int `json:"total"`
+}
+
+// LastSuccessfulHealthcheck returns when the new instances last passed their health check, false if the
+// deployment doesn't report it
+func (s DeploymentStatus) LastSuccessfulHealthcheck() (time.Time, bool) {
+	return s.detailTime("last_successful_healthcheck")
+}
+
+// LastStatusChange returns when the deployment status last changed, false if the deployment doesn't report it
+func (s DeploymentStatus) LastStatusChange() (time.Time, bool) {
+	return s.detailTime("last_status_change")
+}
+
+// ErrorDetail returns why the deployment is degenerate, empty if it isn't
+func (s DeploymentStatus) ErrorDetail() string {
+	return s.Details["error"]
+}
+
+func (s DeploymentStatus) detailTime(key string) (time.Time, bool) {
+	t, err := time.Parse(time.RFC3339, s.Details[key])
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t, true
 }
 
 func NewDeploymentCreate(appGUID string) *DeploymentCreate {
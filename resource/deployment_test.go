@@ -0,0 +1,30 @@
+package resource
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeploymentStatusDetails(t *testing.T) {
+	var s DeploymentStatus
+	err := json.Unmarshal([]byte(`{
+		"value": "FINALIZED",
+		"reason": "DEGENERATE",
+		"details": {
+			"last_successful_healthcheck": "2018-04-25T22:42:10Z",
+			"error": "the app was deleted"
+		}
+	}`), &s)
+	require.NoError(t, err)
+
+	healthcheck, ok := s.LastSuccessfulHealthcheck()
+	require.True(t, ok)
+	require.Equal(t, time.Date(2018, 4, 25, 22, 42, 10, 0, time.UTC), healthcheck.UTC())
+	_, ok = s.LastStatusChange()
+	require.False(t, ok)
+	require.Equal(t, "the app was deleted", s.ErrorDetail())
+	require.Empty(t, DeploymentStatus{}.ErrorDetail())
+}
@@ -0,0 +1,105 @@
+package resource
+
+import (
+	"time"
+)
+
+type LogEnvelopeType string
+
+// The Loggregator v2 envelope types
+const (
+	LogEnvelopeTypeLog     LogEnvelopeType = "LOG"
+	LogEnvelopeTypeCounter LogEnvelopeType = "COUNTER"
+	LogEnvelopeTypeGauge   LogEnvelopeType = "GAUGE"
+	LogEnvelopeTypeTimer   LogEnvelopeType = "TIMER"
+	LogEnvelopeTypeEvent   LogEnvelopeType = "EVENT"
+)
+
+func (l LogEnvelopeType) String() string {
+	return string(l)
+}
+
+type LogType string
+
+// The two log output streams
+const (
+	LogTypeOut LogType = "OUT"
+	LogTypeErr LogType = "ERR"
+)
+
+// LogEnvelope is a Loggregator v2 envelope which holds exactly one of
+// a log, counter, gauge, timer or event.
+type LogEnvelope struct {
+	Timestamp      int64             `json:"timestamp,string"` // nanoseconds since the unix epoch
+	SourceID       string            `json:"source_id"`
+	InstanceID     string            `json:"instance_id"`
+	DeprecatedTags map[string]any    `json:"deprecated_tags,omitempty"`
+	Tags           map[string]string `json:"tags,omitempty"`
+
+	Log     *LogEnvelopeLog     `json:"log,omitempty"`
+	Counter *LogEnvelopeCounter `json:"counter,omitempty"`
+	Gauge   *LogEnvelopeGauge   `json:"gauge,omitempty"`
+	Timer   *LogEnvelopeTimer   `json:"timer,omitempty"`
+	Event   *LogEnvelopeEvent   `json:"event,omitempty"`
+}
+
+type LogEnvelopeLog struct {
+	Payload []byte  `json:"payload"` // base64 encoded on the wire
+	Type    LogType `json:"type"`
+}
+
+type LogEnvelopeCounter struct {
+	Name  string `json:"name"`
+	Delta uint64 `json:"delta,string,omitempty"`
+	Total uint64 `json:"total,string,omitempty"`
+}
+
+type LogEnvelopeGauge struct {
+	Metrics map[string]LogEnvelopeGaugeValue `json:"metrics"`
+}
+
+type LogEnvelopeGaugeValue struct {
+	Unit  string  `json:"unit"`
+	Value float64 `json:"value"`
+}
+
+type LogEnvelopeTimer struct {
+	Name  string `json:"name"`
+	Start int64  `json:"start,string"`
+	Stop  int64  `json:"stop,string"`
+}
+
+type LogEnvelopeEvent struct {
+	Title string `json:"title"`
+	Body  string `json:"body"`
+}
+
+type LogEnvelopeBatch struct {
+	Batch []*LogEnvelope `json:"batch"`
+}
+
+type LogCacheRead struct {
+	Envelopes LogEnvelopeBatch `json:"envelopes"`
+}
+
+// Time returns the envelope timestamp
+func (e *LogEnvelope) Time() time.Time {
+	return time.Unix(0, e.Timestamp)
+}
+
+// Type returns the type of the message held by the envelope
+func (e *LogEnvelope) Type() LogEnvelopeType {
+	switch {
+	case e.Log != nil:
+		return LogEnvelopeTypeLog
+	case e.Counter != nil:
+		return LogEnvelopeTypeCounter
+	case e.Gauge != nil:
+		return LogEnvelopeTypeGauge
+	case e.Timer != nil:
+		return LogEnvelopeTypeTimer
+	case e.Event != nil:
+		return LogEnvelopeTypeEvent
+	}
+	return ""
+}
@@ -4,9 +4,10 @@ type ManifestDiff struct {
 	Diff []ManifestDiffItem `json:"diff"`
 }
 
+// ManifestDiffItem is a single JSON patch style change to a space's manifest
 type ManifestDiffItem struct {
-	Op    string `json:"op"`
-	Path  string `json:"path"`
-	Was   string `json:"was,omitempty"`
-	Value string `json:"value,omitempty"`
+	Op    string `json:"op"`              // add, remove or replace
+	Path  string `json:"path"`            // the JSON pointer to the changed manifest field
+	Was   any    `json:"was,omitempty"`   // the current value, which may be any JSON type
+	Value any    `json:"value,omitempty"` // the new value, which may be any JSON type
 }
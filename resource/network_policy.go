@@ -0,0 +1,55 @@
+package resource
+
+type NetworkPolicyProtocol string
+
+const (
+	NetworkPolicyProtocolTCP NetworkPolicyProtocol = "tcp"
+	NetworkPolicyProtocolUDP NetworkPolicyProtocol = "udp"
+)
+
+// NetworkPolicy allows direct container-to-container traffic from the source app
+// to the destination app on the specified protocol and port range
+type NetworkPolicy struct {
+	Source      NetworkPolicySource      `json:"source"`
+	Destination NetworkPolicyDestination `json:"destination"`
+}
+
+type NetworkPolicySource struct {
+	ID string `json:"id"` // the source app GUID
+}
+
+type NetworkPolicyDestination struct {
+	ID       string                `json:"id"` // the destination app GUID
+	Protocol NetworkPolicyProtocol `json:"protocol"`
+	Ports    NetworkPolicyPorts    `json:"ports"`
+}
+
+type NetworkPolicyPorts struct {
+	Start int `json:"start"`
+	End   int `json:"end"`
+}
+
+type NetworkPolicies struct {
+	Policies []*NetworkPolicy `json:"policies"`
+}
+
+type NetworkPolicyList struct {
+	TotalPolicies int              `json:"total_policies"`
+	Policies      []*NetworkPolicy `json:"policies"`
+}
+
+func NewNetworkPolicy(sourceAppGUID, destinationAppGUID string, protocol NetworkPolicyProtocol, startPort, endPort int) *NetworkPolicy {
+	return &NetworkPolicy{
+		Source: NetworkPolicySource{
+			ID: sourceAppGUID,
+		},
+		Destination: NetworkPolicyDestination{
+			ID:       destinationAppGUID,
+			Protocol: protocol,
+			Ports: NetworkPolicyPorts{
+				Start: startPort,
+				End:   endPort,
+			},
+		},
+	}
+}
@@ -0,0 +1,104 @@
+package resource
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type RouterGroupType string
+
+const (
+	RouterGroupTypeHTTP RouterGroupType = "http"
+	RouterGroupTypeTCP  RouterGroupType = "tcp"
+)
+
+// RouterGroup is a group of routers sharing the same reservable ports, TCP domains are
+// associated with a router group
+type RouterGroup struct {
+	GUID            string          `json:"guid"`
+	Name            string          `json:"name"`
+	Type            RouterGroupType `json:"type"`
+	ReservablePorts string          `json:"reservable_ports"` // comma separated ports and port ranges, e.g. 1024-1033,2000
+}
+
+type RouterGroupUpdate struct {
+	ReservablePorts string `json:"reservable_ports"`
+}
+
+// TCPRoute maps a router group port to a backend ip and port
+type TCPRoute struct {
+	RouterGroupGUID  string              `json:"router_group_guid"`
+	Port             int                 `json:"port"`
+	BackendIP        string              `json:"backend_ip"`
+	BackendPort      int                 `json:"backend_port"`
+	BackendTLSPort   *int                `json:"backend_tls_port,omitempty"`
+	InstanceID       string              `json:"instance_id,omitempty"`
+	SNIHostname      *string             `json:"backend_sni_hostname,omitempty"`
+	IsolationSegment string              `json:"isolation_segment,omitempty"`
+	TTL              *int                `json:"ttl,omitempty"`
+	ModificationTag  *TCPModificationTag `json:"modification_tag,omitempty"`
+}
+
+type TCPModificationTag struct {
+	GUID  string `json:"guid"`
+	Index int    `json:"index"`
+}
+
+// PortRange is an inclusive range of ports
+type PortRange struct {
+	Start int
+	End   int
+}
+
+// Contains returns true if the port is within the range
+func (r PortRange) Contains(port int) bool {
+	return port >= r.Start && port <= r.End
+}
+
+// PortRanges parses the reservable ports of the router group
+func (g *RouterGroup) PortRanges() ([]PortRange, error) {
+	var ranges []PortRange
+	for _, p := range strings.Split(g.ReservablePorts, ",") {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			continue
+		}
+		start, end, isRange := strings.Cut(p, "-")
+		startPort, err := strconv.Atoi(strings.TrimSpace(start))
+		if err != nil {
+			return nil, fmt.Errorf("invalid reservable port %q in router group %s: %w", p, g.Name, err)
+		}
+		endPort := startPort
+		if isRange {
+			if endPort, err = strconv.Atoi(strings.TrimSpace(end)); err != nil {
+				return nil, fmt.Errorf("invalid reservable port range %q in router group %s: %w", p, g.Name, err)
+			}
+		}
+		ranges = append(ranges, PortRange{Start: startPort, End: endPort})
+	}
+	return ranges, nil
+}
+
+// IsPortReservable returns true if the port is within the router group's reservable ports
+func (g *RouterGroup) IsPortReservable(port int) (bool, error) {
+	ranges, err := g.PortRanges()
+	if err != nil {
+		return false, err
+	}
+	for _, r := range ranges {
+		if r.Contains(port) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func NewTCPRoute(routerGroupGUID string, port int, backendIP string, backendPort int) *TCPRoute {
+	return &TCPRoute{
+		RouterGroupGUID: routerGroupGUID,
+		Port:            port,
+		BackendIP:       backendIP,
+		BackendPort:     backendPort,
+	}
+}
@@ -0,0 +1,26 @@
+package resource
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRouterGroupPortRanges(t *testing.T) {
+	g := &RouterGroup{Name: "default-tcp", ReservablePorts: "1024-1033, 2000"}
+	ranges, err := g.PortRanges()
+	require.NoError(t, err)
+	require.Equal(t, []PortRange{{Start: 1024, End: 1033}, {Start: 2000, End: 2000}}, ranges)
+
+	ok, err := g.IsPortReservable(1033)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	ok, err = g.IsPortReservable(1034)
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	g.ReservablePorts = "1024-abc"
+	_, err = g.PortRanges()
+	require.Error(t, err)
+}
@@ -76,6 +76,14 @@ type NullableRelationship struct {
 	GUID *string `json:"guid"`
 }
 
+// The states of the last asynchronous service broker operation
+const (
+	LastOperationInitial    = "initial"
+	LastOperationInProgress = "in progress"
+	LastOperationSucceeded  = "succeeded"
+	LastOperationFailed     = "failed"
+)
+
 type LastOperation struct {
 	Type        string    `json:"type"`
 	State       string    `json:"state"`
@@ -0,0 +1,133 @@
+package resource
+
+import (
+	"time"
+)
+
+const (
+	UAAOriginUAA            = "uaa"
+	UAAGroupMemberTypeUser  = "USER"
+	UAAGroupMemberTypeGroup = "GROUP"
+)
+
+// UAAUser is a SCIM user identity in UAA
+type UAAUser struct {
+	ID                   string         `json:"id"`
+	ExternalID           string         `json:"externalId,omitempty"`
+	UserName             string         `json:"userName"`
+	Name                 *UAAUserName   `json:"name,omitempty"`
+	Emails               []UAAUserEmail `json:"emails,omitempty"`
+	Groups               []UAAUserGroup `json:"groups,omitempty"`
+	Active               bool           `json:"active"`
+	Verified             bool           `json:"verified"`
+	Origin               string         `json:"origin"`
+	ZoneID               string         `json:"zoneId,omitempty"`
+	PasswordLastModified *time.Time     `json:"passwordLastModified,omitempty"`
+	Meta                 *UAAMeta       `json:"meta,omitempty"`
+	Schemas              []string       `json:"schemas,omitempty"`
+}
+
+type UAAUserCreate struct {
+	UserName   string         `json:"userName"`
+	Password   string         `json:"password,omitempty"`
+	Name       *UAAUserName   `json:"name,omitempty"`
+	Emails     []UAAUserEmail `json:"emails,omitempty"`
+	Origin     string         `json:"origin,omitempty"`
+	ExternalID string         `json:"externalId,omitempty"`
+	Active     bool           `json:"active"`
+	Verified   bool           `json:"verified"`
+}
+
+type UAAUserName struct {
+	GivenName  string `json:"givenName,omitempty"`
+	FamilyName string `json:"familyName,omitempty"`
+}
+
+type UAAUserEmail struct {
+	Value   string `json:"value"`
+	Primary bool   `json:"primary"`
+}
+
+type UAAUserGroup struct {
+	Value   string `json:"value"`
+	Display string `json:"display"`
+	Type    string `json:"type"`
+}
+
+type UAAPasswordChange struct {
+	OldPassword string `json:"oldPassword,omitempty"`
+	Password    string `json:"password"`
+}
+
+// UAAGroup is a SCIM group in UAA, group names are the OAuth scopes granted to the members
+type UAAGroup struct {
+	ID          string           `json:"id"`
+	DisplayName string           `json:"displayName"`
+	Description string           `json:"description,omitempty"`
+	Members     []UAAGroupMember `json:"members,omitempty"`
+	ZoneID      string           `json:"zoneId,omitempty"`
+	Meta        *UAAMeta         `json:"meta,omitempty"`
+	Schemas     []string         `json:"schemas,omitempty"`
+}
+
+type UAAGroupMember struct {
+	Origin string `json:"origin"`
+	Type   string `json:"type"`
+	Value  string `json:"value"` // the member's user or group ID
+}
+
+// UAAOAuthClient is an OAuth client registered in UAA
+type UAAOAuthClient struct {
+	ClientID             string   `json:"client_id"`
+	ClientSecret         string   `json:"client_secret,omitempty"`
+	Name                 string   `json:"name,omitempty"`
+	Scope                []string `json:"scope,omitempty"`
+	ResourceIDs          []string `json:"resource_ids,omitempty"`
+	AuthorizedGrantTypes []string `json:"authorized_grant_types,omitempty"`
+	RedirectURI          []string `json:"redirect_uri,omitempty"`
+	Authorities          []string `json:"authorities,omitempty"`
+	AutoApprove          any      `json:"autoapprove,omitempty"` // true or a list of auto approved scopes
+	AllowedProviders     []string `json:"allowedproviders,omitempty"`
+	AccessTokenValidity  int      `json:"access_token_validity,omitempty"`
+	RefreshTokenValidity int      `json:"refresh_token_validity,omitempty"`
+	LastModified         int64    `json:"lastModified,omitempty"`
+}
+
+type UAAMeta struct {
+	Version      int       `json:"version"`
+	Created      time.Time `json:"created"`
+	LastModified time.Time `json:"lastModified"`
+}
+
+// UAAList is a page of SCIM resources
+type UAAList[T any] struct {
+	Resources    []T      `json:"resources"`
+	StartIndex   int      `json:"startIndex"`
+	ItemsPerPage int      `json:"itemsPerPage"`
+	TotalResults int      `json:"totalResults"`
+	Schemas      []string `json:"schemas"`
+}
+
+func NewUAAUserCreate(userName, password, email string) *UAAUserCreate {
+	return &UAAUserCreate{
+		UserName: userName,
+		Password: password,
+		Emails: []UAAUserEmail{
+			{
+				Value:   email,
+				Primary: true,
+			},
+		},
+		Origin:   UAAOriginUAA,
+		Active:   true,
+		Verified: true,
+	}
+}
+
+func NewUAAGroupUserMember(userID, origin string) *UAAGroupMember {
+	return &UAAGroupMember{
+		Origin: origin,
+		Type:   UAAGroupMemberTypeUser,
+		Value:  userID,
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"net/url"
 	"strconv"
 	"strings"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -15,12 +16,26 @@ import (
 	"github.com/martini-contrib/render"
 )
 
+const (
+	defaultAppSSHEndpoint           = "ssh.example.org:2222"
+	defaultAppSSHHostKeyFingerprint = "Y411oivJwZCUQnXHq83mdM5SKCK4ftyoSXI31RRe4Zs"
+)
+
 var (
 	mux           *http.ServeMux
 	server        *httptest.Server
 	fakeUAAServer *httptest.Server
+
+	appSSHEndpoint           = defaultAppSSHEndpoint
+	appSSHHostKeyFingerprint = defaultAppSSHHostKeyFingerprint
 )
 
+// SetAppSSHProxy changes the SSH proxy linked from the fake API root until Teardown
+func SetAppSSHProxy(endpoint, hostKeyFingerprint string) {
+	appSSHEndpoint = endpoint
+	appSSHHostKeyFingerprint = hostKeyFingerprint
+}
+
 type MockRoute struct {
 	Method           string
 	Endpoint         string
@@ -30,6 +45,7 @@ type MockRoute struct {
 	Statuses         []int
 	QueryString      string
 	PostForm         string
+	PostForms        []string // POST-FILE only, a substring of each request body when it differs per call
 	RedirectLocation string
 }
 
@@ -58,7 +74,13 @@ func SetupFakeUAAServer(expiresIn int) string {
 		})
 		count = count + 1
 	})
-	r.NotFound(func() string { return "" })
+	// any other UAA endpoints are served by the mock routes registered on the API server
+	r.NotFound(func(res http.ResponseWriter, req *http.Request) {
+		if mux == nil {
+			return
+		}
+		mux.ServeHTTP(res, req)
+	})
 	m.Action(r.Handle)
 	uaaMux.Handle("/", m)
 	return fakeUAAServer.URL
@@ -73,6 +95,13 @@ func SetupMultiple(mockEndpoints []MockRoute, t *testing.T) string {
 		SetupFakeAPIServer()
 	}
 	m := martini.New()
+	// the routes count their calls, so serve concurrent requests one at a time
+	var mu sync.Mutex
+	m.Use(func(c martini.Context) {
+		mu.Lock()
+		defer mu.Unlock()
+		c.Next()
+	})
 	m.Use(render.Renderer())
 	r := martini.NewRouter()
 	for _, mock := range mockEndpoints {
@@ -83,6 +112,7 @@ func SetupMultiple(mockEndpoints []MockRoute, t *testing.T) string {
 		statuses := mock.Statuses
 		queryString := mock.QueryString
 		postFormBody := mock.PostForm
+		postFormBodies := mock.PostForms
 		redirectLocation := mock.RedirectLocation
 
 		// TODO: add support for other HTTP verbs
@@ -169,6 +199,23 @@ func SetupMultiple(mockEndpoints []MockRoute, t *testing.T) string {
 				count++
 				return status, singleOutput
 			})
+		case "POST-FILE":
+			count := 0
+			r.Post(endpoint, func(res http.ResponseWriter, req *http.Request) (int, string) {
+				testUserAgent(req.Header.Get("User-Agent"), userAgent, t)
+				if count < len(postFormBodies) {
+					testBodyContains(req, postFormBodies[count], t)
+				} else {
+					testBodyContains(req, postFormBody, t)
+				}
+				if redirectLocation != "" {
+					res.Header().Add("Location", redirectLocation)
+				}
+				singleOutput := output[count]
+				status = statuses[count]
+				count++
+				return status, singleOutput
+			})
 		case "PUT-FILE":
 			count := 0
 			r.Put(endpoint, func(res http.ResponseWriter, req *http.Request) (int, string) {
@@ -203,7 +250,7 @@ func SetupMultiple(mockEndpoints []MockRoute, t *testing.T) string {
 					"href": "https://api.example.org/networking/v0/external",
 				},
 				"network_policy_v1": map[string]any{
-					"href": "https://api.example.org/networking/v1/external",
+					"href": server.URL + "/networking/v1/external",
 				},
 				"uaa": map[string]any{
 					"href": fakeUAAServer.URL,
@@ -212,24 +259,24 @@ func SetupMultiple(mockEndpoints []MockRoute, t *testing.T) string {
 					"href": fakeUAAServer.URL,
 				},
 				"credhub": map[string]any{
-					"href": "",
+					"href": server.URL + "/credhub",
 				},
 				"routing": map[string]any{
-					"href": "https://api.example.org/routing",
+					"href": server.URL + "/routing",
 				},
 				"logging": map[string]any{
 					"href": "wss://doppler.example.org:443",
 				},
 				"log_cache": map[string]any{
-					"href": "https://log-cache.example.org",
+					"href": server.URL,
 				},
 				"log_stream": map[string]any{
-					"href": "https://log-stream.example.org",
+					"href": server.URL,
 				},
 				"app_ssh": map[string]any{
-					"href": "ssh.example.org:2222",
+					"href": appSSHEndpoint,
 					"meta": map[string]any{
-						"host_key_fingerprint": "Y411oivJwZCUQnXHq83mdM5SKCK4ftyoSXI31RRe4Zs",
+						"host_key_fingerprint": appSSHHostKeyFingerprint,
 						"oauth_client":         "ssh-proxy",
 					},
 				},
@@ -252,6 +299,7 @@ func Teardown() {
 		fakeUAAServer.Close()
 		fakeUAAServer = nil
 	}
+	SetAppSSHProxy(defaultAppSSHEndpoint, defaultAppSSHHostKeyFingerprint)
 }
 
 func testQueryString(QueryString string, QueryStringExp string, t *testing.T) {
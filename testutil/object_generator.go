@@ -8,6 +8,7 @@ import (
 	"os"
 	"path"
 	"runtime"
+	"strconv"
 	"strings"
 	"text/template"
 )
@@ -166,9 +167,36 @@ func (o ObjectJSONGenerator) DropletAssociation() *JSONResource {
 	return o.renderTemplate(r, "droplet_association.json")
 }
 
+func (o ObjectJSONGenerator) CredHubCredential() *JSONResource {
+	r := &JSONResource{
+		GUID: RandomGUID(),
+		Name: "/c/p-broker/" + RandomGUID() + "/credentials",
+	}
+	return o.renderTemplate(r, "credhub_credential.json")
+}
+
+func (o ObjectJSONGenerator) CredHubPermission() *JSONResource {
+	r := &JSONResource{
+		GUID: RandomGUID(),
+		Name: "/c/p-broker/*",
+		Params: map[string]string{
+			"app": RandomGUID(),
+		},
+	}
+	return o.renderTemplate(r, "credhub_permission.json")
+}
+
 func (o ObjectJSONGenerator) Deployment() *JSONResource {
+	return o.DeploymentWithStatus("ACTIVE", "DEPLOYING")
+}
+
+func (o ObjectJSONGenerator) DeploymentWithStatus(value, reason string) *JSONResource {
 	r := &JSONResource{
 		GUID: RandomGUID(),
+		Params: map[string]string{
+			"value":  value,
+			"reason": reason,
+		},
 	}
 	return o.renderTemplate(r, "deployment.json")
 }
@@ -221,6 +249,20 @@ func (o ObjectJSONGenerator) Job(state string) *JSONResource {
 	return o.renderTemplate(r, "job.json")
 }
 
+func (o ObjectJSONGenerator) LogEnvelope(appGUID, timestamp string) *JSONResource {
+	r := &JSONResource{
+		GUID: appGUID,
+		Params: map[string]string{
+			"timestamp": timestamp,
+		},
+	}
+	return o.renderTemplate(r, "log_envelope.json")
+}
+
+func (o ObjectJSONGenerator) LogEnvelopeBatch(envelopesJSON ...string) string {
+	return `{"envelopes":{"batch":` + o.Array(envelopesJSON...) + `}}`
+}
+
 func (o ObjectJSONGenerator) Manifest() *JSONResource {
 	r := &JSONResource{}
 	return o.renderTemplate(r, "manifest.yml")
@@ -228,7 +270,71 @@ func (o ObjectJSONGenerator) Manifest() *JSONResource {
 
 func (o ObjectJSONGenerator) ManifestDiff() *JSONResource {
 	r := &JSONResource{}
-	return o.renderTemplate(r, "manifest_diff.yml")
+	return o.renderTemplate(r, "manifest_diff.json")
+}
+
+func (o ObjectJSONGenerator) NetworkPolicy(sourceAppGUID, destinationAppGUID string) *JSONResource {
+	r := &JSONResource{
+		Params: map[string]string{
+			"source":      sourceAppGUID,
+			"destination": destinationAppGUID,
+		},
+	}
+	return o.renderTemplate(r, "network_policy.json")
+}
+
+func (o ObjectJSONGenerator) NetworkPolicies(policiesJSON ...string) string {
+	return fmt.Sprintf(`{"total_policies":%d,"policies":%s}`, len(policiesJSON), o.Array(policiesJSON...))
+}
+
+func (o ObjectJSONGenerator) RouterGroup() *JSONResource {
+	r := &JSONResource{
+		GUID: RandomGUID(),
+		Name: RandomName(),
+	}
+	return o.renderTemplate(r, "router_group.json")
+}
+
+func (o ObjectJSONGenerator) TCPRoute(routerGroupGUID string, port int) *JSONResource {
+	r := &JSONResource{
+		GUID: routerGroupGUID,
+		Params: map[string]string{
+			"port": strconv.Itoa(port),
+		},
+	}
+	return o.renderTemplate(r, "tcp_route.json")
+}
+
+func (o ObjectJSONGenerator) UAAUser() *JSONResource {
+	r := &JSONResource{
+		GUID: RandomGUID(),
+		Name: RandomName(),
+	}
+	return o.renderTemplate(r, "uaa_user.json")
+}
+
+func (o ObjectJSONGenerator) UAAGroup(memberID string) *JSONResource {
+	r := &JSONResource{
+		GUID: RandomGUID(),
+		Name: RandomName(),
+		Params: map[string]string{
+			"member": memberID,
+		},
+	}
+	return o.renderTemplate(r, "uaa_group.json")
+}
+
+func (o ObjectJSONGenerator) UAAOAuthClient() *JSONResource {
+	r := &JSONResource{
+		Name: RandomName(),
+	}
+	return o.renderTemplate(r, "uaa_oauth_client.json")
+}
+
+// UAAList wraps the resources in a SCIM list page
+func (o ObjectJSONGenerator) UAAList(startIndex, totalResults int, resourcesJSON ...string) string {
+	return fmt.Sprintf(`{"resources":%s,"startIndex":%d,"itemsPerPage":%d,"totalResults":%d,"schemas":["urn:scim:schemas:core:1.0"]}`,
+		o.Array(resourcesJSON...), startIndex, len(resourcesJSON), totalResults)
 }
 
 func (o ObjectJSONGenerator) Organization() *JSONResource {